@@ -0,0 +1,72 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+//ErrOnlyAdaptiveFormats : The video has no muxed format, its separate video and
+//audio formats need ffmpeg to be merged. Match it with errors.Is.
+var ErrOnlyAdaptiveFormats = errors.New("no muxed format, only adaptive ones")
+
+//adaptiveSelector merges the best adaptive formats of the videos without muxed ones.
+const adaptiveSelector = "bestvideo+bestaudio/bestvideo/bestaudio"
+
+//AdaptiveOnlyError : The video has only adaptive formats and ffmpeg wasn't found,
+//download one of the Itags with FormatSelector or install ffmpeg.
+type AdaptiveOnlyError struct {
+	Itags []int
+}
+
+func (e *AdaptiveOnlyError) Error() string {
+	itags := make([]string, len(e.Itags))
+	for i, itag := range e.Itags {
+		itags[i] = fmt.Sprint(itag)
+	}
+	return fmt.Sprintf("%s, itags %s, merging them needs ffmpeg", ErrOnlyAdaptiveFormats, strings.Join(itags, ","))
+}
+
+//Is : Match ErrOnlyAdaptiveFormats.
+func (e *AdaptiveOnlyError) Is(target error) bool {
+	return target == ErrOnlyAdaptiveFormats
+}
+
+//adaptiveItags lists the itags of the video only and audio only formats.
+func (y *Youtube) adaptiveItags() []int {
+	var itags []int
+	for _, f := range y.Formats {
+		if f.HasVideo != f.HasAudio {
+			itags = append(itags, f.Itag)
+		}
+	}
+	return itags
+}
+
+//onlyAdaptive tells if the video has no muxed stream but adaptive formats.
+func (y *Youtube) onlyAdaptive() bool {
+	return len(y.StreamList) == 0 && len(y.adaptiveItags()) > 0
+}
+
+//ffmpegAvailable tells if FFmpegPath, or ffmpeg in PATH, can be run.
+func (y *Youtube) ffmpegAvailable() bool {
+	ffmpeg := y.FFmpegPath
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	_, err := exec.LookPath(ffmpeg)
+	return err == nil
+}
+
+//downloadAdaptiveOnly merges the best adaptive formats when ffmpeg is available,
+//instead of the muxed stream the video doesn't have.
+func (y *Youtube) downloadAdaptiveOnly(destFile string) error {
+	if !y.ffmpegAvailable() {
+		return &AdaptiveOnlyError{Itags: y.adaptiveItags()}
+	}
+	y.log("No muxed stream, merge the best adaptive formats")
+	y.FormatSelector = adaptiveSelector
+	defer func() { y.FormatSelector = "" }()
+	return y.downloadSelected(destFile)
+}
@@ -0,0 +1,29 @@
+package youtube
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestOnlyAdaptiveFormats(t *testing.T) {
+	v := url.Values{"status": {"ok"}, "adaptive_fmts": {strings.Join([]string{
+		url.Values{"itag": {"137"}, "type": {"video/mp4"}, "size": {"1920x1080"}, "url": {"https://googlevideo.com/137"}}.Encode(),
+		url.Values{"itag": {"140"}, "type": {"audio/mp4"}, "url": {"https://googlevideo.com/140"}}.Encode(),
+	}, ",")}}
+	y := NewYoutube(false)
+	y.videoInfo = v.Encode()
+	if err := y.parseVideoInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if !y.onlyAdaptive() || !y.needsLocalFiles() {
+		t.Error("The video should have only adaptive formats")
+	}
+
+	y.FFmpegPath = "/nonexistent/ffmpeg"
+	err := y.StartDownload(t.TempDir() + "/dl.mp4")
+	if !errors.Is(err, ErrOnlyAdaptiveFormats) || !strings.Contains(err.Error(), "137,140") {
+		t.Errorf("got error %v", err)
+	}
+}
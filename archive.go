@@ -0,0 +1,67 @@
+package youtube
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+//ErrAlreadyDownloaded : Returned by StartDownload when the video is in the archive.
+var ErrAlreadyDownloaded = errors.New("video already recorded in the download archive")
+
+//Archive : Record of downloaded video ids, in the same format as youtube-dl --download-archive.
+type Archive struct {
+	path string
+	mu   sync.Mutex
+	ids  map[string]bool
+}
+
+//OpenArchive : Load the archive file, a missing file is an empty archive.
+func OpenArchive(path string) (*Archive, error) {
+	a := &Archive{path: path, ids: make(map[string]bool)}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "youtube" {
+			a.ids[fields[1]] = true
+		}
+	}
+	return a, scanner.Err()
+}
+
+//Contains : Check if the video id was downloaded.
+func (a *Archive) Contains(videoID string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.ids[videoID]
+}
+
+//Add : Record the video id and append it to the archive file.
+func (a *Archive) Add(videoID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ids[videoID] {
+		return nil
+	}
+	f, err := os.OpenFile(a.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err = fmt.Fprintf(f, "youtube %s\n", videoID); err != nil {
+		return err
+	}
+	a.ids[videoID] = true
+	return nil
+}
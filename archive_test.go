@@ -0,0 +1,41 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.txt")
+	a, err := OpenArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Contains("rFejpH_tAHM") {
+		t.Error("Empty archive should not contain the video")
+	}
+	if err := a.Add("rFejpH_tAHM"); err != nil {
+		t.Fatal(err)
+	}
+	a.Add("rFejpH_tAHM")
+
+	a, err = OpenArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Contains("rFejpH_tAHM") {
+		t.Error("Reloaded archive should contain the video")
+	}
+	b, _ := ioutil.ReadFile(path)
+	if string(b) != "youtube rFejpH_tAHM\n" {
+		t.Errorf("Wrong archive content: %q", b)
+	}
+
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.Archive = a
+	if err := y.StartDownload(filepath.Join(t.TempDir(), "dl.mp4")); err != ErrAlreadyDownloaded {
+		t.Errorf("Archived video should be skipped, err=%v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package youtube
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+//accented and plain are the latin letters with diacritics and their base letter.
+const (
+	accented = "ÀÁÂÃÄÅÇÈÉÊËÌÍÎÏÑÒÓÔÕÖÙÚÛÜÝàáâãäåçèéêëìíîïñòóôõöùúûüýÿĀāĂăĄąĆćĈĉĊċČčĎďĒēĔĕĖėĘęĚěĜĝĞğĠġĢģĤĥĨĩĪīĬĭĮįİĲĳĴĵĶķĹĺĻļĽľĿŀŃńŅņŇňŌōŎŏŐőŔŕŖŗŘřŚśŜŝŞşŠšŢţŤťŨũŪūŬŭŮůŰűŲųŴŵŶŷŸŹźŻżŽžſ"
+	plain    = "AAAAAACEEEEIIIINOOOOOUUUUYaaaaaaceeeeiiiinooooouuuuyyAaAaAaCcCcCcCcDdEeEeEeEeEeGgGgGgGgHhIiIiIiIiIIiJjKkLlLlLlLlNnNnNnOoOoOoRrRrRrSsSsSsSsTtTtUuUuUuUuUuUuWwYyYZzZzZzs"
+)
+
+//transliterations are the letters and punctuation that have no single base letter.
+var transliterations = map[rune]string{
+	'Æ': "AE", 'æ': "ae", 'Œ': "OE", 'œ': "oe", 'Ĳ': "IJ", 'ĳ': "ij",
+	'ß': "ss", 'Þ': "TH", 'þ': "th", 'Ð': "D", 'ð': "d", 'Đ': "D", 'đ': "d",
+	'Ø': "O", 'ø': "o", 'Ł': "L", 'ł': "l", 'Ħ': "H", 'ħ': "h", 'ı': "i",
+	'‘': "'", '’': "'", '“': "'", '”': "'", '«': "'", '»': "'",
+	'–': "-", '—': "-", '…': "...", '×': "x",
+}
+
+func init() {
+	plain := []rune(plain)
+	for i, r := range []rune(accented) {
+		if _, ok := transliterations[r]; !ok {
+			transliterations[r] = string(plain[i])
+		}
+	}
+}
+
+//ASCIIFileName : A file name for the title with only ASCII letters, digits and
+//punctuation, like the --restrict-filenames of youtube-dl: the accented latin
+//letters lose their diacritics, the other characters like emoji or CJK, the
+//spaces and '&' become '_', and SafeFileName applies. It is empty when nothing
+//is left of the title.
+func ASCIIFileName(title string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(title) {
+		switch {
+		case r == ' ', r == '&':
+			b.WriteByte('_')
+		case r < 128:
+			b.WriteRune(r)
+		case transliterations[r] != "":
+			b.WriteString(transliterations[r])
+		default:
+			b.WriteByte('_')
+		}
+	}
+	name := SafeFileName(b.String())
+	for strings.Contains(name, "__") {
+		name = strings.ReplaceAll(name, "__", "_")
+	}
+	return SafeFileName(strings.Trim(name, "_"))
+}
+
+//asciiDest applies ASCIIFileName to the file name of the destination, keeping
+//its directory and extension, the video id replaces a name with no ASCII left.
+func (y *Youtube) asciiDest(destFile string) string {
+	dir, base := filepath.Split(destFile)
+	ext := filepath.Ext(base)
+	name := ASCIIFileName(strings.TrimSuffix(base, ext))
+	if name == "" {
+		name = y.VideoID
+	}
+	return filepath.Join(dir, name+ASCIIFileName(ext))
+}
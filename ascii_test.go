@@ -0,0 +1,35 @@
+package youtube
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestASCIIFileName(t *testing.T) {
+	tests := []struct {
+		title, want string
+	}{
+		{"Crème brûlée", "Creme_brulee"},
+		{"Straße & Œuvre", "Strasse_OEuvre"},
+		{"Rock 🎸 night", "Rock_night"},
+		{"日本語", ""},
+		{"Go: “Simplicity” — Rob Pike", "Go_'Simplicity'_-_Rob_Pike"},
+	}
+	for _, test := range tests {
+		if got := ASCIIFileName(test.title); got != test.want {
+			t.Errorf("ASCIIFileName(%q) = %q, want %q", test.title, got, test.want)
+		}
+	}
+}
+
+func TestASCIIDest(t *testing.T) {
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	dir := filepath.Join("videos", "música")
+	if got, want := y.asciiDest(filepath.Join(dir, "Café.mp4")), filepath.Join(dir, "Cafe.mp4"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := y.asciiDest("日本語.webm"), "rFejpH_tAHM.webm"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package youtube
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//innertubeOrigin is the origin the SAPISIDHASH is computed for.
+const innertubeOrigin = "https://www.youtube.com"
+
+//sapisid is the SAPISID cookie, or its __Secure-3PAPISID copy, of the Cookies.
+func (y *Youtube) sapisid() string {
+	var secure string
+	for _, c := range y.Cookies {
+		switch c.Name {
+		case "SAPISID":
+			return c.Value
+		case "__Secure-3PAPISID":
+			secure = c.Value
+		}
+	}
+	return secure
+}
+
+//sapisidHash is the Authorization header of the signed in innertube requests,
+//the sha1 of the time, the SAPISID cookie and the origin.
+func sapisidHash(sapisid, origin string, now time.Time) string {
+	ts := now.Unix()
+	sum := sha1.Sum([]byte(fmt.Sprintf("%d %s %s", ts, sapisid, origin)))
+	return fmt.Sprintf("SAPISIDHASH %d_%x", ts, sum)
+}
+
+//setAuthorization signs the innertube requests when the Cookies are of a signed
+//in account, the endpoints ignore the cookies without it.
+func (y *Youtube) setAuthorization(req *http.Request) {
+	if !isYoutubeHost(req.URL.Hostname()) || !strings.HasPrefix(req.URL.Path, "/youtubei/") {
+		return
+	}
+	sapisid := y.sapisid()
+	if sapisid == "" || req.Header.Get("Authorization") != "" {
+		return
+	}
+	req.Header.Set("Authorization", sapisidHash(sapisid, innertubeOrigin, time.Now()))
+	req.Header.Set("Origin", innertubeOrigin)
+	req.Header.Set("X-Origin", innertubeOrigin)
+	req.Header.Set("X-Goog-AuthUser", "0")
+}
@@ -0,0 +1,36 @@
+package youtube
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSAPISIDHash(t *testing.T) {
+	//sha1 of "1700000000 abc/def https://www.youtube.com"
+	got := sapisidHash("abc/def", innertubeOrigin, time.Unix(1700000000, 0))
+	if got != "SAPISIDHASH 1700000000_747622f274182ecf105054645d6a0093199ab03d" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestSetAuthorization(t *testing.T) {
+	y := NewYoutube(false)
+	req, _ := http.NewRequest("POST", "https://www.youtube.com/youtubei/v1/browse", nil)
+	y.setAuthorization(req)
+	if req.Header.Get("Authorization") != "" {
+		t.Error("No authorization without cookies")
+	}
+
+	y.Cookies = []*http.Cookie{{Name: "__Secure-3PAPISID", Value: "abc/def", Domain: ".youtube.com"}}
+	y.setAuthorization(req)
+	if auth := req.Header.Get("Authorization"); !strings.HasPrefix(auth, "SAPISIDHASH ") || req.Header.Get("X-Origin") != innertubeOrigin {
+		t.Errorf("Wrong authorization %q", auth)
+	}
+	req, _ = http.NewRequest("GET", "https://www.youtube.com/watch?v=rFejpH_tAHM", nil)
+	y.setAuthorization(req)
+	if req.Header.Get("Authorization") != "" {
+		t.Error("Only the innertube requests are signed")
+	}
+}
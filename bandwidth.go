@@ -0,0 +1,91 @@
+package youtube
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+//ErrBudgetExceeded : The error of the requests, and of the download in progress,
+//once the bytes received reach the Budget of the Bandwidth.
+var ErrBudgetExceeded = errors.New("bandwidth budget exceeded")
+
+//Bandwidth : Counts the bytes of the http bodies received, for metered connections.
+//Share one between Youtube objects, e.g. with Batch, to count their total.
+type Bandwidth struct {
+	//Budget stops the downloads once that many bytes are received, 0 for no limit.
+	Budget int64
+	used   int64
+}
+
+//NewBandwidth : Initialize a counter that stops the downloads after budget bytes, 0 for no limit.
+func NewBandwidth(budget int64) *Bandwidth {
+	return &Bandwidth{Budget: budget}
+}
+
+//Used : The number of bytes received so far.
+func (b *Bandwidth) Used() int64 {
+	return atomic.LoadInt64(&b.used)
+}
+
+//Remaining : The bytes left in the budget, -1 without a budget.
+func (b *Bandwidth) Remaining() int64 {
+	if b.Budget <= 0 {
+		return -1
+	}
+	if left := b.Budget - b.Used(); left > 0 {
+		return left
+	}
+	return 0
+}
+
+//add counts n bytes and tells if the budget is now exceeded.
+func (b *Bandwidth) add(n int) error {
+	used := atomic.AddInt64(&b.used, int64(n))
+	if b.Budget > 0 && used >= b.Budget {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+//BytesReceived : The bytes received by the requests of the Youtube object.
+func (y *Youtube) BytesReceived() int64 {
+	if y.Bandwidth == nil {
+		return 0
+	}
+	return y.Bandwidth.Used()
+}
+
+//checkBudget refuses a new request once the budget is used up.
+func (y *Youtube) checkBudget() error {
+	if y.Bandwidth != nil && y.Bandwidth.Remaining() == 0 {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+//meterResponse counts the body of the response as it is read.
+func (y *Youtube) meterResponse(resp *http.Response) {
+	if y.Bandwidth == nil || resp == nil {
+		return
+	}
+	resp.Body = &meteredBody{ReadCloser: resp.Body, bandwidth: y.Bandwidth}
+}
+
+//meteredBody adds the bytes read to the bandwidth and fails the read that
+//exceeds the budget.
+type meteredBody struct {
+	io.ReadCloser
+	bandwidth *Bandwidth
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if berr := b.bandwidth.add(n); berr != nil && err == nil {
+			err = berr
+		}
+	}
+	return n, err
+}
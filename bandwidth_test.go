@@ -0,0 +1,50 @@
+package youtube
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBandwidthBudget(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 10; i++ {
+			w.Write([]byte(strings.Repeat("v", 1000)))
+			w.(http.Flusher).Flush()
+			time.Sleep(time.Millisecond)
+		}
+	}))
+	defer ts.Close()
+	dir := t.TempDir()
+
+	y := NewYoutube(false)
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	if err := y.StartDownload(filepath.Join(dir, "a.mp4")); err != nil {
+		t.Fatal(err)
+	}
+	if got := y.BytesReceived(); got != 10000 {
+		t.Errorf("received %d bytes, want 10000", got)
+	}
+
+	y.Bandwidth.Budget = 11500
+	if err := y.StartDownload(filepath.Join(dir, "b.mp4")); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("got error %v, want %v", err, ErrBudgetExceeded)
+	}
+	if got := y.Bandwidth.Remaining(); got != 0 {
+		t.Errorf("%d bytes remaining, want 0", got)
+	}
+	used := y.BytesReceived()
+	if used >= 20000 {
+		t.Errorf("received %d bytes, the download should stop at the budget", used)
+	}
+	if err := y.StartDownload(filepath.Join(dir, "c.mp4")); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("got error %v, want %v", err, ErrBudgetExceeded)
+	}
+	if got := y.BytesReceived(); got != used {
+		t.Errorf("received %d bytes after the budget", got-used)
+	}
+}
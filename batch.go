@@ -0,0 +1,324 @@
+package youtube
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+//ErrShutdown : The error of the items added after Shutdown, or interrupted by it.
+var ErrShutdown = errors.New("the batch is shut down")
+
+//ErrCanceled : The error of the items stopped by Cancel.
+var ErrCanceled = errors.New("the download is canceled")
+
+//BatchItem : One download of a batch.
+type BatchItem struct {
+	URL      string
+	DestFile string
+	Err      error
+	//Path and SHA256 of the downloaded file, Path can differ from DestFile
+	//after numbering or remuxing.
+	Path   string
+	SHA256 string
+
+	priority int
+	seq      int
+	index    int
+	done     bool
+	canceled bool
+	cancel   context.CancelFunc
+}
+
+//ID : Identifier of the item, used as DownloadID of its logs and progress events.
+func (i *BatchItem) ID() string {
+	return fmt.Sprintf("batch-%d", i.seq)
+}
+
+//Priority : The current priority of the item, higher is downloaded first.
+func (i *BatchItem) Priority() int {
+	return i.priority
+}
+
+//Batch : Download queue processed by a pool of workers, by priority then insertion order.
+type Batch struct {
+	Workers   int
+	DebugMode bool
+	Archive   *Archive
+	//RateLimiter caps the total bandwidth of the concurrent downloads.
+	RateLimiter *RateLimiter
+	//Bandwidth counts the bytes of all the items and stops them at its budget.
+	Bandwidth *Bandwidth
+	//NewYoutube creates the downloader of each item, NewYoutube(DebugMode) when nil.
+	NewYoutube func() *Youtube
+	//OnProgress receives the progress of every item, tagged by the item ID.
+	OnProgress func(ProgressEvent)
+	//OnFinish is called when an item is done, its Err tells if it failed.
+	OnFinish func(*BatchItem)
+	//ManifestFile receives the sha256 of the files downloaded by Run, in the
+	//format of sha256sum.
+	ManifestFile string
+
+	mu      sync.Mutex
+	queue   batchQueue
+	seq     int
+	running int
+	cond    *sync.Cond
+	closing bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+//NewBatch : Initialize a batch downloader with the number of parallel workers.
+func NewBatch(workers int, debug bool) *Batch {
+	if workers < 1 {
+		workers = 1
+	}
+	b := &Batch{Workers: workers, DebugMode: debug}
+	b.cond = sync.NewCond(&b.mu)
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	return b
+}
+
+//Add : Queue a download, it can be called while Run is processing the queue.
+//After Shutdown, the item is not queued and its Err is ErrShutdown.
+func (b *Batch) Add(url, destFile string, priority int) *BatchItem {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	item := &BatchItem{URL: url, DestFile: destFile, priority: priority, seq: b.seq}
+	if b.closing {
+		item.Err, item.done, item.index = ErrShutdown, true, -1
+		return item
+	}
+	heap.Push(&b.queue, item)
+	b.cond.Signal()
+	return item
+}
+
+//SetPriority : Reorder a pending item, false when it already started.
+func (b *Batch) SetPriority(item *BatchItem, priority int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if item.index < 0 || item.done {
+		return false
+	}
+	item.priority = priority
+	heap.Fix(&b.queue, item.index)
+	return true
+}
+
+//Cancel : Stop an item, false when it is already finished. A pending item leaves
+//the queue at once with ErrCanceled, without OnFinish. A running one is
+//interrupted and finishes with ErrCanceled, keeping its partial file.
+func (b *Batch) Cancel(item *BatchItem) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if item.done || item.canceled {
+		return false
+	}
+	if item.index >= 0 {
+		heap.Remove(&b.queue, item.index)
+		item.Err, item.done, item.index = ErrCanceled, true, -1
+		b.cond.Broadcast()
+		return true
+	}
+	item.canceled = true
+	if item.cancel != nil {
+		item.cancel()
+	}
+	return true
+}
+
+//Pending : The number of items waiting in the queue.
+func (b *Batch) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.queue.Len()
+}
+
+//Run : Download the queued items until the queue is empty and all workers are idle.
+func (b *Batch) Run() error {
+	var wg sync.WaitGroup
+	var failed int
+	var finished []*BatchItem
+	var failedMu sync.Mutex
+	for i := 0; i < b.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				item := b.next()
+				if item == nil {
+					return
+				}
+				item.Err = b.download(item)
+				if item.Err != nil && b.ctx.Err() != nil {
+					item.Err = ErrShutdown
+				}
+				b.finish(item)
+				if b.OnFinish != nil {
+					b.OnFinish(item)
+				}
+				failedMu.Lock()
+				finished = append(finished, item)
+				if item.Err != nil && item.Err != ErrAlreadyDownloaded && item.Err != ErrShutdown && item.Err != ErrCanceled {
+					failed++
+				}
+				failedMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if b.ManifestFile != "" {
+		if err := b.writeManifest(finished); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d batch downloads failed", failed)
+	}
+	return nil
+}
+
+//Shutdown : Stop starting the queued items, which stay Pending, and wait for the
+//running ones. Those still running when the context is done are interrupted, get
+//ErrShutdown and keep their partial file, then the context error is returned.
+func (b *Batch) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	b.closing = true
+	b.cond.Broadcast()
+	b.mu.Unlock()
+
+	idle := make(chan struct{})
+	go func() {
+		b.mu.Lock()
+		for b.running > 0 {
+			b.cond.Wait()
+		}
+		b.mu.Unlock()
+		close(idle)
+	}()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		<-idle
+		return ctx.Err()
+	}
+}
+
+//next waits for a queued item, nil when the queue is empty and nothing runs,
+//since a running item is the only thing that could add more work, or once
+//the batch is shut down.
+func (b *Batch) next() *BatchItem {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closing {
+		return nil
+	}
+	for b.queue.Len() == 0 {
+		if b.running == 0 {
+			b.cond.Broadcast()
+			return nil
+		}
+		b.cond.Wait()
+		if b.closing {
+			return nil
+		}
+	}
+	b.running++
+	return heap.Pop(&b.queue).(*BatchItem)
+}
+
+func (b *Batch) finish(item *BatchItem) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if item.Err != nil && item.canceled {
+		item.Err = ErrCanceled
+	}
+	item.done = true
+	b.running--
+	b.cond.Broadcast()
+}
+
+func (b *Batch) download(item *BatchItem) error {
+	var y *Youtube
+	if b.NewYoutube != nil {
+		y = b.NewYoutube()
+	} else {
+		y = NewYoutube(b.DebugMode)
+		//nobody reads the progress of batch items
+		go func(c chan int64) {
+			for range c {
+			}
+		}(y.DownloadPercent)
+		defer close(y.DownloadPercent)
+	}
+	if b.Archive != nil {
+		y.Archive = b.Archive
+	}
+	if b.RateLimiter != nil {
+		y.RateLimiter = b.RateLimiter
+	}
+	if b.Bandwidth != nil {
+		y.Bandwidth = b.Bandwidth
+	}
+	y.DownloadID = item.ID()
+	ctx, cancel := context.WithCancel(b.ctx)
+	defer cancel()
+	b.mu.Lock()
+	item.cancel = cancel
+	if item.canceled {
+		cancel()
+	}
+	b.mu.Unlock()
+	y.ctx = ctx
+	if b.OnProgress != nil {
+		y.OnProgress = b.OnProgress
+	}
+	if err := y.DecodeURL(item.URL); err != nil {
+		return err
+	}
+	result, err := y.Download(item.DestFile)
+	if result != nil {
+		item.Path, item.SHA256 = result.Path, result.SHA256
+	}
+	return err
+}
+
+//batchQueue is a container/heap of items, highest priority first.
+type batchQueue []*BatchItem
+
+func (q batchQueue) Len() int { return len(q) }
+
+func (q batchQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q batchQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *batchQueue) Push(x interface{}) {
+	item := x.(*BatchItem)
+	item.index = len(*q)
+	*q = append(*q, item)
+}
+
+func (q *batchQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	item.index = -1
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,130 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBatchPriority(t *testing.T) {
+	b := NewBatch(1, false)
+	low := b.Add("https://www.youtube.com/watch?v=rFejpH_tAHM", "a.mp4", 0)
+	b.Add("https://www.youtube.com/watch?v=FHpvI8oGsuQ", "b.mp4", 5)
+	last := b.Add("https://www.youtube.com/watch?v=XbNghLqsVwU", "c.mp4", 0)
+	if !b.SetPriority(last, 10) {
+		t.Fatal("Pending item priority should be changed")
+	}
+
+	var order []string
+	for b.Pending() > 0 {
+		item := b.next()
+		order = append(order, item.DestFile)
+		b.finish(item)
+	}
+	if len(order) != 3 || order[0] != "c.mp4" || order[1] != "b.mp4" || order[2] != "a.mp4" {
+		t.Errorf("Wrong download order: %v", order)
+	}
+	if b.SetPriority(low, 1) {
+		t.Error("Finished item priority should not be changed")
+	}
+}
+
+func TestBatchShutdown(t *testing.T) {
+	started := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/get_video_info" {
+			v, _ := url.ParseQuery(videoInfoFixture(""))
+			v.Set("url_encoded_fmt_stream_map", "itag=18&quality=medium&type=video%2Fmp4&url="+url.QueryEscape("http://"+r.Host+"/videoplayback"))
+			w.Write([]byte(v.Encode()))
+			return
+		}
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+	defer func(u string) { videoInfoURL = u }(videoInfoURL)
+	videoInfoURL = ts.URL + "/get_video_info"
+	dir := t.TempDir()
+
+	b := NewBatch(1, false)
+	first := b.Add("rFejpH_tAHM", filepath.Join(dir, "a.mp4"), 0)
+	b.Add("FHpvI8oGsuQ", filepath.Join(dir, "b.mp4"), 0)
+	done := make(chan error)
+	go func() { done <- b.Run() }()
+	select {
+	case <-started:
+	case err := <-done:
+		t.Fatalf("Run returned %v, first item %v", err, first.Err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := b.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %s", err)
+	}
+	if first.Err != ErrShutdown {
+		t.Errorf("The interrupted item got %v", first.Err)
+	}
+	if b.Pending() != 1 {
+		t.Errorf("%d pending items, want 1", b.Pending())
+	}
+	if item := b.Add("XbNghLqsVwU", "c.mp4", 0); item.Err != ErrShutdown {
+		t.Errorf("Item added after shutdown got %v", item.Err)
+	}
+}
+
+func TestBatchCancel(t *testing.T) {
+	started := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/get_video_info" {
+			v, _ := url.ParseQuery(videoInfoFixture(""))
+			v.Set("url_encoded_fmt_stream_map", "itag=18&quality=medium&type=video%2Fmp4&url="+url.QueryEscape("http://"+r.Host+"/videoplayback"))
+			w.Write([]byte(v.Encode()))
+			return
+		}
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+	defer func(u string) { videoInfoURL = u }(videoInfoURL)
+	videoInfoURL = ts.URL + "/get_video_info"
+	dir := t.TempDir()
+
+	b := NewBatch(1, false)
+	running := b.Add("rFejpH_tAHM", filepath.Join(dir, "a.mp4"), 0)
+	queued := b.Add("FHpvI8oGsuQ", filepath.Join(dir, "b.mp4"), 0)
+	var finished []*BatchItem
+	b.OnFinish = func(item *BatchItem) { finished = append(finished, item) }
+	done := make(chan error)
+	go func() { done <- b.Run() }()
+	<-started
+
+	if !b.Cancel(queued) || queued.Err != ErrCanceled || b.Pending() != 0 {
+		t.Errorf("The queued item should leave the queue, err=%v", queued.Err)
+	}
+	if !b.Cancel(running) {
+		t.Error("The running item should be canceled")
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Run failed: %s", err)
+	}
+	if running.Err != ErrCanceled || len(finished) != 1 || finished[0] != running {
+		t.Errorf("The running item got %v, finished %v", running.Err, finished)
+	}
+	if b.Cancel(running) {
+		t.Error("A finished item can't be canceled")
+	}
+}
@@ -0,0 +1,43 @@
+package youtube
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//LoadBatchFile : Queue the videos of a list file, one url or video id per line,
+//like youtube-dl --batch-file. Empty lines and lines starting with #, ; or ]
+//are skipped. Each video is saved as destDir/<video id>.mp4.
+func (b *Batch) LoadBatchFile(path, destDir string) ([]*BatchItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	type entry struct{ url, id string }
+	var entries []entry
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.ContainsAny(line[:1], "#;]") {
+			continue
+		}
+		videoID, err := ExtractVideoID(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %s", path, n, err)
+		}
+		entries = append(entries, entry{line, videoID})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	//queue only a fully valid file
+	var items []*BatchItem
+	for _, e := range entries {
+		items = append(items, b.Add(e.url, filepath.Join(destDir, e.id+".mp4"), 0))
+	}
+	return items, nil
+}
@@ -0,0 +1,31 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadBatchFile(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "list.txt")
+	ioutil.WriteFile(list, []byte("# talks\nhttps://www.youtube.com/watch?v=rFejpH_tAHM\n\n  ; skipped\nFHpvI8oGsuQ\n] also skipped\n"), 0644)
+
+	b := NewBatch(1, false)
+	items, err := b.LoadBatchFile(list, "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || b.Pending() != 2 {
+		t.Fatalf("Wrong items: %d queued, %d pending", len(items), b.Pending())
+	}
+	if items[0].DestFile != filepath.Join("out", "rFejpH_tAHM.mp4") || items[1].URL != "FHpvI8oGsuQ" {
+		t.Errorf("Wrong items: %+v %+v", items[0], items[1])
+	}
+
+	ioutil.WriteFile(list, []byte("FHpvI8oGsuQ\nhttps://example.com/video\n"), 0644)
+	b = NewBatch(1, false)
+	if _, err := b.LoadBatchFile(list, "out"); err == nil || b.Pending() != 0 {
+		t.Errorf("Invalid line should fail without queueing, err=%v", err)
+	}
+}
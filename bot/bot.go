@@ -0,0 +1,143 @@
+/*
+Package bot downloads videos as chat bot attachments, in the best quality
+that fits under the upload limit of the chat service.
+*/
+package bot
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kkdai/youtube"
+)
+
+//Upload limits of the bot apis, in bytes.
+const (
+	TelegramLimit int64 = 50 << 20
+	DiscordLimit  int64 = 25 << 20
+)
+
+//Attachment : A downloaded video and the metadata to send it.
+type Attachment struct {
+	//Path of the downloaded file in a temporary directory, removed by Close.
+	Path string
+	//FileName is the name to show in the chat, from the video title.
+	FileName     string
+	MimeType     string
+	Size         int64
+	Width        int
+	Height       int
+	VideoID      string
+	Title        string
+	Author       string
+	ThumbnailURL string
+}
+
+//Fetch : Download the video of the url in the best quality under maxSize, with a
+//downloader configured by the caller or a default one when y is nil. The caller
+//must Close the attachment. Merging separate formats, asked with
+//y.FormatSelector = "bestvideo+bestaudio/best", needs ffmpeg.
+func Fetch(y *youtube.Youtube, url string, maxSize int64) (*Attachment, error) {
+	if y == nil {
+		y = youtube.NewYoutube(false)
+	}
+	if err := y.DecodeURL(url); err != nil {
+		return nil, err
+	}
+	selector, max := y.FormatSelector, y.MaxFileSize
+	defer func() { y.FormatSelector, y.MaxFileSize = selector, max }()
+	y.MaxFileSize = maxSize
+	if y.FormatSelector == "" {
+		y.FormatSelector = "best"
+	}
+	formats, err := y.SelectFormats(y.FormatSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "youtube-bot")
+	if err != nil {
+		return nil, err
+	}
+	a := &Attachment{
+		VideoID:      y.VideoID,
+		ThumbnailURL: "https://i.ytimg.com/vi/" + y.VideoID + "/hqdefault.jpg",
+	}
+	if len(y.StreamList) > 0 {
+		a.Title = y.StreamList[0]["title"]
+		a.Author = y.StreamList[0]["author"]
+	}
+	var itags []string
+	for _, f := range formats {
+		itags = append(itags, strconv.Itoa(f.Itag))
+		if f.HasVideo {
+			a.Width, a.Height = f.Width, f.Height
+		}
+	}
+	ext := attachmentExt(formats)
+	//download the formats just picked instead of selecting and probing them again
+	y.FormatSelector = strings.Join(itags, "+")
+	y.MaxFileSize = 0
+	result, err := y.Download(filepath.Join(dir, y.VideoID+"."+ext))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	a.Path = result.Path
+	a.Size = result.Size
+	a.MimeType = mime.TypeByExtension(filepath.Ext(a.Path))
+	a.FileName = FileName(a.Title, a.VideoID) + filepath.Ext(a.Path)
+	if a.Size > maxSize {
+		a.Close()
+		return nil, fmt.Errorf("the download is %d bytes, over the %d bytes limit", a.Size, maxSize)
+	}
+	return a, nil
+}
+
+//Open : Read the downloaded file, e.g. for a multipart upload.
+func (a *Attachment) Open() (io.ReadCloser, error) {
+	return os.Open(a.Path)
+}
+
+//Close : Remove the downloaded file and its temporary directory.
+func (a *Attachment) Close() error {
+	return os.RemoveAll(filepath.Dir(a.Path))
+}
+
+//FileName : A file name for the title that is valid on every system, see
+//youtube.SafeFileName, the video id when the title is empty.
+func FileName(title, videoID string) string {
+	name := youtube.SafeFileName(title)
+	if name == "" {
+		return videoID
+	}
+	if len(name) > 100 {
+		name = name[:100]
+		for !utf8.ValidString(name) {
+			name = name[:len(name)-1]
+		}
+		name = strings.TrimRight(name, ". ")
+	}
+	return name
+}
+
+//attachmentExt is the container of the formats, merged ones go to mp4 when
+//both are mp4 and to mkv otherwise.
+func attachmentExt(formats []youtube.Format) string {
+	if len(formats) == 1 {
+		return formats[0].Ext()
+	}
+	for _, f := range formats {
+		if ext := f.Ext(); ext != "mp4" && ext != "m4a" {
+			return "mkv"
+		}
+	}
+	return "mp4"
+}
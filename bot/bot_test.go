@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/kkdai/youtube"
+)
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		title, want string
+	}{
+		{"AC/DC: Live?", "AC_DC_ Live_"},
+		{"  ", "rFejpH_tAHM"},
+		{"日本語", "日本語"},
+	}
+	for _, test := range tests {
+		if got := FileName(test.title, "rFejpH_tAHM"); got != test.want {
+			t.Errorf("FileName(%q) = %q, want %q", test.title, got, test.want)
+		}
+	}
+	long := ""
+	for i := 0; i < 40; i++ {
+		long += "日"
+	}
+	if got := FileName(long, "id"); len(got) != 99 {
+		t.Errorf("Long title cut to %d bytes, want 99", len(got))
+	}
+}
+
+func TestAttachmentExt(t *testing.T) {
+	mp4 := youtube.Format{MimeType: `video/mp4; codecs="avc1.640028"`}
+	m4a := youtube.Format{MimeType: `audio/mp4; codecs="mp4a.40.2"`}
+	webm := youtube.Format{MimeType: `audio/webm; codecs="opus"`}
+	if ext := attachmentExt([]youtube.Format{mp4, m4a}); ext != "mp4" {
+		t.Errorf("mp4+m4a merged to %s", ext)
+	}
+	if ext := attachmentExt([]youtube.Format{mp4, webm}); ext != "mkv" {
+		t.Errorf("mp4+webm merged to %s", ext)
+	}
+	if ext := attachmentExt([]youtube.Format{webm}); ext != "webm" {
+		t.Errorf("webm downloaded to %s", ext)
+	}
+}
@@ -0,0 +1,386 @@
+package youtube
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//SQLitePath : The sqlite3 command reading the cookie databases of the browsers,
+//the one found in PATH by default.
+var SQLitePath = "sqlite3"
+
+//chromiumBrowser is where a chromium based browser keeps its profile, and the
+//names of its key in the keychain and the keyring.
+type chromiumBrowser struct {
+	linux, darwin, windows string
+	keychain               string
+	keyring                string
+}
+
+var chromiumBrowsers = map[string]chromiumBrowser{
+	"chrome":   {".config/google-chrome", "Library/Application Support/Google/Chrome", `Google\Chrome\User Data`, "Chrome Safe Storage", "chrome"},
+	"chromium": {".config/chromium", "Library/Application Support/Chromium", `Chromium\User Data`, "Chromium Safe Storage", "chromium"},
+	"brave":    {".config/BraveSoftware/Brave-Browser", "Library/Application Support/BraveSoftware/Brave-Browser", `BraveSoftware\Brave-Browser\User Data`, "Brave Safe Storage", "brave"},
+	"edge":     {".config/microsoft-edge", "Library/Application Support/Microsoft Edge", `Microsoft\Edge\User Data`, "Microsoft Edge Safe Storage", "chromium"},
+}
+
+//ImportBrowserCookies : Read the youtube.com cookies of the default profile of a
+//local browser: chrome, chromium, brave, edge or firefox, to set as Cookies.
+//The databases are read with the sqlite3 command, and the chromium cookies are
+//decrypted with the key of the keychain on macOS, of the keyring on linux and
+//of DPAPI on windows. Close the browser if its database is locked. The cookies
+//which can't be decrypted are skipped, with one error each in skipped.
+func ImportBrowserCookies(browser string) (cookies []*http.Cookie, skipped []error, err error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	browser = strings.ToLower(browser)
+	if browser == "firefox" {
+		cookies, err = firefoxCookies(home)
+		return cookies, nil, err
+	}
+	b, ok := chromiumBrowsers[browser]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported browser '%s', use chrome, chromium, brave, edge or firefox", browser)
+	}
+	return chromiumCookies(home, b)
+}
+
+//firefoxCookies reads the cookies of the most recently used firefox profile.
+func firefoxCookies(home string) ([]*http.Cookie, error) {
+	var dir string
+	switch runtime.GOOS {
+	case "windows":
+		dir = filepath.Join(os.Getenv("APPDATA"), "Mozilla", "Firefox", "Profiles")
+	case "darwin":
+		dir = filepath.Join(home, "Library", "Application Support", "Firefox", "Profiles")
+	default:
+		dir = filepath.Join(home, ".mozilla", "firefox")
+	}
+	db, err := latestFile(filepath.Join(dir, "*", "cookies.sqlite"))
+	if err != nil {
+		return nil, fmt.Errorf("no firefox profile found in %s", dir)
+	}
+	var rows []struct {
+		Host     string `json:"host"`
+		Name     string `json:"name"`
+		Value    string `json:"value"`
+		Path     string `json:"path"`
+		Expiry   int64  `json:"expiry"`
+		IsSecure int    `json:"isSecure"`
+	}
+	if err = querySQLite(db, "SELECT host, name, value, path, expiry, isSecure FROM moz_cookies WHERE host = '.youtube.com' OR host = 'youtube.com' OR host LIKE '%.youtube.com'", &rows); err != nil {
+		return nil, err
+	}
+	var cookies []*http.Cookie
+	for _, r := range rows {
+		cookies = append(cookies, &http.Cookie{Name: r.Name, Value: r.Value, Domain: r.Host, Path: r.Path,
+			Expires: time.Unix(r.Expiry, 0), Secure: r.IsSecure == 1})
+	}
+	return cookies, nil
+}
+
+//chromiumCookies reads and decrypts the cookies of the default profile, and
+//returns the decrypt errors of the skipped ones.
+func chromiumCookies(home string, b chromiumBrowser) ([]*http.Cookie, []error, error) {
+	var dir string
+	switch runtime.GOOS {
+	case "windows":
+		dir = filepath.Join(os.Getenv("LOCALAPPDATA"), b.windows)
+	case "darwin":
+		dir = filepath.Join(home, b.darwin)
+	default:
+		dir = filepath.Join(home, b.linux)
+	}
+	db, err := latestFile(filepath.Join(dir, "Default", "Network", "Cookies"), filepath.Join(dir, "Default", "Cookies"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("no cookie database found in %s", dir)
+	}
+	var meta []struct {
+		Value string `json:"value"`
+	}
+	if err = querySQLite(db, "SELECT value FROM meta WHERE key = 'version'", &meta); err != nil {
+		return nil, nil, err
+	}
+	var version int
+	if len(meta) > 0 {
+		version, _ = strconv.Atoi(meta[0].Value)
+	}
+	var rows []struct {
+		Host      string `json:"host_key"`
+		Name      string `json:"name"`
+		Value     string `json:"value"`
+		Encrypted string `json:"encrypted"`
+		Path      string `json:"path"`
+		Expires   int64  `json:"expires_utc"`
+		IsSecure  int    `json:"is_secure"`
+	}
+	if err = querySQLite(db, "SELECT host_key, name, value, hex(encrypted_value) AS encrypted, path, expires_utc, is_secure FROM cookies WHERE host_key = '.youtube.com' OR host_key = 'youtube.com' OR host_key LIKE '%.youtube.com'", &rows); err != nil {
+		return nil, nil, err
+	}
+	d := &chromiumDecrypter{browser: b, dir: dir, dbVersion: version}
+	var cookies []*http.Cookie
+	var skipped []error
+	for _, r := range rows {
+		value := r.Value
+		if value == "" && r.Encrypted != "" {
+			encrypted, err := hex.DecodeString(r.Encrypted)
+			if err == nil {
+				value, err = d.decrypt(encrypted)
+			}
+			if err != nil {
+				//one unreadable cookie doesn't lose the others
+				skipped = append(skipped, fmt.Errorf("cookie %s of %s, decrypt error=%w", r.Name, r.Host, err))
+				continue
+			}
+		}
+		cookies = append(cookies, &http.Cookie{Name: r.Name, Value: value, Domain: r.Host, Path: r.Path,
+			Expires: chromiumTime(r.Expires), Secure: r.IsSecure == 1})
+	}
+	return cookies, skipped, nil
+}
+
+//chromiumTime converts the microseconds since 1601 of chromium.
+func chromiumTime(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(t/1e6-11644473600, 0)
+}
+
+//latestFile is the most recently modified file matching the patterns.
+func latestFile(patterns ...string) (string, error) {
+	var files []string
+	for _, p := range patterns {
+		matches, _ := filepath.Glob(p)
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return "", os.ErrNotExist
+	}
+	modTimes := make(map[string]time.Time)
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			modTimes[f] = info.ModTime()
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return modTimes[files[i]].After(modTimes[files[j]])
+	})
+	return files[0], nil
+}
+
+//querySQLite runs the query on a copy of the database, which the running
+//browser keeps locked, and decodes the json rows into answer.
+func querySQLite(db string, query string, answer interface{}) error {
+	tmp, err := ioutil.TempFile("", "cookies")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	in, err := os.Open(db)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	_, err = io.Copy(tmp, in)
+	in.Close()
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(SQLitePath, "-readonly", "-json", tmp.Name(), query)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("sqlite3 failed, err=%s, output=%s", err, stderr.Bytes())
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		//no row
+		return nil
+	}
+	return json.Unmarshal(out, answer)
+}
+
+//chromiumDecrypter decrypts the cookie values, v10 and v11 are the versions of
+//the encryption.
+type chromiumDecrypter struct {
+	browser   chromiumBrowser
+	dir       string
+	dbVersion int
+	keys      map[string][]byte
+}
+
+func (d *chromiumDecrypter) decrypt(encrypted []byte) (string, error) {
+	var value []byte
+	var err error
+	if runtime.GOOS == "windows" {
+		value, err = d.decryptWindows(encrypted)
+	} else {
+		value, err = d.decryptCBC(encrypted)
+	}
+	if err != nil {
+		return "", err
+	}
+	//since version 24 the value starts with the sha256 of the domain
+	if d.dbVersion >= 24 && len(value) >= 32 {
+		value = value[32:]
+	}
+	return string(value), nil
+}
+
+//decryptCBC decrypts the AES-128-CBC values of linux and macOS.
+func (d *chromiumDecrypter) decryptCBC(encrypted []byte) ([]byte, error) {
+	if len(encrypted) < 3 {
+		return nil, errors.New("value too short")
+	}
+	prefix := string(encrypted[:3])
+	if prefix != "v10" && prefix != "v11" {
+		return nil, fmt.Errorf("unknown encryption %q", prefix)
+	}
+	key, err := d.key(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return decryptChromiumCBC(key, encrypted[3:])
+}
+
+//key derives the key of the version from the password of the keychain or
+//keyring, v10 of linux uses a fixed password.
+func (d *chromiumDecrypter) key(version string) ([]byte, error) {
+	if key, ok := d.keys[version]; ok {
+		return key, nil
+	}
+	password, iterations := "peanuts", 1
+	switch {
+	case runtime.GOOS == "darwin":
+		out, err := exec.Command("security", "find-generic-password", "-w", "-s", d.browser.keychain).Output()
+		if err != nil {
+			return nil, fmt.Errorf("read '%s' from the keychain failed, err=%s", d.browser.keychain, err)
+		}
+		password, iterations = strings.TrimSpace(string(out)), 1003
+	case version == "v11":
+		out, err := exec.Command("secret-tool", "lookup", "application", d.browser.keyring).Output()
+		if err != nil {
+			return nil, fmt.Errorf("read the '%s' key from the keyring failed, err=%s", d.browser.keyring, err)
+		}
+		password = strings.TrimSpace(string(out))
+	}
+	key := pbkdf2SHA1([]byte(password), []byte("saltysalt"), iterations, 16)
+	if d.keys == nil {
+		d.keys = make(map[string][]byte)
+	}
+	d.keys[version] = key
+	return key, nil
+}
+
+//decryptChromiumCBC decrypts with the IV of 16 spaces and removes the PKCS7 padding.
+func decryptChromiumCBC(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid ciphertext length")
+	}
+	plain := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, bytes.Repeat([]byte{' '}, aes.BlockSize)).CryptBlocks(plain, ciphertext)
+	pad := int(plain[len(plain)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return nil, errors.New("invalid padding, wrong key")
+	}
+	return plain[:len(plain)-pad], nil
+}
+
+//decryptWindows decrypts the AES-256-GCM values with the key of the Local State
+//file, protected by DPAPI, or the older values protected by DPAPI directly.
+func (d *chromiumDecrypter) decryptWindows(encrypted []byte) ([]byte, error) {
+	if bytes.HasPrefix(encrypted, []byte("v20")) {
+		return nil, errors.New("the app-bound encryption of chrome 127 and later isn't supported, use firefox")
+	}
+	if !bytes.HasPrefix(encrypted, []byte("v10")) {
+		return dpapiDecrypt(encrypted)
+	}
+	key, ok := d.keys["v10"]
+	if !ok {
+		b, err := ioutil.ReadFile(filepath.Join(d.dir, "Local State"))
+		if err != nil {
+			return nil, err
+		}
+		var state struct {
+			OSCrypt struct {
+				EncryptedKey []byte `json:"encrypted_key"`
+			} `json:"os_crypt"`
+		}
+		if err = json.Unmarshal(b, &state); err != nil {
+			return nil, err
+		}
+		if key, err = dpapiDecrypt(bytes.TrimPrefix(state.OSCrypt.EncryptedKey, []byte("DPAPI"))); err != nil {
+			return nil, err
+		}
+		d.keys = map[string][]byte{"v10": key}
+	}
+	return decryptChromiumGCM(key, encrypted[3:])
+}
+
+//decryptChromiumGCM decrypts the 12 bytes nonce, ciphertext and tag.
+func decryptChromiumGCM(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("value too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+//pbkdf2SHA1 is the PBKDF2 key derivation of RFC 8018 with HMAC-SHA1.
+func pbkdf2SHA1(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha1.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.Write(prf, binary.BigEndian, block)
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package youtube
+
+import "errors"
+
+//dpapiDecrypt is only available on windows.
+func dpapiDecrypt(data []byte) ([]byte, error) {
+	return nil, errors.New("DPAPI is only available on windows")
+}
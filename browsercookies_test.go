@@ -0,0 +1,94 @@
+package youtube
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPBKDF2SHA1(t *testing.T) {
+	//RFC 6070 test vector
+	key := pbkdf2SHA1([]byte("password"), []byte("salt"), 2, 20)
+	if got := fmt.Sprintf("%x", key); got != "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957" {
+		t.Errorf("got %s", got)
+	}
+}
+
+//encryptChromiumCBC encrypts like chrome on linux with the v10 key.
+func encryptChromiumCBC(t *testing.T, value string) []byte {
+	block, err := aes.NewCipher(pbkdf2SHA1([]byte("peanuts"), []byte("saltysalt"), 1, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pad := aes.BlockSize - len(value)%aes.BlockSize
+	plain := append([]byte(value), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	cipher.NewCBCEncrypter(block, bytes.Repeat([]byte{' '}, aes.BlockSize)).CryptBlocks(plain, plain)
+	return append([]byte("v10"), plain...)
+}
+
+func TestChromiumCookies(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the v10 key is the linux one")
+	}
+	if _, err := exec.LookPath(SQLitePath); err != nil {
+		t.Skip("sqlite3 not found")
+	}
+	home := t.TempDir()
+	dir := filepath.Join(home, ".config", "google-chrome", "Default", "Network")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	sql := fmt.Sprintf(`CREATE TABLE meta(key TEXT, value TEXT);
+INSERT INTO meta VALUES('version', '18');
+CREATE TABLE cookies(host_key TEXT, name TEXT, value TEXT, encrypted_value BLOB, path TEXT, expires_utc INTEGER, is_secure INTEGER);
+INSERT INTO cookies VALUES('.youtube.com', 'SAPISID', '', X'%x', '/', 13400000000000000, 1);
+INSERT INTO cookies VALUES('.youtube.com', 'PREF', 'f6=40000000', X'', '/', 0, 0);
+INSERT INTO cookies VALUES('.google.com', 'NID', 'other', X'', '/', 0, 0);
+INSERT INTO cookies VALUES('.notyoutube.com', 'ID', 'other', X'', '/', 0, 0);
+INSERT INTO cookies VALUES('www.youtube.com', 'LOGIN_INFO', '', X'763130deadbeef', '/', 0, 1);`, encryptChromiumCBC(t, "secret/value"))
+	cmd := exec.Command(SQLitePath, filepath.Join(dir, "Cookies"), sql)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%s: %s", err, out)
+	}
+
+	cookies, skipped, err := chromiumCookies(home, chromiumBrowsers["chrome"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 1 || !strings.Contains(skipped[0].Error(), "LOGIN_INFO of www.youtube.com") {
+		t.Errorf("Wrong skipped cookies %v", skipped)
+	}
+	if len(cookies) != 2 || cookies[0].Value != "secret/value" || cookies[1].Value != "f6=40000000" {
+		t.Fatalf("Wrong cookies %v", cookies)
+	}
+	if c := cookies[0]; !c.Secure || c.Domain != ".youtube.com" || c.Expires.Year() != 2025 {
+		t.Errorf("Wrong cookie attributes %+v", c)
+	}
+}
+
+func TestSetCookies(t *testing.T) {
+	y := NewYoutube(false)
+	y.Cookies = []*http.Cookie{
+		{Name: "SAPISID", Value: "a", Domain: ".youtube.com"},
+		{Name: "NID", Value: "b", Domain: ".google.com"},
+		{Name: "PREF", Value: "c"},
+	}
+	req, _ := http.NewRequest("GET", "https://www.youtube.com/watch?v=rFejpH_tAHM", nil)
+	y.setCookies(req)
+	if got := req.Header.Get("Cookie"); got != "SAPISID=a; PREF=c" {
+		t.Errorf("got cookies %q", got)
+	}
+	req, _ = http.NewRequest("GET", "https://r1.googlevideo.com/videoplayback", nil)
+	y.setCookies(req)
+	if got := req.Header.Get("Cookie"); got != "" {
+		t.Errorf("got cookies %q", got)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package youtube
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procCryptUnprotectData = syscall.NewLazyDLL("crypt32.dll").NewProc("CryptUnprotectData")
+
+type dataBlob struct {
+	size uint32
+	data *byte
+}
+
+//dpapiDecrypt decrypts data protected by DPAPI for the current user.
+func dpapiDecrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, syscall.EINVAL
+	}
+	in := dataBlob{size: uint32(len(data)), data: &data[0]}
+	var out dataBlob
+	r, _, err := procCryptUnprotectData.Call(uintptr(unsafe.Pointer(&in)), 0, 0, 0, 0, 0, uintptr(unsafe.Pointer(&out)))
+	if r == 0 {
+		return nil, err
+	}
+	defer syscall.LocalFree(syscall.Handle(unsafe.Pointer(out.data)))
+	return append([]byte(nil), unsafe.Slice(out.data, out.size)...), nil
+}
@@ -0,0 +1,151 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//CaptionTrack : A subtitle track of the video.
+type CaptionTrack struct {
+	//Language is the language code, e.g. en or pt-BR.
+	Language string
+	Name     string
+	//AutoGenerated is set for the speech recognition tracks.
+	AutoGenerated bool
+	//Translatable tells if youtube can translate the track to other languages.
+	Translatable bool
+	//TranslatedFrom is the language of the original track of a translated track.
+	TranslatedFrom string
+	URL            string
+}
+
+//TranslateCaptions : The track auto-translated by youtube to the language, one
+//of TranslationLanguages.
+func (y *Youtube) TranslateCaptions(t CaptionTrack, lang string) (CaptionTrack, error) {
+	if !t.Translatable {
+		return CaptionTrack{}, fmt.Errorf("%s caption track can't be translated", t.Language)
+	}
+	if len(y.TranslationLanguages) > 0 && !containsFold(y.TranslationLanguages, lang) {
+		return CaptionTrack{}, fmt.Errorf("youtube can't translate captions to %s", lang)
+	}
+	translated := t
+	translated.Language = lang
+	translated.Name = t.Name + " >> " + lang
+	translated.Translatable = false
+	translated.TranslatedFrom = t.Language
+	translated.URL = setQueryParam(t.URL, "tlang", lang)
+	return translated, nil
+}
+
+//SelectCaptionTrack : Pick the caption track of the first available language of
+//the preference list. A language also matches its regional variants, "pt" matches
+// "pt-BR". Auto generated tracks are only used when allowAuto is set and no
+//uploaded track matches.
+func (y *Youtube) SelectCaptionTrack(languages []string, allowAuto bool) (CaptionTrack, error) {
+	for _, auto := range []bool{false, true} {
+		if auto && !allowAuto {
+			break
+		}
+		for _, lang := range languages {
+			if t, ok := findCaptionTrack(y.CaptionTracks, lang, auto); ok {
+				return t, nil
+			}
+		}
+	}
+	return CaptionTrack{}, fmt.Errorf("no caption track in %s", strings.Join(languages, ", "))
+}
+
+//findCaptionTrack looks for the exact language first, then for a variant.
+func findCaptionTrack(tracks []CaptionTrack, lang string, auto bool) (CaptionTrack, bool) {
+	var variant *CaptionTrack
+	for i, t := range tracks {
+		if t.AutoGenerated != auto {
+			continue
+		}
+		if strings.EqualFold(t.Language, lang) {
+			return t, true
+		}
+		if variant == nil && sameBaseLanguage(t.Language, lang) {
+			variant = &tracks[i]
+		}
+	}
+	if variant != nil {
+		return *variant, true
+	}
+	return CaptionTrack{}, false
+}
+
+//sameBaseLanguage compares the languages without their region, pt-BR and pt-PT match.
+func sameBaseLanguage(a, b string) bool {
+	base := func(l string) string {
+		return strings.ToLower(strings.SplitN(l, "-", 2)[0])
+	}
+	return base(a) == base(b)
+}
+
+//DownloadCaptions : Download the caption track selected like SelectCaptionTrack
+//in the WebVTT format, and return the track used.
+func (y *Youtube) DownloadCaptions(destFile string, languages []string, allowAuto bool) (CaptionTrack, error) {
+	t, err := y.SelectCaptionTrack(languages, allowAuto)
+	if err != nil {
+		return CaptionTrack{}, err
+	}
+	return t, y.DownloadCaptionTrack(destFile, t)
+}
+
+//DownloadTranslatedCaptions : Download the captions translated to the language,
+//from the track of the preferred languages, or else from any translatable track.
+func (y *Youtube) DownloadTranslatedCaptions(destFile string, lang string, from []string, allowAuto bool) (CaptionTrack, error) {
+	source, err := y.SelectCaptionTrack(from, allowAuto)
+	if err != nil || !source.Translatable {
+		source, err = y.translatableTrack(allowAuto)
+		if err != nil {
+			return CaptionTrack{}, err
+		}
+	}
+	t, err := y.TranslateCaptions(source, lang)
+	if err != nil {
+		return CaptionTrack{}, err
+	}
+	return t, y.DownloadCaptionTrack(destFile, t)
+}
+
+//translatableTrack returns the first translatable track, uploaded ones first.
+func (y *Youtube) translatableTrack(allowAuto bool) (CaptionTrack, error) {
+	for _, auto := range []bool{false, allowAuto} {
+		for _, t := range y.CaptionTracks {
+			if t.Translatable && t.AutoGenerated == auto {
+				return t, nil
+			}
+		}
+	}
+	return CaptionTrack{}, errors.New("no translatable caption track")
+}
+
+//DownloadCaptionTrack : Download the caption track in the WebVTT format.
+func (y *Youtube) DownloadCaptionTrack(destFile string, t CaptionTrack) error {
+	y.log(fmt.Sprintf("Download %s captions '%s' to file=%s", t.Language, t.Name, destFile))
+	body, err := y.fetch(setQueryParam(t.URL, "fmt", "vtt"))
+	if err != nil {
+		return err
+	}
+	out, err := y.createOutput(destFile, false)
+	if err != nil {
+		return err
+	}
+	if _, err = out.Write(body); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
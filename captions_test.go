@@ -0,0 +1,91 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+const captionsPlayerResponse = `{"captions":{"playerCaptionsTracklistRenderer":{"captionTracks":[
+{"baseUrl":"https://www.youtube.com/api/timedtext?lang=en","name":{"simpleText":"English"},"languageCode":"en","isTranslatable":true},
+{"baseUrl":"https://www.youtube.com/api/timedtext?lang=pt-PT","name":{"simpleText":"Portuguese (Portugal)"},"languageCode":"pt-PT"},
+{"baseUrl":"https://www.youtube.com/api/timedtext?lang=ja&kind=asr","name":{"runs":[{"text":"Japanese (auto-generated)"}]},"languageCode":"ja","kind":"asr"}],
+"translationLanguages":[{"languageCode":"fr","languageName":{"simpleText":"French"}},{"languageCode":"zh-Hant"}]}}}`
+
+func TestSelectCaptionTrack(t *testing.T) {
+	y := NewYoutube(false)
+	y.videoInfo = videoInfoFixture(captionsPlayerResponse)
+	if err := y.parseVideoInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if len(y.CaptionTracks) != 3 || !y.CaptionTracks[2].AutoGenerated || y.CaptionTracks[2].Name != "Japanese (auto-generated)" {
+		t.Fatalf("Wrong caption tracks: %+v", y.CaptionTracks)
+	}
+
+	tests := []struct {
+		languages []string
+		allowAuto bool
+		want      string
+	}{
+		{[]string{"pt-BR", "pt", "en"}, false, "pt-PT"},
+		{[]string{"ja", "en"}, false, "en"},
+		{[]string{"ja", "en"}, true, "en"},
+		{[]string{"ja"}, true, "ja"},
+		{[]string{"ja"}, false, ""},
+	}
+	for _, tt := range tests {
+		track, err := y.SelectCaptionTrack(tt.languages, tt.allowAuto)
+		if (err != nil) != (tt.want == "") || track.Language != tt.want {
+			t.Errorf("%v auto=%v: got %q err=%v, want %q", tt.languages, tt.allowAuto, track.Language, err, tt.want)
+		}
+	}
+}
+
+func TestDownloadCaptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fmt") != "vtt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("WEBVTT\n"))
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.CaptionTracks = []CaptionTrack{{Language: "en", URL: ts.URL + "/api/timedtext?lang=en"}}
+	dest := filepath.Join(t.TempDir(), "dl.en.vtt")
+	track, err := y.DownloadCaptions(dest, []string{"fr", "en"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != "WEBVTT\n" || track.Language != "en" {
+		t.Errorf("Wrong captions %q from track %+v", b, track)
+	}
+}
+
+func TestTranslateCaptions(t *testing.T) {
+	y := NewYoutube(false)
+	y.videoInfo = videoInfoFixture(captionsPlayerResponse)
+	if err := y.parseVideoInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if len(y.TranslationLanguages) != 2 {
+		t.Fatalf("Wrong translation languages: %v", y.TranslationLanguages)
+	}
+	translated, err := y.TranslateCaptions(y.CaptionTracks[0], "zh-Hant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translated.Language != "zh-Hant" || translated.TranslatedFrom != "en" ||
+		translated.URL != "https://www.youtube.com/api/timedtext?lang=en&tlang=zh-Hant" {
+		t.Errorf("Wrong translated track: %+v", translated)
+	}
+	if _, err := y.TranslateCaptions(y.CaptionTracks[1], "fr"); err == nil {
+		t.Error("Untranslatable track should fail")
+	}
+	if _, err := y.TranslateCaptions(y.CaptionTracks[0], "xx"); err == nil {
+		t.Error("Unknown language should fail")
+	}
+}
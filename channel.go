@@ -0,0 +1,148 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//ChannelLink : A link listed on the channel about page.
+type ChannelLink struct {
+	Title string
+	URL   string
+}
+
+//ChannelInfo : Information of the channel about page.
+//The counts are parsed from the displayed texts, so they are rounded like them.
+type ChannelInfo struct {
+	ID              string
+	Title           string
+	Description     string
+	SubscriberCount int64
+	ViewCount       int64
+	Links           []ChannelLink
+	AvatarURL       string
+	BannerURL       string
+}
+
+var countRe = regexp.MustCompile(`([\d.,]+)\s*([KMB])?`)
+
+//GetChannelInfo : Retrieval the about page of a channel id (UC...) or handle (@name),
+//or its Data API resource when APIKey is set, which has no links.
+func (y *Youtube) GetChannelInfo(channelID string) (*ChannelInfo, error) {
+	if y.useDataAPI() {
+		info, err := y.apiChannelInfo(context.Background(), channelID)
+		if err == nil {
+			return info, nil
+		}
+		y.dataAPIFallback(err)
+	}
+	data, err := y.fetchInitialData(channelURL(channelID) + "/about")
+	if err != nil {
+		return nil, err
+	}
+	info := parseChannelInfo(data)
+	if info.ID == "" && info.Title == "" {
+		return nil, fmt.Errorf("no channel information found for '%s'", channelID)
+	}
+	return info, nil
+}
+
+func channelURL(channelID string) string {
+	if strings.HasPrefix(channelID, "@") {
+		return "https://www.youtube.com/" + channelID
+	}
+	return "https://www.youtube.com/channel/" + channelID
+}
+
+func parseChannelInfo(data interface{}) *ChannelInfo {
+	info := &ChannelInfo{}
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		switch key {
+		case "channelMetadataRenderer":
+			info.ID, _ = v["externalId"].(string)
+			info.Title, _ = v["title"].(string)
+			info.Description, _ = v["description"].(string)
+			info.AvatarURL = lastThumbnail(v["avatar"])
+		case "c4TabbedHeaderRenderer":
+			info.SubscriberCount = parseCount(jsonText(v["subscriberCountText"]))
+			info.BannerURL = lastThumbnail(v["banner"])
+		case "channelAboutFullMetadataRenderer":
+			info.ViewCount = parseCount(jsonText(v["viewCountText"]))
+			links, _ := v["primaryLinks"].([]interface{})
+			for _, l := range links {
+				lm, _ := l.(map[string]interface{})
+				var link ChannelLink
+				link.Title = jsonText(lm["title"])
+				walkJSON(lm, func(key string, v map[string]interface{}) {
+					if key == "urlEndpoint" {
+						link.URL, _ = v["url"].(string)
+					}
+				})
+				info.Links = append(info.Links, link)
+			}
+		case "aboutChannelViewModel":
+			//newer layout of the about page
+			if s, ok := v["subscriberCountText"].(string); ok {
+				info.SubscriberCount = parseCount(s)
+			}
+			if s, ok := v["viewCountText"].(string); ok {
+				info.ViewCount = parseCount(s)
+			}
+			links, _ := v["links"].([]interface{})
+			for _, l := range links {
+				walkJSON(l, func(key string, v map[string]interface{}) {
+					if key == "channelExternalLinkViewModel" {
+						info.Links = append(info.Links, ChannelLink{
+							Title: viewModelText(v["title"]),
+							URL:   viewModelText(v["link"]),
+						})
+					}
+				})
+			}
+		}
+	})
+	return info
+}
+
+//lastThumbnail returns the last, the largest, url of {"thumbnails": [{"url": ..}]}.
+func lastThumbnail(v interface{}) string {
+	m, _ := v.(map[string]interface{})
+	thumbs, _ := m["thumbnails"].([]interface{})
+	if len(thumbs) == 0 {
+		return ""
+	}
+	t, _ := thumbs[len(thumbs)-1].(map[string]interface{})
+	u, _ := t["url"].(string)
+	return u
+}
+
+//viewModelText reads the {"content": ..} texts of the view models.
+func viewModelText(v interface{}) string {
+	m, _ := v.(map[string]interface{})
+	s, _ := m["content"].(string)
+	return s
+}
+
+//parseCount reads counts like "1,234 views" or "1.2M subscribers".
+func parseCount(text string) int64 {
+	subs := countRe.FindStringSubmatch(text)
+	if subs == nil {
+		return 0
+	}
+	n, err := strconv.ParseFloat(strings.Replace(subs[1], ",", "", -1), 64)
+	if err != nil {
+		return 0
+	}
+	switch subs[2] {
+	case "K":
+		n *= 1e3
+	case "M":
+		n *= 1e6
+	case "B":
+		n *= 1e9
+	}
+	return int64(n)
+}
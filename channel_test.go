@@ -0,0 +1,30 @@
+package youtube
+
+import "testing"
+
+const channelPage = `<script>var ytInitialData = {"header":{"c4TabbedHeaderRenderer":{"subscriberCountText":{"simpleText":"1.2M subscribers"},
+"banner":{"thumbnails":[{"url":"https://yt3.ggpht.com/small"},{"url":"https://yt3.ggpht.com/banner"}]}}},
+"contents":{"channelAboutFullMetadataRenderer":{"viewCountText":{"simpleText":"123,456,789 views"},
+"primaryLinks":[{"title":{"simpleText":"Website"},"navigationEndpoint":{"urlEndpoint":{"url":"https://www.dotconferences.com"}}}]}},
+"metadata":{"channelMetadataRenderer":{"externalId":"UCSRhwaM00ay0fasnsw6EXKA","title":"dotconferences","description":"Talks",
+"avatar":{"thumbnails":[{"url":"https://yt3.ggpht.com/avatar"}]}}}};</script>`
+
+func TestParseChannelInfo(t *testing.T) {
+	data, err := extractInitialData([]byte(channelPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := parseChannelInfo(data)
+	if info.ID != "UCSRhwaM00ay0fasnsw6EXKA" || info.Title != "dotconferences" || info.Description != "Talks" {
+		t.Errorf("Wrong channel metadata: %+v", info)
+	}
+	if info.SubscriberCount != 1200000 || info.ViewCount != 123456789 {
+		t.Errorf("Wrong channel counts: %d %d", info.SubscriberCount, info.ViewCount)
+	}
+	if info.AvatarURL != "https://yt3.ggpht.com/avatar" || info.BannerURL != "https://yt3.ggpht.com/banner" {
+		t.Errorf("Wrong channel images: %s %s", info.AvatarURL, info.BannerURL)
+	}
+	if len(info.Links) != 1 || info.Links[0].URL != "https://www.dotconferences.com" {
+		t.Errorf("Wrong channel links: %+v", info.Links)
+	}
+}
@@ -0,0 +1,190 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+//ChannelTab : A tab of a channel page, each one has its own browse endpoint.
+type ChannelTab string
+
+//The channel tabs listing videos, then the ones listing playlists.
+const (
+	TabVideos    ChannelTab = "videos"
+	TabShorts    ChannelTab = "shorts"
+	TabLive      ChannelTab = "streams"
+	TabReleases  ChannelTab = "releases"
+	TabPodcasts  ChannelTab = "podcasts"
+	TabPlaylists ChannelTab = "playlists"
+)
+
+//ChannelTabs : All the tabs, in the order of the channel page.
+var ChannelTabs = []ChannelTab{TabVideos, TabShorts, TabLive, TabReleases, TabPodcasts, TabPlaylists}
+
+//ChannelPlaylist : A playlist, album or podcast listed on a channel tab.
+type ChannelPlaylist struct {
+	ID         string
+	Title      string
+	VideoCount int64
+}
+
+//ChannelTabContent : The items of a channel tab, videos for the videos, shorts
+//and live tabs, playlists for the releases, podcasts and playlists tabs.
+type ChannelTabContent struct {
+	ChannelID string
+	Tab       ChannelTab
+	Videos    []VideoSummary
+	Playlists []ChannelPlaylist
+}
+
+//ParseChannelTabs : Read a comma separated tab selector, like "videos,shorts",
+// "live" is accepted for "streams" and "all" selects every tab.
+func ParseChannelTabs(selector string) ([]ChannelTab, error) {
+	var tabs []ChannelTab
+	for _, name := range strings.Split(selector, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "":
+			continue
+		case "all":
+			return ChannelTabs, nil
+		case "live":
+			name = string(TabLive)
+		}
+		tab := ChannelTab(name)
+		found := false
+		for _, t := range ChannelTabs {
+			found = found || t == tab
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown channel tab '%s'", name)
+		}
+		tabs = append(tabs, tab)
+	}
+	if len(tabs) == 0 {
+		return nil, fmt.Errorf("no channel tab in '%s'", selector)
+	}
+	return tabs, nil
+}
+
+//GetChannelTab : Retrieval the items of a tab of a channel id (UC...) or handle
+// (@name), following every page until the end or the context is done.
+func (y *Youtube) GetChannelTab(ctx context.Context, channelID string, tab ChannelTab) (*ChannelTabContent, error) {
+	page, err := y.request(ctx, "GET", channelURL(channelID)+"/"+string(tab), nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := extractInitialData(page)
+	if err != nil {
+		return nil, err
+	}
+	//youtube shows the home tab of the channels without the tab
+	if selected := selectedChannelTab(data); selected != "" && selected != tab {
+		return nil, fmt.Errorf("channel %s has no %s tab", channelID, tab)
+	}
+	cfg := y.innertubeConfigOf(ctx, page)
+	c := &ChannelTabContent{ChannelID: channelID, Tab: tab}
+	seen := make(map[string]bool)
+	for pages := 1; ; pages++ {
+		parseChannelTabPage(data, c, seen)
+		token := continuationToken(data)
+		if token == "" {
+			break
+		}
+		if data, err = y.browseContinuation(ctx, cfg, token); err != nil {
+			return nil, fmt.Errorf("channel %s page %d: %s", tab, pages+1, err)
+		}
+	}
+	y.log(fmt.Sprintf("Channel %s tab %s: %d videos, %d playlists", channelID, tab, len(c.Videos), len(c.Playlists)))
+	return c, nil
+}
+
+//GetChannelTabs : Retrieval the selected tabs of a channel, see GetChannelTab.
+func (y *Youtube) GetChannelTabs(ctx context.Context, channelID string, tabs []ChannelTab) ([]*ChannelTabContent, error) {
+	var contents []*ChannelTabContent
+	for _, tab := range tabs {
+		c, err := y.GetChannelTab(ctx, channelID, tab)
+		if err != nil {
+			return contents, err
+		}
+		contents = append(contents, c)
+	}
+	return contents, nil
+}
+
+//selectedChannelTab is the tab the page shows, from the url of its selected tabRenderer.
+func selectedChannelTab(data interface{}) ChannelTab {
+	var tab ChannelTab
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		if key != "tabRenderer" || v["selected"] != true || tab != "" {
+			return
+		}
+		walkJSON(v["endpoint"], func(key string, v map[string]interface{}) {
+			if u, ok := v["url"].(string); ok && key == "webCommandMetadata" {
+				tab = ChannelTab(path.Base(u))
+			}
+		})
+	})
+	return tab
+}
+
+//parseChannelTabPage collects the videos and playlists of a page of a channel
+//tab, in their classic renderers and in the newer view models.
+func parseChannelTabPage(data interface{}, c *ChannelTabContent, seen map[string]bool) {
+	addVideo := func(id, title string, membersOnly bool) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			c.Videos = append(c.Videos, VideoSummary{ID: id, Title: title, MembersOnly: membersOnly})
+		}
+	}
+	addPlaylist := func(p ChannelPlaylist) {
+		if p.ID != "" && !seen[p.ID] {
+			seen[p.ID] = true
+			c.Playlists = append(c.Playlists, p)
+		}
+	}
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		switch key {
+		case "videoRenderer", "gridVideoRenderer":
+			id, _ := v["videoId"].(string)
+			addVideo(id, jsonText(v["title"]), membersOnlyBadge(v["badges"]))
+		case "reelItemRenderer":
+			id, _ := v["videoId"].(string)
+			addVideo(id, jsonText(v["headline"]), false)
+		case "shortsLockupViewModel":
+			var id string
+			walkJSON(v["onTap"], func(key string, v map[string]interface{}) {
+				if key == "reelWatchEndpoint" {
+					id, _ = v["videoId"].(string)
+				}
+			})
+			overlay, _ := v["overlayMetadata"].(map[string]interface{})
+			addVideo(id, viewModelText(overlay["primaryText"]), false)
+		case "gridPlaylistRenderer", "playlistRenderer":
+			p := ChannelPlaylist{Title: jsonText(v["title"]), VideoCount: parseCount(jsonText(v["videoCountText"]))}
+			p.ID, _ = v["playlistId"].(string)
+			addPlaylist(p)
+		case "lockupViewModel":
+			if v["contentType"] != "LOCKUP_CONTENT_TYPE_PLAYLIST" && v["contentType"] != "LOCKUP_CONTENT_TYPE_PODCAST" {
+				return
+			}
+			p := ChannelPlaylist{}
+			p.ID, _ = v["contentId"].(string)
+			walkJSON(v["metadata"], func(key string, v map[string]interface{}) {
+				if key == "lockupMetadataViewModel" {
+					p.Title = viewModelText(v["title"])
+				}
+			})
+			walkJSON(v["contentImage"], func(key string, v map[string]interface{}) {
+				if key == "thumbnailBadgeViewModel" && p.VideoCount == 0 {
+					if text, _ := v["text"].(string); strings.Contains(text, "video") || strings.Contains(text, "episode") {
+						p.VideoCount = parseCount(text)
+					}
+				}
+			})
+			addPlaylist(p)
+		}
+	})
+}
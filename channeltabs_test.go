@@ -0,0 +1,57 @@
+package youtube
+
+import "testing"
+
+const channelTabPage = `<script>var ytInitialData = {"contents":{"twoColumnBrowseResultsRenderer":{"tabs":[
+{"tabRenderer":{"endpoint":{"commandMetadata":{"webCommandMetadata":{"url":"/@dotconferences/videos"}}},"selected":false}},
+{"tabRenderer":{"endpoint":{"commandMetadata":{"webCommandMetadata":{"url":"/@dotconferences/shorts"}}},"selected":true,
+"content":{"richGridRenderer":{"contents":[
+{"richItemRenderer":{"content":{"reelItemRenderer":{"videoId":"rFejpH_tAHM","headline":{"simpleText":"Simplicity"}}}}},
+{"richItemRenderer":{"content":{"shortsLockupViewModel":{"onTap":{"innertubeCommand":{"reelWatchEndpoint":{"videoId":"FHpvI8oGsuQ"}}},
+"overlayMetadata":{"primaryText":{"content":"Gophers"}}}}}},
+{"richItemRenderer":{"content":{"reelItemRenderer":{"videoId":"rFejpH_tAHM","headline":{"simpleText":"Simplicity"}}}}}]}}}}]}}};</script>`
+
+func TestParseChannelTab(t *testing.T) {
+	data, err := extractInitialData([]byte(channelTabPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tab := selectedChannelTab(data); tab != TabShorts {
+		t.Errorf("Wrong selected tab %s", tab)
+	}
+	c := &ChannelTabContent{}
+	parseChannelTabPage(data, c, map[string]bool{})
+	if len(c.Videos) != 2 || c.Videos[0].Title != "Simplicity" || c.Videos[1].ID != "FHpvI8oGsuQ" || c.Videos[1].Title != "Gophers" {
+		t.Errorf("Wrong shorts %+v", c.Videos)
+	}
+}
+
+func TestParseChannelTabPlaylists(t *testing.T) {
+	data := map[string]interface{}{"items": []interface{}{
+		map[string]interface{}{"gridPlaylistRenderer": map[string]interface{}{
+			"playlistId": "OLAK5uy_k1", "title": map[string]interface{}{"simpleText": "Album"},
+			"videoCountText": map[string]interface{}{"simpleText": "12 songs"}}},
+		map[string]interface{}{"lockupViewModel": map[string]interface{}{
+			"contentId": "PL59FEE129ADFF2B12", "contentType": "LOCKUP_CONTENT_TYPE_PODCAST",
+			"metadata": map[string]interface{}{"lockupMetadataViewModel": map[string]interface{}{
+				"title": map[string]interface{}{"content": "Podcast"}}}}},
+	}}
+	c := &ChannelTabContent{}
+	parseChannelTabPage(data, c, map[string]bool{})
+	if len(c.Playlists) != 2 || c.Playlists[0].VideoCount != 12 || c.Playlists[1].Title != "Podcast" {
+		t.Errorf("Wrong playlists %+v", c.Playlists)
+	}
+}
+
+func TestParseChannelTabs(t *testing.T) {
+	tabs, err := ParseChannelTabs("videos, Live")
+	if err != nil || len(tabs) != 2 || tabs[1] != TabLive {
+		t.Errorf("got %v, err=%v", tabs, err)
+	}
+	if tabs, _ := ParseChannelTabs("all"); len(tabs) != len(ChannelTabs) {
+		t.Errorf("got %v", tabs)
+	}
+	if _, err := ParseChannelTabs("community"); err == nil {
+		t.Error("Unknown tab should fail")
+	}
+}
@@ -0,0 +1,118 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//DefaultChapterTemplate : The names of the chapter files when ChapterTemplate is empty.
+const DefaultChapterTemplate = "{name} - {index} - {chapter}{ext}"
+
+//Chapter : A chapter of the video, End is zero for the last one which runs to
+//the end of the video.
+type Chapter struct {
+	Title string
+	Start time.Duration
+	End   time.Duration
+}
+
+//GetChapters : Retrieval the chapters of the decoded video, empty when the video has none.
+func (y *Youtube) GetChapters() ([]Chapter, error) {
+	if y.VideoID == "" {
+		return nil, errors.New("no video decoded")
+	}
+	data, err := y.fetchInitialData("https://www.youtube.com/watch?v=" + y.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	chapters := parseChapters(data)
+	y.log(fmt.Sprintf("Found %d chapters", len(chapters)))
+	return chapters, nil
+}
+
+//parseChapters reads the chapter markers of the player bar, the description
+//and the auto generated chapters can both be there so the starts are deduplicated.
+func parseChapters(data interface{}) []Chapter {
+	var chapters []Chapter
+	seen := map[time.Duration]bool{}
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		if key != "chapterRenderer" {
+			return
+		}
+		start := jsonMillis(v["timeRangeStartMillis"])
+		if !seen[start] {
+			seen[start] = true
+			chapters = append(chapters, Chapter{Title: jsonText(v["title"]), Start: start})
+		}
+	})
+	sort.Slice(chapters, func(i, j int) bool { return chapters[i].Start < chapters[j].Start })
+	for i := 0; i+1 < len(chapters); i++ {
+		chapters[i].End = chapters[i+1].Start
+	}
+	return chapters
+}
+
+//chapterFileName expands the ChapterTemplate for the chapter at index, from 1.
+func (y *Youtube) chapterFileName(file string, index int, c Chapter) string {
+	template := y.ChapterTemplate
+	if template == "" {
+		template = DefaultChapterTemplate
+	}
+	dir, base := filepath.Split(file)
+	ext := filepath.Ext(base)
+	title := SafeFileName(c.Title)
+	if y.RestrictFileNames {
+		title = ASCIIFileName(title)
+	}
+	if title == "" {
+		title = "chapter"
+	}
+	r := strings.NewReplacer("{name}", strings.TrimSuffix(base, ext), "{index}", fmt.Sprintf("%02d", index),
+		"{chapter}", title, "{ext}", ext)
+	return filepath.Join(dir, SafeFileName(r.Replace(template)))
+}
+
+//splitChapters copies each chapter of the file to its own file without
+//re-encoding, with the Chapters or else the ones of the video page, or the
+//silences of the file with SilenceSplit, and keeps the file. The cuts fall on
+//the key frames, audio files are cut exactly. It returns the chapters used.
+func (y *Youtube) splitChapters(file string) ([]Chapter, []string, error) {
+	chapters := y.Chapters
+	var err error
+	if chapters == nil {
+		if chapters, err = y.GetChapters(); err != nil {
+			return nil, nil, fmt.Errorf("get chapters error=%s", err)
+		}
+	}
+	if len(chapters) == 0 && y.SilenceSplit != nil {
+		if chapters, err = y.DetectChapters(file, *y.SilenceSplit); err != nil {
+			return nil, nil, err
+		}
+	}
+	var files []string
+	for i, c := range chapters {
+		out := y.chapterFileName(file, i+1, c)
+		args := []string{"-i", file, "-ss", formatSeconds(c.Start)}
+		if c.End > c.Start {
+			args = append(args, "-t", formatSeconds(c.End-c.Start))
+		}
+		args = append(args, "-map", "0", "-c", "copy", "-metadata", "title="+c.Title, out)
+		if err := y.runFFmpeg(args...); err != nil {
+			os.Remove(out)
+			return chapters, files, err
+		}
+		files = append(files, out)
+	}
+	y.log(fmt.Sprintf("Split into %d chapter files", len(files)))
+	return chapters, files, nil
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,54 @@
+package youtube
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const chaptersData = `{"markersMap":[
+{"key":"DESCRIPTION_CHAPTERS","value":{"chapters":[
+	{"chapterRenderer":{"title":{"simpleText":"Intro"},"timeRangeStartMillis":0}},
+	{"chapterRenderer":{"title":{"simpleText":"Side A/1"},"timeRangeStartMillis":95000}}]}},
+{"key":"AUTO_CHAPTERS","value":{"chapters":[
+	{"chapterRenderer":{"title":{"simpleText":"Intro"},"timeRangeStartMillis":0}}]}}]}`
+
+func TestParseChapters(t *testing.T) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(chaptersData), &data); err != nil {
+		t.Fatal(err)
+	}
+	chapters := parseChapters(data)
+	if len(chapters) != 2 || chapters[0].Title != "Intro" || chapters[0].End != 95*time.Second ||
+		chapters[1].Start != 95*time.Second || chapters[1].End != 0 {
+		t.Errorf("Wrong chapters: %+v", chapters)
+	}
+}
+
+func TestSplitChapters(t *testing.T) {
+	y := NewYoutube(false)
+	y.FFmpegPath = fakeFFmpeg(t)
+	y.Chapters = []Chapter{{Title: "Intro", End: time.Minute}, {Title: "Side A/1", Start: time.Minute}}
+	dir := t.TempDir()
+	src := filepath.Join(dir, "album.m4a")
+	ioutil.WriteFile(src, []byte("audio"), 0644)
+
+	_, files, err := y.splitChapters(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0] != filepath.Join(dir, "album - 01 - Intro.m4a") || files[1] != filepath.Join(dir, "album - 02 - Side A_1.m4a") {
+		t.Fatalf("Wrong chapter files: %v", files)
+	}
+
+	y.ChapterTemplate = "{index}. {chapter}{ext}"
+	_, files, err = y.splitChapters(src)
+	if err != nil || files[1] != filepath.Join(dir, "02. Side A_1.m4a") {
+		t.Errorf("Wrong templated files: %v, err=%v", files, err)
+	}
+	if data, _ := ioutil.ReadFile(files[1]); string(data) != "audio" {
+		t.Errorf("Wrong chapter content: %s", data)
+	}
+}
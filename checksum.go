@@ -0,0 +1,68 @@
+package youtube
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//checksumExt is the extension of the sidecar, in the format of sha256sum.
+const checksumExt = ".sha256"
+
+//fileSHA256 is the hex sha256 of the file content.
+func fileSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+//writeChecksum writes the .sha256 sidecar of the file, checked with
+// "sha256sum -c" from its directory.
+func (y *Youtube) writeChecksum(file string) (string, error) {
+	sum, err := fileSHA256(file)
+	if err != nil {
+		return "", fmt.Errorf("checksum of file=%s failed, error=%s", file, err)
+	}
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(file))
+	if err = ioutil.WriteFile(file+checksumExt, []byte(line), 0644); err != nil {
+		return "", fmt.Errorf("write checksum of file=%s failed, error=%s", file, err)
+	}
+	y.log(fmt.Sprintf("sha256 %s %s", sum, file))
+	return sum, nil
+}
+
+//writeManifest writes the checksums of the downloaded items in the format of
+//sha256sum, the paths are relative to the manifest directory when possible.
+func (b *Batch) writeManifest(items []*BatchItem) error {
+	dir := filepath.Dir(b.ManifestFile)
+	var lines strings.Builder
+	for _, item := range items {
+		if item.Err != nil || item.Path == "" {
+			continue
+		}
+		if item.SHA256 == "" {
+			sum, err := fileSHA256(item.Path)
+			if err != nil {
+				return fmt.Errorf("checksum of file=%s failed, error=%s", item.Path, err)
+			}
+			item.SHA256 = sum
+		}
+		name := item.Path
+		if rel, err := filepath.Rel(dir, name); err == nil && !strings.HasPrefix(rel, "..") {
+			name = rel
+		}
+		fmt.Fprintf(&lines, "%s  %s\n", item.SHA256, filepath.ToSlash(name))
+	}
+	return ioutil.WriteFile(b.ManifestFile, []byte(lines.String()), 0644)
+}
@@ -0,0 +1,58 @@
+package youtube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//videoDataSHA256 is the sha256 of "video data", served by existsServer.
+const videoDataSHA256 = "a37684ccb4710846dfe2f0ec8239ee3f36b5cacc1d7c917fb20984e5fd7d3de9"
+
+func TestChecksumSidecar(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	dest := filepath.Join(t.TempDir(), "dl.mp4")
+
+	y := NewYoutube(false)
+	y.Checksum = true
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	result, err := y.Download(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SHA256 != videoDataSHA256 {
+		t.Errorf("got sha256 %s", result.SHA256)
+	}
+	b, _ := ioutil.ReadFile(dest + checksumExt)
+	if want := videoDataSHA256 + "  dl.mp4\n"; string(b) != want {
+		t.Errorf("sidecar contains %q, want %q", b, want)
+	}
+}
+
+func TestBatchManifest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "videos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	done := filepath.Join(dir, "videos", "a.mp4")
+	if err := ioutil.WriteFile(done, []byte("video data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewBatch(1, false)
+	b.ManifestFile = filepath.Join(dir, "manifest.sha256")
+	items := []*BatchItem{
+		{Path: done},
+		{Path: filepath.Join(dir, "b.mp4"), Err: fmt.Errorf("failed")},
+	}
+	if err := b.writeManifest(items); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := ioutil.ReadFile(b.ManifestFile)
+	if want := videoDataSHA256 + "  videos/a.mp4\n"; string(got) != want {
+		t.Errorf("manifest contains %q, want %q", got, want)
+	}
+}
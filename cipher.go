@@ -0,0 +1,311 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//CipherExtractor : A strategy finding the signature transformation in the player
+//javascript, set in CipherExtractors to patch a player change without forking.
+//The returned function turns the "s" parameter of a signature cipher into the
+//signature. No javascript engine is built in, the module only depends on the
+//standard library. The "n" throttling parameter of the stream urls is out of
+//scope: its transformation is a whole function of the player, not a sequence of
+//helper calls, and the urls keep it untransformed, so youtube may throttle them.
+type CipherExtractor interface {
+	Extract(js string) (decipher func(s string) string, err error)
+}
+
+//CipherExtractorFunc : Use a function as a CipherExtractor.
+type CipherExtractorFunc func(js string) (func(s string) string, error)
+
+//Extract : Call the function.
+func (f CipherExtractorFunc) Extract(js string) (func(s string) string, error) {
+	return f(js)
+}
+
+//RegexExtractor : Finds the decipher function and its helper object with regular expressions.
+type RegexExtractor struct{}
+
+//Extract : Match the player with the regular expressions.
+func (RegexExtractor) Extract(js string) (func(s string) string, error) {
+	ops, err := parseCipherOps(js)
+	if err != nil {
+		return nil, err
+	}
+	return opsDecipher(ops), nil
+}
+
+//ScanExtractor : Walks the player javascript matching the braces, so it doesn't
+//depend on the argument names, the spacing or the nesting of the helper methods.
+type ScanExtractor struct{}
+
+//Extract : Scan the player for the function splitting and joining its argument.
+func (ScanExtractor) Extract(js string) (func(s string) string, error) {
+	ops, err := scanCipherOps(js)
+	if err != nil {
+		return nil, err
+	}
+	return opsDecipher(ops), nil
+}
+
+//DefaultCipherExtractors : The strategies tried in order when CipherExtractors is empty.
+var DefaultCipherExtractors = []CipherExtractor{RegexExtractor{}, ScanExtractor{}}
+
+//extractDecipher tries the strategies in order and returns the first success.
+func (y *Youtube) extractDecipher(js string) (func(s string) string, error) {
+	extractors := y.CipherExtractors
+	if len(extractors) == 0 {
+		extractors = DefaultCipherExtractors
+	}
+	var errs []string
+	for _, e := range extractors {
+		decipher, err := e.Extract(js)
+		if err == nil {
+			y.log(fmt.Sprintf("Decipher extracted by %T", e))
+			return decipher, nil
+		}
+		errs = append(errs, fmt.Sprintf("%T: %s", e, err))
+	}
+	return nil, errors.New(strings.Join(errs, ", "))
+}
+
+func opsDecipher(ops []cipherOp) func(s string) string {
+	return func(s string) string {
+		return applyCipherOps(ops, s)
+	}
+}
+
+func scanCipherOps(js string) ([]cipherOp, error) {
+	body, arg, err := scanDecipherBody(js)
+	if err != nil {
+		return nil, err
+	}
+	var ops []cipherOp
+	var helper string
+	var methods map[string]string
+	for _, stmt := range strings.Split(body, ";") {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" || strings.HasPrefix(stmt, arg+"=") || strings.HasPrefix(stmt, "return") {
+			continue
+		}
+		obj, method, n, ok := parseHelperCall(stmt, arg)
+		if !ok {
+			return nil, fmt.Errorf("unknown decipher statement '%s'", stmt)
+		}
+		if helper == "" {
+			helper = obj
+			if methods, err = scanHelperObject(js, helper); err != nil {
+				return nil, err
+			}
+		}
+		kind, ok := methods[method]
+		if obj != helper || !ok {
+			return nil, fmt.Errorf("unknown decipher method '%s.%s'", obj, method)
+		}
+		ops = append(ops, cipherOp{kind: kind, arg: n})
+	}
+	if len(ops) == 0 {
+		return nil, errors.New("no decipher step found")
+	}
+	return ops, nil
+}
+
+//scanDecipherBody finds the function whose body starts by splitting its argument
+//and returns the body and the argument name.
+func scanDecipherBody(js string) (string, string, error) {
+	const split = `.split("")`
+	for from := 0; ; {
+		i := strings.Index(js[from:], split)
+		if i < 0 {
+			return "", "", errors.New("no decipher function found")
+		}
+		i += from
+		from = i + len(split)
+		//arg=arg.split("") right after the opening brace of function(arg){
+		arg := identBefore(js, i)
+		assign := i - len(arg) - 1 - len(arg)
+		if arg == "" || assign < 1 || js[assign:i-len(arg)] != arg+"=" {
+			continue
+		}
+		open := strings.LastIndex(js[:assign], "{")
+		if open < 0 || strings.TrimSpace(js[open+1:assign]) != "" {
+			continue
+		}
+		head := strings.TrimSpace(js[:open])
+		if !strings.HasSuffix(strings.Replace(head, " ", "", -1), "function("+arg+")") {
+			continue
+		}
+		end := matchBrace(js, open)
+		if end < 0 {
+			return "", "", errors.New("unterminated decipher function")
+		}
+		body := js[open+1 : end]
+		if !strings.Contains(body, arg+`.join("")`) {
+			continue
+		}
+		return body, arg, nil
+	}
+}
+
+//parseHelperCall parses obj.method(arg,n) and obj["method"](arg,n).
+func parseHelperCall(stmt, arg string) (obj, method string, n int, ok bool) {
+	open := strings.Index(stmt, "(")
+	if open < 0 || !strings.HasSuffix(stmt, ")") {
+		return "", "", 0, false
+	}
+	callee := stmt[:open]
+	params := strings.Split(stmt[open+1:len(stmt)-1], ",")
+	if len(params) != 2 || strings.TrimSpace(params[0]) != arg {
+		return "", "", 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(params[1]))
+	if err != nil {
+		return "", "", 0, false
+	}
+	if i := strings.Index(callee, "["); i > 0 && strings.HasSuffix(callee, "]") {
+		obj, method = callee[:i], strings.Trim(callee[i+1:len(callee)-1], `"'`)
+	} else if i := strings.Index(callee, "."); i > 0 {
+		obj, method = callee[:i], callee[i+1:]
+	} else {
+		return "", "", 0, false
+	}
+	return obj, method, n, true
+}
+
+//scanHelperObject classifies the methods of the object literal assigned to name.
+func scanHelperObject(js, name string) (map[string]string, error) {
+	obj, err := helperObjectLiteral(js, name)
+	if err != nil {
+		return nil, err
+	}
+	return scanMethods(obj[1 : len(obj)-1]), nil
+}
+
+//helperObjectLiteral returns the {..} object literal assigned to name.
+func helperObjectLiteral(js, name string) (string, error) {
+	for from := 0; ; {
+		i := strings.Index(js[from:], name)
+		if i < 0 {
+			return "", fmt.Errorf("no decipher helper object '%s' found", name)
+		}
+		i += from
+		from = i + len(name)
+		if i > 0 && isIdentByte(js[i-1]) {
+			continue
+		}
+		rest := strings.TrimLeft(js[i+len(name):], " \t\n")
+		if !strings.HasPrefix(rest, "=") {
+			continue
+		}
+		rest = strings.TrimLeft(rest[1:], " \t\n")
+		if !strings.HasPrefix(rest, "{") {
+			continue
+		}
+		open := len(js) - len(rest)
+		end := matchBrace(js, open)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated decipher helper object '%s'", name)
+		}
+		return js[open : end+1], nil
+	}
+}
+
+//scanMethods splits name:function(..){..} entries, whatever their bodies hold.
+func scanMethods(obj string) map[string]string {
+	methods := make(map[string]string)
+	for i := 0; i < len(obj); {
+		colon := strings.Index(obj[i:], ":")
+		if colon < 0 {
+			break
+		}
+		name := strings.Trim(strings.TrimSpace(strings.TrimLeft(obj[i:i+colon], ", \t\n")), `"'`)
+		open := strings.Index(obj[i+colon:], "{")
+		if open < 0 {
+			break
+		}
+		open += i + colon
+		end := matchBrace(obj, open)
+		if end < 0 {
+			break
+		}
+		body := obj[open+1 : end]
+		switch {
+		case strings.Contains(body, "reverse"):
+			methods[name] = "reverse"
+		case strings.Contains(body, "splice"):
+			methods[name] = "splice"
+		default:
+			methods[name] = "swap"
+		}
+		i = end + 1
+	}
+	return methods
+}
+
+//matchBrace returns the index of the brace closing the one at open, skipping
+//the string literals, or -1.
+func matchBrace(s string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+//identBefore returns the javascript identifier ending at i.
+func identBefore(s string, i int) string {
+	start := i
+	for start > 0 && isIdentByte(s[start-1]) {
+		start--
+	}
+	return s[start:i]
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+//DecipherSource : Standalone javascript declaring the helper object and evaluating
+//to the decipher function of the player. No engine is built in, a CipherExtractor
+//running one, like goja, evaluates it; see the README. The engine should run it
+//once in Extract, so that a failure is an error of Extract rather than a wrong
+//signature.
+func DecipherSource(js string) (string, error) {
+	body, arg, err := scanDecipherBody(js)
+	if err != nil {
+		return "", err
+	}
+	for _, stmt := range strings.Split(body, ";") {
+		obj, _, _, ok := parseHelperCall(strings.TrimSpace(stmt), arg)
+		if !ok {
+			continue
+		}
+		literal, err := helperObjectLiteral(js, obj)
+		if err != nil {
+			return "", err
+		}
+		return "var " + obj + "=" + literal + ";(function(" + arg + "){" + body + "})", nil
+	}
+	return "", errors.New("no decipher step found")
+}
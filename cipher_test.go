@@ -0,0 +1,65 @@
+package youtube
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+//reformattedPlayerJS is playerJS with another argument name, spacing and a
+//nested block that the regular expressions miss.
+const reformattedPlayerJS = `var Xy = {
+	Ab: function (a) { a.reverse() },
+	"cD": function (a, b) { a.splice(0, b) },
+	e$: function (a, b) { if (b) { var c = a[0]; a[0] = a[b % a.length]; a[b % a.length] = c } }
+};
+Qa = function (sig) {sig=sig.split(""); Xy.e$(sig, 3); Xy["Ab"](sig, 12); Xy.cD(sig, 2); return sig.join("")};`
+
+func TestScanExtractor(t *testing.T) {
+	for _, js := range []string{playerJS, reformattedPlayerJS} {
+		decipher, err := ScanExtractor{}.Extract(js)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig := decipher("abcdefg"); sig != "eacbd" {
+			t.Errorf("Wrong signature: %s", sig)
+		}
+	}
+	if _, err := (RegexExtractor{}).Extract(reformattedPlayerJS); err == nil {
+		t.Error("The regular expressions should miss the reformatted player")
+	}
+}
+
+func TestExtractDecipherOrder(t *testing.T) {
+	y := NewYoutube(false)
+	decipher, err := y.extractDecipher(reformattedPlayerJS)
+	if err != nil || decipher("abcdefg") != "eacbd" {
+		t.Errorf("The default strategies failed: %v", err)
+	}
+
+	y.CipherExtractors = []CipherExtractor{
+		CipherExtractorFunc(func(js string) (func(string) string, error) {
+			return nil, errors.New("broken")
+		}),
+		CipherExtractorFunc(func(js string) (func(string) string, error) {
+			return strings.ToUpper, nil
+		}),
+	}
+	if decipher, err = y.extractDecipher(playerJS); err != nil || decipher("abc") != "ABC" {
+		t.Errorf("The custom strategy wasn't used: %v", err)
+	}
+	y.CipherExtractors = y.CipherExtractors[:1]
+	if _, err = y.extractDecipher(playerJS); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Wrong error %v", err)
+	}
+}
+
+func TestDecipherSource(t *testing.T) {
+	src, err := DecipherSource(reformattedPlayerJS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(src, "var Xy={") || !strings.HasSuffix(src, `return sig.join("")})`) {
+		t.Errorf("Wrong source: %s", src)
+	}
+}
@@ -0,0 +1,215 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//Timeouts : Timeouts of the http operations, zero means no timeout.
+type Timeouts struct {
+	//Dial limits establishing the tcp connection.
+	Dial time.Duration
+	//TLSHandshake limits the tls handshake.
+	TLSHandshake time.Duration
+	//ResponseHeader limits waiting for the response headers once the request is sent.
+	ResponseHeader time.Duration
+	//Request limits a whole video information request, body included.
+	//It doesn't apply to video downloads, which may take very long.
+	Request time.Duration
+	//Idle aborts a video download when no data is received for this long.
+	Idle time.Duration
+}
+
+//DefaultTimeouts : Timeouts used by NewYoutube.
+var DefaultTimeouts = Timeouts{
+	Dial:           30 * time.Second,
+	TLSHandshake:   10 * time.Second,
+	ResponseHeader: 30 * time.Second,
+	Request:        time.Minute,
+	Idle:           time.Minute,
+}
+
+//sharedLocks guard the state built on first use, which concurrent requests like
+//those of StreamHandler share. The copies of a Youtube share them too.
+type sharedLocks struct {
+	client   sync.Mutex
+	decipher sync.Mutex
+}
+
+//getClient builds the http client on first use, so options must be set before it.
+func (y *Youtube) getClient() *http.Client {
+	y.locks.client.Lock()
+	defer y.locks.client.Unlock()
+	if y.client != nil {
+		return y.client
+	}
+	dialer := &net.Dialer{Timeout: y.Timeouts.Dial}
+	var transport http.RoundTripper = &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			switch {
+			case y.ForceIPv4:
+				network = "tcp4"
+			case y.ForceIPv6:
+				network = "tcp6"
+			}
+			d := *dialer
+			if y.SourceAddress != "" {
+				ip, err := sourceIP(y.SourceAddress, network == "tcp6")
+				if err != nil {
+					return nil, err
+				}
+				d.LocalAddr = &net.TCPAddr{IP: ip}
+				if ip.To4() != nil {
+					network = "tcp4"
+				} else {
+					network = "tcp6"
+				}
+			}
+			var conn net.Conn
+			var err error
+			if y.Resolver != nil {
+				conn, err = y.dialResolved(ctx, &d, network, addr)
+			} else {
+				conn, err = d.DialContext(ctx, network, addr)
+			}
+			if err != nil {
+				return nil, err
+			}
+			y.log(fmt.Sprintf("Remote IP: %s", conn.RemoteAddr()))
+			return conn, nil
+		},
+		Proxy:                 y.proxy,
+		TLSHandshakeTimeout:   y.Timeouts.TLSHandshake,
+		ResponseHeaderTimeout: y.Timeouts.ResponseHeader,
+	}
+	if y.ConnHooks != nil {
+		transport = &hookTransport{base: transport, hooks: y.ConnHooks}
+	}
+	if y.ReplayFrom != nil {
+		transport = &replayTransport{fixture: y.ReplayFrom}
+	} else if y.RecordTo != nil {
+		transport = &recordTransport{base: transport, fixture: y.RecordTo}
+	}
+	y.client = &http.Client{
+		Transport:     transport,
+		CheckRedirect: y.checkRedirect,
+	}
+	return y.client
+}
+
+//proxy returns the url of Proxy, an http, https or socks5 proxy, or the one of
+//the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func (y *Youtube) proxy(req *http.Request) (*url.URL, error) {
+	if y.Proxy == "" {
+		return http.ProxyFromEnvironment(req)
+	}
+	u, err := url.Parse(y.Proxy)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy '%s', expected a url like socks5://127.0.0.1:1080", y.Proxy)
+	}
+	return u, nil
+}
+
+//DefaultMaxRedirects : Redirects followed when MaxRedirects is zero. MaxRedirects
+//counts the redirects, not the requests: net/http stops after 10 requests, which
+//is 9 redirects. A negative MaxRedirects doesn't follow any redirect, the
+//redirect answer is returned.
+const DefaultMaxRedirects = 10
+
+//ErrTooManyRedirects : Returned when a request is redirected more than MaxRedirects times.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+func (y *Youtube) checkRedirect(req *http.Request, via []*http.Request) error {
+	if req.URL.Hostname() == "consent.youtube.com" {
+		return ErrConsentRequired
+	}
+	max := y.MaxRedirects
+	if max < 0 {
+		return http.ErrUseLastResponse
+	}
+	if max == 0 {
+		max = DefaultMaxRedirects
+	}
+	//via holds the first request and the redirects already followed
+	if len(via) > max {
+		return ErrTooManyRedirects
+	}
+	y.log(fmt.Sprintf("Redirect %d: %s -> %s", len(via), via[len(via)-1].URL.Host, req.URL.Host))
+	return nil
+}
+
+//ErrIdleTimeout : Returned when a download receives no data for Timeouts.Idle.
+var ErrIdleTimeout = errors.New("no data received within the idle timeout")
+
+//idleReader cancels the request when no data is read for the idle duration,
+//the read then fails with ErrIdleTimeout instead of the cancellation.
+type idleReader struct {
+	r       io.Reader
+	idle    time.Duration
+	timer   *time.Timer
+	expired int32
+}
+
+func newIdleReader(r io.Reader, idle time.Duration, cancel context.CancelFunc) *idleReader {
+	ir := &idleReader{r: r, idle: idle}
+	ir.timer = time.AfterFunc(idle, func() {
+		atomic.StoreInt32(&ir.expired, 1)
+		cancel()
+	})
+	return ir
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.idle)
+	}
+	if err != nil {
+		r.timer.Stop()
+		if err != io.EOF && atomic.LoadInt32(&r.expired) == 1 {
+			err = ErrIdleTimeout
+		}
+	}
+	return n, err
+}
+
+//sourceIP resolves the source address option, an ip or the name of a network
+//interface, in which case its first address is used, ipv6 when preferred.
+func sourceIP(source string, preferIPv6 bool) (net.IP, error) {
+	if ip := net.ParseIP(source); ip != nil {
+		return ip, nil
+	}
+	iface, err := net.InterfaceByName(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source address '%s': %s", source, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	var found net.IP
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok || ipnet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if (ipnet.IP.To4() == nil) == preferIPv6 {
+			return ipnet.IP, nil
+		}
+		if found == nil {
+			found = ipnet.IP
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no address on interface '%s'", source)
+	}
+	return found, nil
+}
@@ -0,0 +1,145 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdleReaderCancel(t *testing.T) {
+	pr, pw := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := newIdleReader(pr, 10*time.Millisecond, func() {
+		cancel()
+		pw.CloseWithError(context.Canceled)
+	})
+	go pw.Write([]byte("data"))
+	if _, err := r.Read(make([]byte, 4)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(make([]byte, 4)); err != ErrIdleTimeout {
+		t.Errorf("Stalled read should time out, err=%v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("Context should be canceled")
+	}
+}
+
+func TestIdleTimeoutDownload(t *testing.T) {
+	stall := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "10")
+		w.Write([]byte("video"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-stall:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(stall)
+
+	y := NewYoutube(false)
+	y.Timeouts.Idle = 20 * time.Millisecond
+	err := y.videoDLWorker(filepath.Join(t.TempDir(), "dl.mp4"), ts.URL)
+	if !errors.Is(err, ErrIdleTimeout) {
+		t.Errorf("Stalled download should time out, err=%v", err)
+	}
+}
+
+func TestRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/final":
+			w.Write([]byte("video"))
+		case "/start":
+			http.Redirect(w, r, "/middle", http.StatusFound)
+		default:
+			http.Redirect(w, r, "/final", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	if err := y.videoDLWorker(filepath.Join(t.TempDir(), "dl.mp4"), ts.URL+"/start"); err != nil {
+		t.Fatal(err)
+	}
+	if y.FinalURL != ts.URL+"/final" {
+		t.Errorf("Wrong final url: %s", y.FinalURL)
+	}
+
+	y = NewYoutube(false)
+	y.MaxRedirects = 2
+	if err := y.videoDLWorker(filepath.Join(t.TempDir(), "dl.mp4"), ts.URL+"/start"); err != nil {
+		t.Errorf("2 redirects should be followed, err=%v", err)
+	}
+	y.MaxRedirects = 1
+	err := y.videoDLWorker(filepath.Join(t.TempDir(), "dl.mp4"), ts.URL+"/start")
+	if uerr, ok := err.(*url.Error); !ok || uerr.Err != ErrTooManyRedirects {
+		t.Errorf("The second redirect should be refused, err=%v", err)
+	}
+
+	y = NewYoutube(false)
+	y.MaxRedirects = -1
+	req, _ := http.NewRequest("GET", ts.URL+"/start", nil)
+	resp, err := y.do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/middle" {
+		t.Errorf("The redirect answer should be returned: %d %s", resp.StatusCode, resp.Header.Get("Location"))
+	}
+}
+
+func TestSourceAddress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.RemoteAddr))
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.ForceIPv4 = true
+	y.SourceAddress = "lo"
+	body, err := y.fetch(ts.URL)
+	if err != nil {
+		t.Skip("no loopback interface:", err)
+	}
+	if !strings.HasPrefix(string(body), "127.") {
+		t.Errorf("Wrong source address: %s", body)
+	}
+
+	y = NewYoutube(false)
+	y.SourceAddress = "no-such-interface0"
+	if _, err := y.fetch(ts.URL); err == nil {
+		t.Error("Unknown interface should fail")
+	}
+}
+
+func TestProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//a proxy gets the absolute url
+		w.Write([]byte(r.URL.String()))
+	}))
+	defer proxy.Close()
+
+	y := NewYoutube(false)
+	y.Proxy = proxy.URL
+	body, err := y.fetch("http://www.youtube.com/iframe_api")
+	if err != nil || string(body) != "http://www.youtube.com/iframe_api" {
+		t.Errorf("The request didn't go through the proxy: %s, err=%v", body, err)
+	}
+
+	y = NewYoutube(false)
+	y.Proxy = "127.0.0.1:1080"
+	if _, err := y.fetch("http://www.youtube.com/iframe_api"); err == nil || !strings.Contains(err.Error(), "invalid proxy") {
+		t.Errorf("got error %v, want an invalid proxy", err)
+	}
+}
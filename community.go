@@ -0,0 +1,65 @@
+package youtube
+
+import "fmt"
+
+//PollChoice : One choice of a community poll.
+type PollChoice struct {
+	Text string
+	//VotePercentage is the displayed percentage, only visible once voted.
+	VotePercentage string
+}
+
+//CommunityPost : A post of the channel community tab.
+type CommunityPost struct {
+	ID         string
+	Text       string
+	Published  string
+	LikeCount  int64
+	ImageURLs  []string
+	Poll       []PollChoice
+	TotalVotes int64
+}
+
+//GetCommunityPosts : Retrieval the posts on the first page of the channel community tab.
+func (y *Youtube) GetCommunityPosts(channelID string) ([]CommunityPost, error) {
+	data, err := y.fetchInitialData(channelURL(channelID) + "/community")
+	if err != nil {
+		return nil, err
+	}
+	posts := parseCommunityPosts(data)
+	y.log(fmt.Sprintf("Found %d community posts", len(posts)))
+	return posts, nil
+}
+
+func parseCommunityPosts(data interface{}) []CommunityPost {
+	var posts []CommunityPost
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		if key != "backstagePostRenderer" {
+			return
+		}
+		p := CommunityPost{
+			Text:      jsonText(v["contentText"]),
+			Published: jsonText(v["publishedTimeText"]),
+			LikeCount: parseCount(jsonText(v["voteCount"])),
+		}
+		p.ID, _ = v["postId"].(string)
+		walkJSON(v["backstageAttachment"], func(key string, v map[string]interface{}) {
+			switch key {
+			case "backstageImageRenderer":
+				p.ImageURLs = append(p.ImageURLs, lastThumbnail(v["image"]))
+			case "pollRenderer":
+				p.TotalVotes = parseCount(jsonText(v["totalVotes"]))
+				choices, _ := v["choices"].([]interface{})
+				for _, c := range choices {
+					cm, _ := c.(map[string]interface{})
+					p.Poll = append(p.Poll, PollChoice{
+						Text:           jsonText(cm["text"]),
+						VotePercentage: jsonText(cm["votePercentage"]),
+					})
+				}
+			}
+		})
+		posts = append(posts, p)
+	})
+	return posts
+}
@@ -0,0 +1,29 @@
+package youtube
+
+import "testing"
+
+const communityPage = `<script>var ytInitialData = {"contents":[
+{"backstagePostThreadRenderer":{"post":{"backstagePostRenderer":{"postId":"Ugkx1","contentText":{"runs":[{"text":"New talk "},{"text":"online"}]},
+"publishedTimeText":{"runs":[{"text":"2 days ago"}]},"voteCount":{"simpleText":"1.5K"},
+"backstageAttachment":{"postMultiImageRenderer":{"images":[{"backstageImageRenderer":{"image":{"thumbnails":[{"url":"https://yt3.ggpht.com/1"}]}}},
+{"backstageImageRenderer":{"image":{"thumbnails":[{"url":"https://yt3.ggpht.com/2"}]}}}]}}}}}},
+{"backstagePostThreadRenderer":{"post":{"backstagePostRenderer":{"postId":"Ugkx2","contentText":{"runs":[{"text":"Vote"}]},
+"backstageAttachment":{"pollRenderer":{"totalVotes":{"simpleText":"300 votes"},"choices":[
+{"text":{"runs":[{"text":"Go"}]},"votePercentage":{"simpleText":"90%"}},{"text":{"runs":[{"text":"Rust"}]},"votePercentage":{"simpleText":"10%"}}]}}}}}}]};</script>`
+
+func TestParseCommunityPosts(t *testing.T) {
+	data, err := extractInitialData([]byte(communityPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts := parseCommunityPosts(data)
+	if len(posts) != 2 {
+		t.Fatalf("Wrong posts: %+v", posts)
+	}
+	if p := posts[0]; p.ID != "Ugkx1" || p.Text != "New talk online" || p.LikeCount != 1500 || len(p.ImageURLs) != 2 {
+		t.Errorf("Wrong image post: %+v", p)
+	}
+	if p := posts[1]; p.TotalVotes != 300 || len(p.Poll) != 2 || p.Poll[0].Text != "Go" || p.Poll[0].VotePercentage != "90%" {
+		t.Errorf("Wrong poll post: %+v", p)
+	}
+}
@@ -0,0 +1,49 @@
+package youtube
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+//consentCookies accept the consent.youtube.com interstitial shown in the EU,
+//SOCS is the current cookie and CONSENT the older one.
+var consentCookies = []*http.Cookie{
+	{Name: "SOCS", Value: "CAI"},
+	{Name: "CONSENT", Value: "YES+cb"},
+}
+
+//ErrConsentRequired : Returned when youtube still redirects to its consent page
+//despite the consent cookies.
+var ErrConsentRequired = errors.New("redirected to consent.youtube.com, the consent cookies were refused")
+
+//setCookies adds the Cookies whose domain matches the host of the request, the
+//cookies without a domain go to youtube.com.
+func (y *Youtube) setCookies(req *http.Request) {
+	host := req.URL.Hostname()
+	for _, c := range y.Cookies {
+		domain := strings.TrimPrefix(c.Domain, ".")
+		if domain == "" {
+			domain = "youtube.com"
+		}
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			continue
+		}
+		if _, err := req.Cookie(c.Name); err == http.ErrNoCookie {
+			req.AddCookie(&http.Cookie{Name: c.Name, Value: c.Value})
+		}
+	}
+}
+
+//setConsentCookies adds the consent cookies to the youtube requests which
+//don't have them yet.
+func setConsentCookies(req *http.Request) {
+	if !isYoutubeHost(req.URL.Hostname()) {
+		return
+	}
+	for _, c := range consentCookies {
+		if _, err := req.Cookie(c.Name); err == http.ErrNoCookie {
+			req.AddCookie(c)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package youtube
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestConsentCookies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("SOCS"); err != nil || c.Value != "CAI" {
+			http.Redirect(w, r, "https://consent.youtube.com/m?continue=https://www.youtube.com/", http.StatusFound)
+			return
+		}
+		w.Write([]byte("watch page"))
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+
+	y := NewYoutube(false)
+	y.client = &http.Client{Transport: hostRedirect{u}, CheckRedirect: y.checkRedirect}
+	body, err := y.fetch("https://www.youtube.com/watch?v=rFejpH_tAHM")
+	if err != nil || string(body) != "watch page" {
+		t.Errorf("Got %q, error %v", body, err)
+	}
+
+	//other hosts don't get the cookies
+	if _, err := y.fetch("https://example.com/"); !errors.Is(err, ErrConsentRequired) {
+		t.Errorf("got error %v, want %v", err, ErrConsentRequired)
+	}
+}
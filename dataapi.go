@@ -0,0 +1,189 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var dataAPIURL = "https://www.googleapis.com/youtube/v3/"
+
+//ErrQuotaExceeded : Returned by the Data API when the daily quota of APIKey is used up,
+//the metadata are then scraped from the pages until the Youtube is recreated.
+var ErrQuotaExceeded = errors.New("youtube data api quota exceeded")
+
+//dataAPIPageSize is the maximum number of results of a Data API page.
+const dataAPIPageSize = 50
+
+//useDataAPI tells if the metadata should be asked to the official Data API.
+func (y *Youtube) useDataAPI() bool {
+	return y.APIKey != "" && !y.quotaExceeded
+}
+
+//dataAPIFallback logs why the Data API failed before scraping.
+func (y *Youtube) dataAPIFallback(err error) {
+	if errors.Is(err, ErrQuotaExceeded) {
+		y.quotaExceeded = true
+	}
+	y.log(fmt.Sprintf("Data API failed, scraping instead, error=%s", err))
+}
+
+//dataAPI gets the resource, e.g. "playlistItems", and decodes its json answer.
+func (y *Youtube) dataAPI(ctx context.Context, resource string, params url.Values, answer interface{}) error {
+	params.Set("key", y.APIKey)
+	req, err := http.NewRequest("GET", dataAPIURL+resource+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := y.do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+				Errors  []struct {
+					Reason string `json:"reason"`
+				} `json:"errors"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &apiErr)
+		for _, e := range apiErr.Error.Errors {
+			if e.Reason == "quotaExceeded" || e.Reason == "dailyLimitExceeded" {
+				return ErrQuotaExceeded
+			}
+		}
+		return fmt.Errorf("data api status code %d: %s", resp.StatusCode, apiErr.Error.Message)
+	}
+	return json.Unmarshal(body, answer)
+}
+
+//apiPlaylist gets the playlist title and every page of its items.
+func (y *Youtube) apiPlaylist(ctx context.Context, playlistID string) (*Playlist, error) {
+	var playlists struct {
+		Items []struct {
+			Snippet struct {
+				Title string `json:"title"`
+			} `json:"snippet"`
+		} `json:"items"`
+	}
+	err := y.dataAPI(ctx, "playlists", url.Values{"part": {"snippet"}, "id": {playlistID}}, &playlists)
+	if err != nil {
+		return nil, err
+	}
+	if len(playlists.Items) == 0 {
+		return nil, fmt.Errorf("playlist %s not found", playlistID)
+	}
+	p := &Playlist{
+		ID:      playlistID,
+		Title:   playlists.Items[0].Snippet.Title,
+		IsAlbum: strings.HasPrefix(playlistID, albumPlaylistPrefix),
+	}
+	var token string
+	for pages := 1; ; pages++ {
+		params := url.Values{
+			"part":       {"snippet"},
+			"playlistId": {playlistID},
+			"maxResults": {strconv.Itoa(dataAPIPageSize)},
+		}
+		if token != "" {
+			params.Set("pageToken", token)
+		}
+		var items struct {
+			NextPageToken string `json:"nextPageToken"`
+			Items         []struct {
+				Snippet struct {
+					Title      string `json:"title"`
+					ResourceID struct {
+						VideoID string `json:"videoId"`
+					} `json:"resourceId"`
+				} `json:"snippet"`
+			} `json:"items"`
+		}
+		if err := y.dataAPI(ctx, "playlistItems", params, &items); err != nil {
+			return nil, fmt.Errorf("playlist page %d: %w", pages, err)
+		}
+		for _, it := range items.Items {
+			p.Videos = append(p.Videos, PlaylistEntry{
+				ID:    it.Snippet.ResourceID.VideoID,
+				Title: it.Snippet.Title,
+				Index: len(p.Videos) + 1,
+			})
+		}
+		if y.OnPlaylistProgress != nil {
+			y.OnPlaylistProgress(PlaylistProgress{PlaylistID: playlistID, Pages: pages, Videos: len(p.Videos)})
+		}
+		if token = items.NextPageToken; token == "" {
+			break
+		}
+	}
+	if len(p.Videos) == 0 {
+		return nil, errors.New("no video found in the playlist")
+	}
+	return p, nil
+}
+
+//apiChannelInfo gets the channel of an id or handle. The api doesn't give the links.
+func (y *Youtube) apiChannelInfo(ctx context.Context, channelID string) (*ChannelInfo, error) {
+	params := url.Values{"part": {"snippet,statistics,brandingSettings"}}
+	if strings.HasPrefix(channelID, "@") {
+		params.Set("forHandle", channelID)
+	} else {
+		params.Set("id", channelID)
+	}
+	var channels struct {
+		Items []struct {
+			ID      string `json:"id"`
+			Snippet struct {
+				Title       string `json:"title"`
+				Description string `json:"description"`
+				Thumbnails  map[string]struct {
+					URL string `json:"url"`
+				} `json:"thumbnails"`
+			} `json:"snippet"`
+			Statistics struct {
+				SubscriberCount string `json:"subscriberCount"`
+				ViewCount       string `json:"viewCount"`
+			} `json:"statistics"`
+			BrandingSettings struct {
+				Image struct {
+					BannerExternalURL string `json:"bannerExternalUrl"`
+				} `json:"image"`
+			} `json:"brandingSettings"`
+		} `json:"items"`
+	}
+	if err := y.dataAPI(ctx, "channels", params, &channels); err != nil {
+		return nil, err
+	}
+	if len(channels.Items) == 0 {
+		return nil, fmt.Errorf("channel %s not found", channelID)
+	}
+	c := channels.Items[0]
+	info := &ChannelInfo{
+		ID:          c.ID,
+		Title:       c.Snippet.Title,
+		Description: c.Snippet.Description,
+		BannerURL:   c.BrandingSettings.Image.BannerExternalURL,
+	}
+	info.SubscriberCount, _ = strconv.ParseInt(c.Statistics.SubscriberCount, 10, 64)
+	info.ViewCount, _ = strconv.ParseInt(c.Statistics.ViewCount, 10, 64)
+	for _, size := range []string{"high", "medium", "default"} {
+		if t, ok := c.Snippet.Thumbnails[size]; ok {
+			info.AvatarURL = t.URL
+			break
+		}
+	}
+	return info, nil
+}
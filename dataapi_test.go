@@ -0,0 +1,92 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func dataAPIServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("key") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":{"message":"quota","errors":[{"reason":"quotaExceeded"}]}}`)
+			return
+		}
+		switch r.URL.Path {
+		case "/playlists":
+			fmt.Fprint(w, `{"items":[{"snippet":{"title":"Mix"}}]}`)
+		case "/playlistItems":
+			if q.Get("pageToken") == "" {
+				fmt.Fprint(w, `{"nextPageToken":"p2","items":[{"snippet":{"title":"One","resourceId":{"videoId":"aaaaaaaaaaA"}}}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"items":[{"snippet":{"title":"Two","resourceId":{"videoId":"bbbbbbbbbbA"}}}]}`)
+		case "/channels":
+			if q.Get("forHandle") != "@name" {
+				t.Errorf("Wrong channel query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"items":[{"id":"UC123","snippet":{"title":"Name","thumbnails":{"default":{"url":"small"},"high":{"url":"big"}}},
+"statistics":{"subscriberCount":"1200","viewCount":"5000"}}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestDataAPIPlaylist(t *testing.T) {
+	ts := dataAPIServer(t)
+	defer ts.Close()
+	defer func(u string) { dataAPIURL = u }(dataAPIURL)
+	dataAPIURL = ts.URL + "/"
+
+	y := NewYoutube(false)
+	y.APIKey = "secret"
+	var pages int
+	y.OnPlaylistProgress = func(p PlaylistProgress) { pages = p.Pages }
+	p, err := y.apiPlaylist(context.Background(), "PLaaaaaaaaaaaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Title != "Mix" || len(p.Videos) != 2 || p.Videos[1].ID != "bbbbbbbbbbA" || p.Videos[1].Index != 2 || pages != 2 {
+		t.Errorf("Wrong playlist %+v after %d pages", p, pages)
+	}
+}
+
+func TestDataAPIChannel(t *testing.T) {
+	ts := dataAPIServer(t)
+	defer ts.Close()
+	defer func(u string) { dataAPIURL = u }(dataAPIURL)
+	dataAPIURL = ts.URL + "/"
+
+	y := NewYoutube(false)
+	y.APIKey = "secret"
+	info, err := y.apiChannelInfo(context.Background(), "@name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ID != "UC123" || info.SubscriberCount != 1200 || info.ViewCount != 5000 || info.AvatarURL != "big" {
+		t.Errorf("Wrong channel %+v", info)
+	}
+}
+
+func TestDataAPIQuota(t *testing.T) {
+	ts := dataAPIServer(t)
+	defer ts.Close()
+	defer func(u string) { dataAPIURL = u }(dataAPIURL)
+	dataAPIURL = ts.URL + "/"
+
+	y := NewYoutube(false)
+	y.APIKey = "exhausted"
+	_, err := y.apiPlaylist(context.Background(), "PLaaaaaaaaaaaa")
+	if err != ErrQuotaExceeded {
+		t.Fatalf("got error %v, want %v", err, ErrQuotaExceeded)
+	}
+	y.dataAPIFallback(err)
+	if y.useDataAPI() {
+		t.Error("The Data API is still used after the quota is exceeded")
+	}
+}
@@ -0,0 +1,189 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//playerVersionTTL is how long the player version is reused without checking it.
+const playerVersionTTL = 6 * time.Hour
+
+var (
+	playerVersionRe = regexp.MustCompile(`player\\?/([0-9a-fA-F]{8})\\?/`)
+	//the decipher function splits the signature, calls the helper object
+	//methods on it and joins it back
+	decipherFuncRe = regexp.MustCompile(`[a-zA-Z0-9$_]+\s*=\s*function\(\s*a\s*\)\s*\{\s*a\s*=\s*a\.split\(\s*""\s*\)\s*;([^}]+)return\s+a\.join\(\s*""\s*\)`)
+	decipherCallRe = regexp.MustCompile(`([a-zA-Z0-9$_]+)(?:\.|\[")([a-zA-Z0-9$_]+)(?:"\])?\(\s*a\s*,\s*(\d+)\s*\)`)
+	helperMethodRe = regexp.MustCompile(`"?([a-zA-Z0-9$_]+)"?\s*:\s*function\(a(?:,b)?\)\{([^}]*)\}`)
+)
+
+//cipherOp is one step of the signature transformation.
+type cipherOp struct {
+	kind string
+	arg  int
+}
+
+//DecipherURL : Build the playable url of a signatureCipher ("s=..&sp=..&url=..").
+//The transformation is extracted from the current player javascript. The "n"
+//parameter isn't transformed, see CipherExtractor.
+func (y *Youtube) DecipherURL(signatureCipher string) (string, error) {
+	q, err := url.ParseQuery(signatureCipher)
+	if err != nil {
+		return "", err
+	}
+	target := q.Get("url")
+	if target == "" {
+		return "", errors.New("no url in the signature cipher")
+	}
+	s := q.Get("s")
+	if s == "" {
+		return target, nil
+	}
+	decipher, err := y.playerDecipher()
+	if err != nil {
+		return "", err
+	}
+	sp := q.Get("sp")
+	if sp == "" {
+		sp = "signature"
+	}
+	return setQueryParam(target, sp, decipher(s)), nil
+}
+
+//formatURL returns the url of the format, signed when it has a signature cipher.
+func (y *Youtube) formatURL(f Format) (string, error) {
+	if f.signatureCipher == "" {
+		return f.URL, nil
+	}
+	return y.DecipherURL(f.signatureCipher)
+}
+
+//streamURL returns the url of a stream of the list, signed through its format
+//since the url of the stream map lacks the signature of ciphered formats.
+func (y *Youtube) streamURL(s stream) (string, error) {
+	for _, f := range y.Formats {
+		if strconv.Itoa(f.Itag) == s["itag"] {
+			return y.formatURL(f)
+		}
+	}
+	return s["url"], nil
+}
+
+//playerDecipher returns the transformation of the current player, cached per player
+//version, the player javascript being kept in the cache directory. The version is
+//checked again after playerVersionTTL or a forbidden stream.
+func (y *Youtube) playerDecipher() (func(s string) string, error) {
+	y.locks.decipher.Lock()
+	defer y.locks.decipher.Unlock()
+	if y.decipher != nil && time.Since(y.playerVersionAt) < playerVersionTTL {
+		return y.decipher, nil
+	}
+	body, err := y.fetch("https://www.youtube.com/iframe_api")
+	if err != nil {
+		return nil, err
+	}
+	subs := playerVersionRe.FindSubmatch(body)
+	if subs == nil {
+		return nil, errors.New("no player version found")
+	}
+	version := string(subs[1])
+	if y.playerVersion == version && y.decipher != nil {
+		y.playerVersionAt = time.Now()
+		return y.decipher, nil
+	}
+	span := y.startSpan("youtube.decipher", "player", version)
+	cached := filepath.Join("player", version+".js")
+	js := y.readCache(cached)
+	if js == nil {
+		if js, err = y.fetch("https://www.youtube.com/s/player/" + version + "/player_ias.vflset/en_US/base.js"); err != nil {
+			span.end(err)
+			return nil, err
+		}
+		y.writeCache(cached, js)
+	}
+	decipher, err := y.extractDecipher(string(js))
+	span.end(err)
+	if err != nil {
+		return nil, fmt.Errorf("player %s: %s", version, err)
+	}
+	y.playerVersion, y.playerVersionAt, y.decipher = version, time.Now(), decipher
+	return decipher, nil
+}
+
+//forgetPlayerVersion makes the next decipher check the player version.
+func (y *Youtube) forgetPlayerVersion() {
+	y.locks.decipher.Lock()
+	y.playerVersionAt = time.Time{}
+	y.locks.decipher.Unlock()
+}
+
+func parseCipherOps(js string) ([]cipherOp, error) {
+	body := decipherFuncRe.FindStringSubmatch(js)
+	if body == nil {
+		return nil, errors.New("no decipher function found")
+	}
+	calls := decipherCallRe.FindAllStringSubmatch(body[1], -1)
+	if len(calls) == 0 {
+		return nil, errors.New("no decipher step found")
+	}
+
+	//the helper object holding the reverse, splice and swap methods
+	objRe, err := regexp.Compile(`var\s+` + regexp.QuoteMeta(calls[0][1]) + `\s*=\s*\{((?:[^{}]*\{[^}]*\}[^{}]*?)+)\}\s*;`)
+	if err != nil {
+		return nil, err
+	}
+	obj := objRe.FindStringSubmatch(js)
+	if obj == nil {
+		return nil, fmt.Errorf("no decipher helper object '%s' found", calls[0][1])
+	}
+	kinds := make(map[string]string)
+	for _, m := range helperMethodRe.FindAllStringSubmatch(obj[1], -1) {
+		switch {
+		case strings.Contains(m[2], "reverse"):
+			kinds[m[1]] = "reverse"
+		case strings.Contains(m[2], "splice"):
+			kinds[m[1]] = "splice"
+		default:
+			kinds[m[1]] = "swap"
+		}
+	}
+
+	var ops []cipherOp
+	for _, c := range calls {
+		kind, ok := kinds[c[2]]
+		if !ok {
+			return nil, fmt.Errorf("unknown decipher method '%s'", c[2])
+		}
+		arg, _ := strconv.Atoi(c[3])
+		ops = append(ops, cipherOp{kind: kind, arg: arg})
+	}
+	return ops, nil
+}
+
+func applyCipherOps(ops []cipherOp, s string) string {
+	b := []byte(s)
+	for _, op := range ops {
+		switch op.kind {
+		case "reverse":
+			for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+				b[i], b[j] = b[j], b[i]
+			}
+		case "splice":
+			if op.arg < len(b) {
+				b = b[op.arg:]
+			}
+		case "swap":
+			if len(b) > 0 {
+				i := op.arg % len(b)
+				b[0], b[i] = b[i], b[0]
+			}
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,91 @@
+package youtube
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const playerJS = `var Xy={Ab:function(a){a.reverse()},
+cD:function(a,b){a.splice(0,b)},
+e$:function(a,b){var c=a[0];a[0]=a[b%a.length];a[b%a.length]=c}};
+Qa=function(a){a=a.split("");Xy.e$(a,3);Xy.Ab(a,12);Xy.cD(a,2);return a.join("")};`
+
+func TestParseCipherOps(t *testing.T) {
+	ops, err := parseCipherOps(playerJS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 3 || ops[0].kind != "swap" || ops[1].kind != "reverse" || ops[2] != (cipherOp{"splice", 2}) {
+		t.Fatalf("Wrong ops: %+v", ops)
+	}
+	//swap 0 and 3: dbcaefg, reverse: gfeacbd, splice 2: eacbd
+	if sig := applyCipherOps(ops, "abcdefg"); sig != "eacbd" {
+		t.Errorf("Wrong signature: %s", sig)
+	}
+}
+
+func TestDecipherURLUnsigned(t *testing.T) {
+	y := NewYoutube(false)
+	u, err := y.DecipherURL("url=https%3A%2F%2Fr1.googlevideo.com%2Fvideoplayback%3Fitag%3D18")
+	if err != nil || u != "https://r1.googlevideo.com/videoplayback?itag=18" {
+		t.Errorf("Unsigned url should be returned as is: %s, err=%v", u, err)
+	}
+}
+
+func TestStreamURL(t *testing.T) {
+	y := NewYoutube(false)
+	y.Formats = []Format{{Itag: 18, signatureCipher: "url=https%3A%2F%2Fr1.googlevideo.com%2Fvideoplayback%3Fitag%3D18%26sig%3Dok"}}
+	u, err := y.streamURL(stream{"itag": "18", "url": "https://r1.googlevideo.com/videoplayback?itag=18"})
+	if err != nil || u != "https://r1.googlevideo.com/videoplayback?itag=18&sig=ok" {
+		t.Errorf("The stream url should come from its format: %s, err=%v", u, err)
+	}
+	u, err = y.streamURL(stream{"itag": "22", "url": "https://r1.googlevideo.com/videoplayback?itag=22"})
+	if err != nil || u != "https://r1.googlevideo.com/videoplayback?itag=22" {
+		t.Errorf("A stream without format keeps its url: %s, err=%v", u, err)
+	}
+}
+
+func TestPlayerVersionCache(t *testing.T) {
+	checks := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/iframe_api" {
+			checks++
+			w.Write([]byte(`player\/abcdef12\/`))
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/s/player/") {
+			w.Write([]byte(playerJS))
+		}
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	y := NewYoutube(false)
+	y.client = &http.Client{Transport: hostRedirect{u}}
+	decipher := func() {
+		target, err := y.DecipherURL("s=abcdefg&sp=sig&url=https%3A%2F%2Fr1.googlevideo.com%2Fvideoplayback")
+		if err != nil || target != "https://r1.googlevideo.com/videoplayback?sig=eacbd" {
+			t.Fatalf("Wrong url %s, err=%v", target, err)
+		}
+	}
+	decipher()
+	decipher()
+	if checks != 1 {
+		t.Errorf("The player version was checked %d times, want once", checks)
+	}
+	y.playerVersionAt = time.Now().Add(-playerVersionTTL)
+	decipher()
+	if checks != 2 {
+		t.Errorf("The expired player version wasn't checked again")
+	}
+	y.forgetPlayerVersion()
+	decipher()
+	if checks != 3 {
+		t.Errorf("The forgotten player version wasn't checked again")
+	}
+}
@@ -0,0 +1,76 @@
+package youtube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+//DefaultConfigDir : The config directory of the package, $XDG_CONFIG_HOME/youtube on
+//Unix, %AppData%\youtube on Windows and ~/Library/Application Support/youtube on macOS.
+func DefaultConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "youtube"), nil
+}
+
+//DefaultCacheDir : The cache directory of the package, $XDG_CACHE_HOME/youtube on
+//Unix, %LocalAppData%\youtube on Windows and ~/Library/Caches/youtube on macOS.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "youtube"), nil
+}
+
+//cacheDir is CacheDir or the default one, empty when the cache is disabled.
+func (y *Youtube) cacheDir() string {
+	if y.DisableCache {
+		return ""
+	}
+	if y.CacheDir != "" {
+		return y.CacheDir
+	}
+	dir, err := DefaultCacheDir()
+	if err != nil {
+		y.log(fmt.Sprintf("No cache directory, error=%s", err))
+		return ""
+	}
+	return dir
+}
+
+//readCache returns the cached file, nil when it isn't cached.
+func (y *Youtube) readCache(name string) []byte {
+	dir := y.cacheDir()
+	if dir == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+//writeCache stores the file in the cache, failures only being logged.
+func (y *Youtube) writeCache(name string, data []byte) {
+	dir := y.cacheDir()
+	if dir == "" {
+		return
+	}
+	file := filepath.Join(dir, name)
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err == nil {
+		tmp := file + ".tmp"
+		if err = ioutil.WriteFile(tmp, data, 0644); err == nil {
+			err = os.Rename(tmp, file)
+		}
+	}
+	if err != nil {
+		y.log(fmt.Sprintf("Cache file=%s failed, error=%s", file, err))
+	}
+}
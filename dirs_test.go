@@ -0,0 +1,56 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultDirs(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/config")
+	t.Setenv("XDG_CACHE_HOME", "/tmp/cache")
+	if dir, err := DefaultConfigDir(); err != nil || dir != "/tmp/config/youtube" {
+		t.Errorf("Wrong config dir %s, error %v", dir, err)
+	}
+	if dir, err := DefaultCacheDir(); err != nil || dir != "/tmp/cache/youtube" {
+		t.Errorf("Wrong cache dir %s, error %v", dir, err)
+	}
+}
+
+func TestPlayerCache(t *testing.T) {
+	var playerFetches int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/iframe_api" {
+			w.Write([]byte(`player\/abcdef12\/`))
+			return
+		}
+		playerFetches++
+		w.Write([]byte(playerJS))
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+	dir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		y := NewYoutube(false)
+		y.CacheDir = dir
+		y.client = &http.Client{Transport: hostRedirect{u}}
+		decipher, err := y.playerDecipher()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig := decipher("abcdefg"); sig != "eacbd" {
+			t.Errorf("Wrong signature: %s", sig)
+		}
+	}
+	if playerFetches != 1 {
+		t.Errorf("The player was fetched %d times", playerFetches)
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(dir, "player", "abcdef12.js")); !strings.Contains(string(b), "a.split") {
+		t.Error("The player isn't cached")
+	}
+}
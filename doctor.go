@@ -0,0 +1,152 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+)
+
+//DoctorVideoID : The video Doctor decodes and downloads a few bytes of.
+var DoctorVideoID = "rFejpH_tAHM"
+
+//doctorRangeSize is the number of bytes of the ranged download check.
+const doctorRangeSize = 1024
+
+//DoctorCheck : The outcome of one Doctor check.
+type DoctorCheck struct {
+	Name     string
+	OK       bool
+	Skipped  bool
+	Duration time.Duration
+	//Detail is the error of a failed check or what a passed one found.
+	Detail string
+}
+
+//DoctorReport : The checks run by Doctor, in order.
+type DoctorReport struct {
+	GoVersion string
+	OS        string
+	Checks    []DoctorCheck
+}
+
+//OK : Tell if every check passed.
+func (r DoctorReport) OK() bool {
+	for _, c := range r.Checks {
+		if !c.OK {
+			return false
+		}
+	}
+	return true
+}
+
+//String : The report as text to paste in a bug report.
+func (r DoctorReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "go: %s\nos: %s\n", r.GoVersion, r.OS)
+	for _, c := range r.Checks {
+		status := "ok"
+		switch {
+		case c.Skipped:
+			status = "skipped"
+		case !c.OK:
+			status = "FAILED"
+		}
+		fmt.Fprintf(&b, "%-16s %-7s %6dms  %s\n", c.Name, status, c.Duration.Milliseconds(), c.Detail)
+	}
+	return b.String()
+}
+
+//Doctor : Diagnose the environment with the downloader settings: connectivity to
+//youtube.com, the player javascript fetch, the decipher extraction, the video
+//information of DoctorVideoID and a tiny ranged download from googlevideo.
+//The checks after a failed one that they depend on are skipped.
+func (y *Youtube) Doctor() DoctorReport {
+	report := DoctorReport{GoVersion: runtime.Version(), OS: runtime.GOOS + "/" + runtime.GOARCH}
+	failed := false
+	check := func(name string, run func() (string, error)) {
+		c := DoctorCheck{Name: name}
+		if failed {
+			c.Skipped = true
+		} else {
+			start := time.Now()
+			detail, err := run()
+			c.Duration = time.Since(start)
+			c.OK, c.Detail = err == nil, detail
+			if err != nil {
+				c.Detail, failed = err.Error(), true
+			}
+		}
+		report.Checks = append(report.Checks, c)
+	}
+
+	check("youtube.com", func() (string, error) {
+		_, err := y.fetch("https://www.youtube.com/")
+		return "reachable", err
+	})
+	check("player js", func() (string, error) {
+		body, err := y.fetch("https://www.youtube.com/iframe_api")
+		if err != nil {
+			return "", err
+		}
+		subs := playerVersionRe.FindSubmatch(body)
+		if subs == nil {
+			return "", fmt.Errorf("no player version in the iframe api")
+		}
+		return "player " + string(subs[1]), nil
+	})
+	check("decipher", func() (string, error) {
+		_, err := y.playerDecipher()
+		return "extracted", err
+	})
+	var target string
+	check("video info", func() (string, error) {
+		if err := y.DecodeURL(DoctorVideoID); err != nil {
+			return "", err
+		}
+		if len(y.Formats) == 0 {
+			return "", fmt.Errorf("no format found")
+		}
+		var err error
+		if target, err = y.formatURL(y.Formats[0]); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d formats", len(y.Formats)), nil
+	})
+	check("googlevideo", func() (string, error) {
+		return y.rangedDownload(target)
+	})
+	return report
+}
+
+//rangedDownload gets the first bytes of the stream url.
+func (y *Youtube) rangedDownload(target string) (string, error) {
+	target, err := y.rewriteURL(target)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", doctorRangeSize-1))
+	resp, err := y.do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status code %d from %s", resp.StatusCode, resp.Request.URL.Host)
+	}
+	n, err := io.Copy(ioutil.Discard, io.LimitReader(resp.Body, doctorRangeSize))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d bytes from %s", n, resp.Request.URL.Host), nil
+}
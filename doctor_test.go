@@ -0,0 +1,57 @@
+package youtube
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+//hostRedirect sends every request to the test server, whatever its host.
+type hostRedirect struct {
+	server *url.URL
+}
+
+func (h hostRedirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = h.server.Scheme, h.server.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestDoctor(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/iframe_api":
+			w.Write([]byte(`var scriptUrl = 'https:\/\/www.youtube.com\/s\/player\/abcdef12\/www-widgetapi.vflset\/www-widgetapi.js';`))
+		case strings.HasPrefix(r.URL.Path, "/s/player/abcdef12/"):
+			w.Write([]byte(playerJS))
+		case r.URL.Path == "/get_video_info":
+			w.Write([]byte(videoInfoFixture("")))
+		case r.URL.Path == "/videoplayback":
+			if r.Header.Get("Range") != "bytes=0-1023" {
+				t.Errorf("Wrong range %s", r.Header.Get("Range"))
+			}
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(make([]byte, 1024))
+		}
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+
+	y := NewYoutube(false)
+	y.client = &http.Client{Transport: hostRedirect{u}}
+	report := y.Doctor()
+	if !report.OK() {
+		t.Fatalf("Doctor failed:\n%s", report)
+	}
+	if d := report.Checks[len(report.Checks)-1].Detail; d != "1024 bytes from "+u.Host {
+		t.Errorf("Wrong ranged download: %s", d)
+	}
+
+	ts.Close()
+	report = y.Doctor()
+	if report.OK() || report.Checks[0].OK || !report.Checks[1].Skipped {
+		t.Errorf("Wrong failure report:\n%s", report)
+	}
+}
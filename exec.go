@@ -0,0 +1,47 @@
+package youtube
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//execAfterDownload runs the ExecAfterDownload command template for the result,
+//e.g. a media library scan. The arguments are separated by spaces and these
+//placeholders are replaced: {path}, {dir} and {file} of the final file, {id},
+// {url}, {title}, {author}, {channel_id}, {size} and {sha256}. The command isn't
+//run by a shell, quotes don't group the words: an argument can't contain a
+//space, but the placeholder values can, e.g. a title. Run a script instead of a
+//command needing quoted arguments or pipes.
+func (y *Youtube) execAfterDownload(result *DownloadResult) error {
+	fields := strings.Fields(y.ExecAfterDownload)
+	if len(fields) == 0 {
+		return nil
+	}
+	details := y.Details
+	if details == nil {
+		details = &VideoDetails{}
+	}
+	r := strings.NewReplacer(
+		"{path}", result.Path, "{dir}", filepath.Dir(result.Path), "{file}", filepath.Base(result.Path),
+		"{id}", y.VideoID, "{url}", "https://www.youtube.com/watch?v="+y.VideoID,
+		"{title}", details.Title, "{author}", details.Author, "{channel_id}", details.ChannelID,
+		"{size}", strconv.FormatInt(result.Size, 10), "{sha256}", result.SHA256,
+	)
+	args := make([]string, len(fields)-1)
+	for i, a := range fields[1:] {
+		args[i] = r.Replace(a)
+	}
+	return y.runCommand(fields[0], args...)
+}
+
+//runCommand runs a user command and keeps its output in the error.
+func (y *Youtube) runCommand(command string, args ...string) error {
+	y.log(fmt.Sprintf("Run: %s %s", command, strings.Join(args, " ")))
+	if out, err := exec.Command(command, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed, err=%s, output=%s", command, err, out)
+	}
+	return nil
+}
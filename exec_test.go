@@ -0,0 +1,57 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecAfterDownload(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "scan")
+	out := filepath.Join(dir, "args")
+	ioutil.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" > "+out+"\n"), 0755)
+
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.Details = &VideoDetails{Title: "Simplicity", ChannelID: "UCSRhwaM00ay0fasnsw6EXKA"}
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	y.ExecAfterDownload = script + " --file={file} {id} {title} {channel_id} {size}"
+	if _, err := y.Download(filepath.Join(dir, "dl.mp4")); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(out)
+	if want := "--file=dl.mp4 rFejpH_tAHM Simplicity UCSRhwaM00ay0fasnsw6EXKA 10\n"; string(b) != want {
+		t.Errorf("Wrong command arguments %q, want %q", b, want)
+	}
+
+	y.ExecAfterDownload = "false {path}"
+	if _, err := y.Download(filepath.Join(dir, "dl2.mp4")); err == nil {
+		t.Error("A failing command should be reported")
+	}
+}
+
+func TestExecAfterDownloadSpaces(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "scan")
+	out := filepath.Join(dir, "args")
+	ioutil.WriteFile(script, []byte("#!/bin/sh\nprintf '%s\\n' \"$@\" > "+out+"\n"), 0755)
+
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.Details = &VideoDetails{Title: "Simple Made Easy"}
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	//the quotes aren't a shell's, they stay in the arguments
+	y.ExecAfterDownload = script + " {title} \"a b\""
+	if _, err := y.Download(filepath.Join(dir, "dl.mp4")); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(out)
+	if want := "Simple Made Easy\n\"a\nb\"\n"; string(b) != want {
+		t.Errorf("Wrong command arguments %q, want %q", b, want)
+	}
+}
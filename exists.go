@@ -0,0 +1,112 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//ExistsPolicy : What StartDownload does when the destination file already exists.
+type ExistsPolicy int
+
+const (
+	//ExistsOverwrite : Truncate the existing file, the default.
+	ExistsOverwrite ExistsPolicy = iota
+	//ExistsSkip : Keep the existing file and return without downloading.
+	ExistsSkip
+	//ExistsError : Return ErrFileExists.
+	ExistsError
+	//ExistsNumber : Download to "name (1).ext", "name (2).ext", ...
+	ExistsNumber
+	//ExistsResume : Continue a partial download with a Range request.
+	ExistsResume
+)
+
+//ErrFileExists : Returned by StartDownload with ExistsError when the destination exists.
+var ErrFileExists = errors.New("destination file already exists")
+
+//maxNumberedFiles bounds the search of a free numbered name.
+const maxNumberedFiles = 10000
+
+//resolveDest applies the OnExists policy to the destination, it returns the
+//file to download to, or skip when the download must not happen.
+//Only local files are checked, a Storage decides itself.
+func (y *Youtube) resolveDest(destFile string) (dest string, skip bool, err error) {
+	if y.Storage != nil || !fileExists(destFile) {
+		return destFile, false, nil
+	}
+	switch y.OnExists {
+	case ExistsSkip:
+		y.log(fmt.Sprintf("Skip download, %s already exists", destFile))
+		return destFile, true, nil
+	case ExistsError:
+		return "", false, fmt.Errorf("%w: %s", ErrFileExists, destFile)
+	case ExistsNumber:
+		ext := filepath.Ext(destFile)
+		base := strings.TrimSuffix(destFile, ext)
+		for i := 1; i < maxNumberedFiles; i++ {
+			name := fmt.Sprintf("%s (%d)%s", base, i, ext)
+			if !fileExists(name) {
+				y.log(fmt.Sprintf("%s already exists, download to %s", destFile, name))
+				return name, false, nil
+			}
+		}
+		return "", false, fmt.Errorf("%w: no free numbered name for %s", ErrFileExists, destFile)
+	}
+	return destFile, false, nil
+}
+
+//resumeItagSuffix names the sidecar file recording the itag of a partial download.
+const resumeItagSuffix = ".itag"
+
+//resuming reports whether partial local files are continued.
+func (y *Youtube) resuming() bool {
+	return y.OnExists == ExistsResume && (y.Storage == nil || y.localOutput)
+}
+
+//resumeOffset returns the size of the partial file to resume, 0 to start over.
+//A partial file of another itag than the one downloaded now is started over,
+//the bytes of two streams can't be appended.
+func (y *Youtube) resumeOffset(destFile string) int64 {
+	if !y.resuming() {
+		return 0
+	}
+	info, err := os.Stat(destFile)
+	if err != nil || !info.Mode().IsRegular() {
+		return 0
+	}
+	if y.streamItag != 0 && info.Size() > 0 {
+		data, err := ioutil.ReadFile(destFile + resumeItagSuffix)
+		if err == nil && strings.TrimSpace(string(data)) != strconv.Itoa(y.streamItag) {
+			y.log(fmt.Sprintf("%s is a partial download of itag=%s, start over for itag=%d", destFile, strings.TrimSpace(string(data)), y.streamItag))
+			return 0
+		}
+	}
+	return info.Size()
+}
+
+//writeResumeItag records the itag written to destFile until its download ends.
+func (y *Youtube) writeResumeItag(destFile string) {
+	if !y.resuming() || y.streamItag == 0 {
+		return
+	}
+	if err := ioutil.WriteFile(destFile+resumeItagSuffix, []byte(strconv.Itoa(y.streamItag)), 0644); err != nil {
+		y.log(fmt.Sprintf("write resume itag error=%s", err))
+	}
+}
+
+//clearResumeItag removes the sidecar of a completed download.
+func (y *Youtube) clearResumeItag(destFile string) {
+	if y.resuming() {
+		os.Remove(destFile + resumeItagSuffix)
+	}
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
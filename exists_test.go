@@ -0,0 +1,113 @@
+package youtube
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func existsServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "dl.mp4", time.Time{}, strings.NewReader("video data"))
+	}))
+}
+
+func TestExistsPolicies(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "dl.mp4")
+
+	tests := []struct {
+		policy ExistsPolicy
+		file   string
+		want   string
+		err    error
+	}{
+		{ExistsSkip, dest, "old", nil},
+		{ExistsError, dest, "old", ErrFileExists},
+		{ExistsNumber, filepath.Join(dir, "dl (1).mp4"), "video data", nil},
+		{ExistsOverwrite, dest, "video data", nil},
+	}
+	for _, test := range tests {
+		if err := ioutil.WriteFile(dest, []byte("old"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		y := NewYoutube(false)
+		y.OnExists = test.policy
+		y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+		if err := y.StartDownload(dest); !errors.Is(err, test.err) {
+			t.Errorf("policy %d: got error %v, want %v", test.policy, err, test.err)
+		}
+		b, _ := ioutil.ReadFile(test.file)
+		if string(b) != test.want {
+			t.Errorf("policy %d: %s contains %q, want %q", test.policy, test.file, b, test.want)
+		}
+	}
+}
+
+func TestExistsResume(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	dest := filepath.Join(t.TempDir(), "dl.mp4")
+	if err := ioutil.WriteFile(dest, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	y := NewYoutube(false)
+	y.OnExists = ExistsResume
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	for i := 0; i < 2; i++ {
+		if err := y.StartDownload(dest); err != nil {
+			t.Fatal(err)
+		}
+		if b, _ := ioutil.ReadFile(dest); string(b) != "video data" {
+			t.Errorf("Resumed file contains %q", b)
+		}
+	}
+}
+
+func TestExistsResumeOtherItag(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	//the first stream breaks after a few bytes
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte("other"))
+	}))
+	defer broken.Close()
+	dest := filepath.Join(t.TempDir(), "dl.mp4")
+
+	y := NewYoutube(false)
+	y.OnExists = ExistsResume
+	y.StreamList = []stream{{"itag": "22", "url": broken.URL}, {"itag": "18", "url": ts.URL}}
+	if err := y.StartDownload(dest); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != "video data" {
+		t.Errorf("File contains %q, want the bytes of itag 18 only", b)
+	}
+	if fileExists(dest + resumeItagSuffix) {
+		t.Error("The itag sidecar of the completed download is left")
+	}
+
+	//a partial file of itag 22 isn't resumed by itag 18
+	if err := ioutil.WriteFile(dest, []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(dest+resumeItagSuffix, []byte("22"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	if err := y.StartDownload(dest); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != "video data" {
+		t.Errorf("File contains %q after a change of itag", b)
+	}
+}
@@ -0,0 +1,98 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//ExternalDownloader : Hands the stream downloads to another program, e.g. aria2c
+//for its multi-connection downloads. These placeholders of Args are replaced for
+//each stream: {url}, {output} the file path, {dir} and {file} its directory and
+//name, and an argument "{headers}" expands to HeaderFlag and "Name: value" for
+//each header. The rate limit, the bandwidth budget and the progress in percent
+//don't apply, the progress ends at 100 once the program exits.
+type ExternalDownloader struct {
+	Command    string
+	Args       []string
+	HeaderFlag string
+	Headers    http.Header
+}
+
+//Aria2c : aria2c with 16 connections per stream, resuming the partial files.
+func Aria2c() *ExternalDownloader {
+	return &ExternalDownloader{
+		Command: "aria2c",
+		Args: []string{"--max-connection-per-server=16", "--split=16", "--min-split-size=1M",
+			"--continue=true", "--allow-overwrite=true", "--auto-file-renaming=false",
+			"--console-log-level=warn", "--summary-interval=0", "{headers}", "--dir={dir}", "--out={file}", "{url}"},
+		HeaderFlag: "--header",
+	}
+}
+
+//ParseExternalDownloader : Read a command template like "curl -o {output} {url}",
+//the arguments are separated by spaces. "aria2c" alone is the Aria2c defaults.
+func ParseExternalDownloader(template string) (*ExternalDownloader, error) {
+	fields := strings.Fields(template)
+	if len(fields) == 0 {
+		return nil, errors.New("empty external downloader command")
+	}
+	if len(fields) == 1 && filepath.Base(fields[0]) == "aria2c" {
+		d := Aria2c()
+		d.Command = fields[0]
+		return d, nil
+	}
+	if !strings.Contains(template, "{url}") {
+		return nil, fmt.Errorf("the external downloader command has no {url}: '%s'", template)
+	}
+	return &ExternalDownloader{Command: fields[0], Args: fields[1:], HeaderFlag: "-H"}, nil
+}
+
+//args expands the placeholders of the arguments for one download.
+func (d *ExternalDownloader) args(url, output string) []string {
+	r := strings.NewReplacer("{url}", url, "{output}", output, "{dir}", filepath.Dir(output), "{file}", filepath.Base(output))
+	var args []string
+	for _, a := range d.Args {
+		if a != "{headers}" {
+			args = append(args, r.Replace(a))
+			continue
+		}
+		names := make([]string, 0, len(d.Headers))
+		for name := range d.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, v := range d.Headers[name] {
+				args = append(args, d.HeaderFlag, name+": "+v)
+			}
+		}
+	}
+	return args
+}
+
+//externalDownload runs the ExternalDownloader for the stream url.
+func (y *Youtube) externalDownload(ctx context.Context, target, destFile string, span *traceSpan) error {
+	d := y.ExternalDownloader
+	args := d.args(target, destFile)
+	y.log(fmt.Sprintf("Run: %s %s", d.Command, strings.Join(args, " ")))
+	out, err := exec.CommandContext(ctx, d.Command, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed, err=%s, output=%s", d.Command, err, out)
+	}
+	size := fileSize(destFile)
+	span.set("bytes", size)
+	if y.result != nil {
+		y.result.Size = size
+	}
+	y.FinalURL = target
+	y.contentLength, y.totalWrittenBytes = float64(size), float64(size)
+	y.downloadLevel = 100
+	y.reportProgress()
+	return nil
+}
@@ -0,0 +1,55 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExternalDownloaderArgs(t *testing.T) {
+	d := Aria2c()
+	d.Headers = http.Header{"Cookie": {"a=b"}}
+	args := d.args("https://r1.googlevideo.com/videoplayback", filepath.Join("videos", "dl.mp4"))
+	want := []string{"--header", "Cookie: a=b", "--dir=videos", "--out=dl.mp4", "https://r1.googlevideo.com/videoplayback"}
+	if got := args[len(args)-len(want):]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := ParseExternalDownloader("curl -o {output}"); err == nil {
+		t.Error("A template without {url} should fail")
+	}
+	if d, _ := ParseExternalDownloader("/usr/bin/aria2c"); d.HeaderFlag != "--header" || d.Command != "/usr/bin/aria2c" {
+		t.Errorf("Wrong aria2c defaults %+v", d)
+	}
+}
+
+func TestExternalDownload(t *testing.T) {
+	dir := t.TempDir()
+	//the fake downloader writes its url to the output
+	script := filepath.Join(dir, "fetch")
+	if err := ioutil.WriteFile(script, []byte("#!/bin/sh\nprintf %s \"$2\" > \"$1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d, err := ParseExternalDownloader(script + " {output} {url}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	y := NewYoutube(false)
+	y.ExternalDownloader = d
+	y.StreamList = []stream{{"itag": "18", "url": "https://r1.googlevideo.com/videoplayback"}}
+	var last ProgressEvent
+	y.OnProgress = func(ev ProgressEvent) { last = ev }
+	dest := filepath.Join(dir, "dl.mp4")
+	result, err := y.Download(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != "https://r1.googlevideo.com/videoplayback" {
+		t.Errorf("The file contains %q", b)
+	}
+	if result.Size != 40 || last.Percent != 100 {
+		t.Errorf("Wrong size %d or progress %+v", result.Size, last)
+	}
+}
@@ -0,0 +1,80 @@
+package youtube
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+//maxFileNameLen leaves room for an extension and a " (n)" suffix under the
+// 255 bytes most file systems accept.
+const maxFileNameLen = 200
+
+//maxPathLen is the MAX_PATH of the win32 api, longer paths need the \\?\ prefix.
+const maxPathLen = 260
+
+//reservedNames are the device names windows refuses as file names, with any extension.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+//SafeFileName : A file name for the title that is valid on windows, macOS and linux:
+//path separators, reserved characters and control characters become '_', trailing
+//dots and spaces are removed, device names like CON or NUL get a '_' suffix and
+//the name is cut to 200 bytes. It is empty when nothing is left of the title.
+func SafeFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r < 32, r == 127, strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(title))
+	if len(name) > maxFileNameLen {
+		name = name[:maxFileNameLen]
+		for !utf8.ValidString(name) {
+			name = name[:len(name)-1]
+		}
+	}
+	//explorer silently drops them, so "a." and "a" would be the same file
+	name = strings.TrimRight(name, ". ")
+	base := name
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[:i]
+	}
+	if reservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+		name = base + "_" + name[len(base):]
+	}
+	return name
+}
+
+//longPath makes the path usable by the win32 api when it exceeds MAX_PATH,
+//it is unchanged on the other systems.
+func longPath(name string) string {
+	if runtime.GOOS != "windows" {
+		return name
+	}
+	if abs, err := filepath.Abs(name); err == nil {
+		name = abs
+	}
+	return extendedPath(name)
+}
+
+//extendedPath adds the \\?\ prefix to a long absolute windows path, or \\?\UNC\
+//to a network share. The prefix disables the path normalization, so the path
+//must be absolute and use backslashes.
+func extendedPath(abs string) string {
+	if len(abs) < maxPathLen || strings.HasPrefix(abs, `\\?\`) {
+		return abs
+	}
+	abs = strings.ReplaceAll(abs, "/", `\`)
+	if strings.HasPrefix(abs, `\\`) {
+		return `\\?\UNC\` + abs[2:]
+	}
+	return `\\?\` + abs
+}
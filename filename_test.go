@@ -0,0 +1,47 @@
+package youtube
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSafeFileName(t *testing.T) {
+	tests := []struct {
+		title, want string
+	}{
+		{"Rob Pike: Simplicity", "Rob Pike_ Simplicity"},
+		{"a/b\\c", "a_b_c"},
+		{"  The end...  ", "The end"},
+		{"CON", "CON_"},
+		{"nul.txt", "nul_.txt"},
+		{"Com1 ", "Com1_"},
+		{"CONSOLE", "CONSOLE"},
+		{"...", ""},
+	}
+	for _, test := range tests {
+		if got := SafeFileName(test.title); got != test.want {
+			t.Errorf("SafeFileName(%q) = %q, want %q", test.title, got, test.want)
+		}
+	}
+	long := SafeFileName(strings.Repeat("é", 150))
+	if len(long) != maxFileNameLen {
+		t.Errorf("length %d, want %d", len(long), maxFileNameLen)
+	}
+}
+
+func TestExtendedPath(t *testing.T) {
+	dir := strings.Repeat(`\folder`, 40)
+	tests := []struct {
+		path, want string
+	}{
+		{`C:\videos\a.mp4`, `C:\videos\a.mp4`},
+		{`C:` + dir + `\a.mp4`, `\\?\C:` + dir + `\a.mp4`},
+		{`\\nas\share` + dir, `\\?\UNC\nas\share` + dir},
+		{`\\?\C:` + dir, `\\?\C:` + dir},
+	}
+	for _, test := range tests {
+		if got := extendedPath(test.path); got != test.want {
+			t.Errorf("extendedPath(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
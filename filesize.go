@@ -0,0 +1,75 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+//probeSizes asks the content length of the formats without one, the formats
+//of unknown size are left out since they may not fit under MaxFileSize.
+func (y *Youtube) probeSizes(formats []Format) []Format {
+	var known []Format
+	for _, f := range formats {
+		if f.ContentLength <= 0 {
+			size, err := y.probeSize(f)
+			if err != nil {
+				y.log(fmt.Sprintf("Probe size of itag=%d failed, error=%s", f.Itag, err))
+				continue
+			}
+			f.ContentLength = size
+		}
+		known = append(known, f)
+	}
+	return known
+}
+
+//probeSize sends a HEAD request to the format url.
+func (y *Youtube) probeSize(f Format) (int64, error) {
+	target, err := y.requestURL(f)
+	if err != nil {
+		return 0, err
+	}
+	ctx := context.Background()
+	if y.Timeouts.Request > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, y.Timeouts.Request)
+		defer cancel()
+	}
+	req, err := http.NewRequest("HEAD", target, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := y.do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("non 200 status code received: %d", resp.StatusCode)
+	}
+	if resp.ContentLength <= 0 {
+		return 0, fmt.Errorf("no content length for itag=%d", f.Itag)
+	}
+	return resp.ContentLength, nil
+}
+
+//totalSize is the size of the formats, merged in one file.
+func totalSize(formats []Format) int64 {
+	var size int64
+	for _, f := range formats {
+		size += f.ContentLength
+	}
+	return size
+}
+
+//withoutItag returns the formats other than the itag.
+func withoutItag(formats []Format, itag int) []Format {
+	var kept []Format
+	for _, f := range formats {
+		if f.Itag != itag {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
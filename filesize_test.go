@@ -0,0 +1,50 @@
+package youtube
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMaxFileSize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("Probe with %s, want HEAD", r.Method)
+		}
+		w.Header().Set("Content-Length", "3000")
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.Formats = []Format{
+		{Itag: 313, Height: 2160, ContentLength: 90000, HasVideo: true},
+		{Itag: 137, Height: 1080, ContentLength: 40000, HasVideo: true},
+		{Itag: 136, Height: 720, URL: ts.URL, HasVideo: true},
+		{Itag: 251, Bitrate: 160000, ContentLength: 8000, HasAudio: true},
+		{Itag: 140, Bitrate: 128000, ContentLength: 5000, HasAudio: true},
+	}
+	tests := []struct {
+		max   int64
+		itags []int
+	}{
+		{0, []int{313, 251}},
+		{50000, []int{137, 251}},
+		{44000, []int{136, 251}},
+		{8500, []int{136, 140}},
+	}
+	for _, test := range tests {
+		y.MaxFileSize = test.max
+		picked, err := y.SelectFormats("bestvideo+bestaudio")
+		if err != nil {
+			t.Errorf("max %d: %s", test.max, err)
+			continue
+		}
+		if picked[0].Itag != test.itags[0] || picked[1].Itag != test.itags[1] {
+			t.Errorf("max %d: picked %d+%d, want %v", test.max, picked[0].Itag, picked[1].Itag, test.itags)
+		}
+	}
+	y.MaxFileSize = 1000
+	if _, err := y.SelectFormats("bestvideo+bestaudio"); err == nil {
+		t.Error("No format should fit under 1000 bytes")
+	}
+}
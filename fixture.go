@@ -0,0 +1,215 @@
+package youtube
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+//DefaultFixtureBodySize : Bytes of each answer kept by a Fixture when MaxBodySize is zero,
+//enough for the pages and the player while cutting the video streams short.
+const DefaultFixtureBodySize = 4 << 20
+
+//fixtureSecrets are the query parameters removed from the recorded urls.
+var fixtureSecrets = []string{"key", "ip", "ipbits", "sig", "signature", "lsig", "n", "pot"}
+
+//fixtureHeaders are the answer headers kept in a Fixture.
+var fixtureHeaders = []string{"Content-Type", "Content-Range", "Location", "Retry-After"}
+
+//Fixture : The http exchanges of a download, set as RecordTo to capture them for a
+//bug report, then as ReplayFrom to run the same download offline. The urls lose
+//their signatures, ip and api key, and no cookie nor request header is kept.
+type Fixture struct {
+	MaxBodySize int64      `json:"-"`
+	Exchanges   []Exchange `json:"exchanges"`
+
+	mu     sync.Mutex
+	played map[int]bool
+}
+
+//Exchange : One recorded request and its answer.
+type Exchange struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	//Body is the text of the answer, BodyBase64 the binary ones.
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"body_base64,omitempty"`
+	Truncated  bool   `json:"truncated,omitempty"`
+}
+
+//LoadFixture : Read a fixture saved by Fixture.Save.
+func LoadFixture(path string) (*Fixture, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &Fixture{}
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %s", path, err)
+	}
+	return f, nil
+}
+
+//Save : Write the recorded exchanges as json.
+func (f *Fixture) Save(path string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+//record wraps the answer body to add the exchange once it is closed, keeping
+//the first MaxBodySize bytes read.
+func (f *Fixture) record(req *http.Request, resp *http.Response) {
+	max := f.MaxBodySize
+	if max <= 0 {
+		max = DefaultFixtureBodySize
+	}
+	e := Exchange{Method: req.Method, URL: sanitizeURL(req.URL), Status: resp.StatusCode}
+	for _, h := range fixtureHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			if e.Header == nil {
+				e.Header = http.Header{}
+			}
+			e.Header.Set(h, v)
+		}
+	}
+	resp.Body = &recordedBody{ReadCloser: resp.Body, max: max, done: func(body []byte, truncated bool) {
+		e.Truncated = truncated
+		if utf8.Valid(body) {
+			e.Body = string(body)
+		} else {
+			e.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+		}
+		f.mu.Lock()
+		f.Exchanges = append(f.Exchanges, e)
+		f.mu.Unlock()
+	}}
+}
+
+//recordedBody copies what is read up to max bytes and hands it to done on Close.
+type recordedBody struct {
+	io.ReadCloser
+	buf       bytes.Buffer
+	max       int64
+	truncated bool
+	done      func(body []byte, truncated bool)
+}
+
+func (b *recordedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if keep := b.max - int64(b.buf.Len()); int64(n) > keep {
+		b.buf.Write(p[:keep])
+		b.truncated = true
+	} else {
+		b.buf.Write(p[:n])
+	}
+	return n, err
+}
+
+func (b *recordedBody) Close() error {
+	if b.done != nil {
+		b.done(b.buf.Bytes(), b.truncated)
+		b.done = nil
+	}
+	return b.ReadCloser.Close()
+}
+
+//replay answers the first exchange of the request not played yet, or the last
+//one played when the request is repeated more often than recorded.
+func (f *Fixture) replay(req *http.Request) (*http.Response, error) {
+	target := sanitizeURL(req.URL)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.played == nil {
+		f.played = make(map[int]bool)
+	}
+	found := -1
+	for i, e := range f.Exchanges {
+		if e.Method != req.Method || e.URL != target {
+			continue
+		}
+		found = i
+		if !f.played[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("no recorded answer for %s %s", req.Method, target)
+	}
+	f.played[found] = true
+	e := f.Exchanges[found]
+	body := []byte(e.Body)
+	if e.BodyBase64 != "" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(e.BodyBase64); err != nil {
+			return nil, err
+		}
+	}
+	header := http.Header{}
+	for k, v := range e.Header {
+		header[k] = v
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+//sanitizeURL removes the secrets of the url, the recorded and replayed urls
+//are compared this way.
+func sanitizeURL(u *url.URL) string {
+	c := *u
+	c.User = nil
+	q := c.Query()
+	for _, s := range fixtureSecrets {
+		q.Del(s)
+	}
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+//recordTransport adds the exchanges of its requests to the fixture.
+type recordTransport struct {
+	base    http.RoundTripper
+	fixture *Fixture
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.fixture.record(req, resp)
+	return resp, nil
+}
+
+//replayTransport answers from the fixture without network.
+type replayTransport struct {
+	fixture *Fixture
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.fixture.replay(req)
+}
@@ -0,0 +1,48 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixtureRecordReplay(t *testing.T) {
+	ts := existsServer()
+	dir := t.TempDir()
+	fixtureFile := filepath.Join(dir, "fixture.json")
+
+	y := NewYoutube(false)
+	y.RecordTo = &Fixture{MaxBodySize: 5}
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL + "/videoplayback?itag=18&sig=secret"}}
+	if err := y.StartDownload(filepath.Join(dir, "recorded.mp4")); err != nil {
+		t.Fatal(err)
+	}
+	if err := y.RecordTo.Save(fixtureFile); err != nil {
+		t.Fatal(err)
+	}
+	ts.Close()
+	b, _ := ioutil.ReadFile(fixtureFile)
+	if strings.Contains(string(b), "secret") || !strings.Contains(string(b), `"truncated": true`) {
+		t.Errorf("Wrong fixture:\n%s", b)
+	}
+
+	f, err := LoadFixture(fixtureFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y = NewYoutube(false)
+	y.ReplayFrom = f
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL + "/videoplayback?itag=18&sig=other"}}
+	dest := filepath.Join(dir, "replayed.mp4")
+	if err := y.StartDownload(dest); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != "video" {
+		t.Errorf("Replayed %q", b)
+	}
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL + "/videoplayback?itag=22"}}
+	if err := y.StartDownload(dest); err == nil {
+		t.Error("A request that wasn't recorded should fail")
+	}
+}
@@ -0,0 +1,239 @@
+package youtube
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Format : One downloadable format of the video, muxed or adaptive (video or audio only).
+type Format struct {
+	Itag          int
+	MimeType      string
+	Quality       string
+	QualityLabel  string
+	Width         int
+	Height        int
+	FPS           int
+	Bitrate       int
+	ContentLength int64
+	URL           string
+	HasVideo      bool
+	HasAudio      bool
+	//HDR is set for high dynamic range video, see ColorTransfer.
+	HDR bool
+	//ColorTransfer is the transfer characteristics with the ffmpeg names,
+	//e.g. bt709, or smpte2084 (PQ) and arib-std-b67 (HLG) for HDR.
+	ColorTransfer string
+	//Projection is set for 360 and VR video, e.g. EQUIRECTANGULAR or MESH.
+	Projection string
+	//AudioTrack is set on videos with several audio tracks, like dubs.
+	AudioTrack *AudioTrack
+	//Duration of the stream from the player response, it can differ a little
+	//between the video and audio streams.
+	Duration time.Duration
+	//AverageBitrate in bits per second, Bitrate is the peak of the VBR formats.
+	AverageBitrate int
+
+	//signatureCipher is set when the url must be signed with DecipherURL.
+	signatureCipher string
+}
+
+//Ext : The container extension of the format, e.g. mp4, m4a or webm.
+func (f Format) Ext() string {
+	if strings.HasPrefix(f.MimeType, "audio/mp4") {
+		return "m4a"
+	}
+	mime := f.MimeType
+	if i := strings.Index(mime, ";"); i >= 0 {
+		mime = mime[:i]
+	}
+	if i := strings.Index(mime, "/"); i >= 0 {
+		mime = mime[i+1:]
+	}
+	if mime == "3gpp" {
+		return "3gp"
+	}
+	return strings.TrimSpace(mime)
+}
+
+//ExpectedSize : The size of the format in bytes, estimated from its average bitrate
+//and duration when the size isn't known, 0 when neither is.
+func (f Format) ExpectedSize() int64 {
+	if f.ContentLength > 0 {
+		return f.ContentLength
+	}
+	bitrate := f.AverageBitrate
+	if bitrate == 0 {
+		bitrate = f.Bitrate
+	}
+	return int64(float64(bitrate) / 8 * f.Duration.Seconds())
+}
+
+//formatSize is the ExpectedSize of the format of the itag, 0 when unknown.
+func (y *Youtube) formatSize(itag int) int64 {
+	for _, f := range y.Formats {
+		if f.Itag == itag {
+			return f.ExpectedSize()
+		}
+	}
+	return 0
+}
+
+//qualityHeights are the heights of the muxed formats, which don't have a size.
+var qualityHeights = map[string]int{
+	"tiny":   144,
+	"small":  240,
+	"medium": 360,
+	"large":  480,
+	"hd720":  720,
+	"hd1080": 1080,
+}
+
+//parseFormat reads one entry of url_encoded_fmt_stream_map (muxed) or adaptive_fmts.
+func parseFormat(q url.Values, muxed bool) Format {
+	f := Format{
+		MimeType:     q.Get("type"),
+		Quality:      q.Get("quality"),
+		QualityLabel: q.Get("quality_label"),
+		URL:          q.Get("url"),
+	}
+	if q.Get("s") != "" {
+		f.signatureCipher = url.Values{"url": {f.URL}, "s": {q.Get("s")}, "sp": {q.Get("sp")}}.Encode()
+	}
+	f.Itag, _ = strconv.Atoi(q.Get("itag"))
+	f.FPS, _ = strconv.Atoi(q.Get("fps"))
+	f.Bitrate, _ = strconv.Atoi(q.Get("bitrate"))
+	f.ContentLength, _ = strconv.ParseInt(q.Get("clen"), 10, 64)
+	if size := strings.SplitN(q.Get("size"), "x", 2); len(size) == 2 {
+		f.Width, _ = strconv.Atoi(size[0])
+		f.Height, _ = strconv.Atoi(size[1])
+	}
+	if subs := labelFPSRe.FindStringSubmatch(f.QualityLabel); subs != nil && f.FPS == 0 {
+		f.FPS, _ = strconv.Atoi(subs[1])
+	}
+	f.HDR = strings.Contains(f.QualityLabel, "HDR")
+	if muxed {
+		f.HasVideo, f.HasAudio = true, true
+		if f.Height == 0 {
+			f.Height = qualityHeights[f.Quality]
+		}
+	} else {
+		f.HasVideo = strings.HasPrefix(f.MimeType, "video/")
+		f.HasAudio = strings.HasPrefix(f.MimeType, "audio/")
+	}
+	return f
+}
+
+//labelFPSRe reads the frame rate of quality labels like "1080p60 HDR".
+var labelFPSRe = regexp.MustCompile(`^\d+p(\d+)`)
+
+//colorTransfers maps the player response transfer characteristics to the ffmpeg names.
+var colorTransfers = map[string]string{
+	"COLOR_TRANSFER_CHARACTERISTICS_BT709":        "bt709",
+	"COLOR_TRANSFER_CHARACTERISTICS_SMPTEST2084":  "smpte2084",
+	"COLOR_TRANSFER_CHARACTERISTICS_ARIB_STD_B67": "arib-std-b67",
+}
+
+//streamingFormat is the information of a format in the player response.
+type streamingFormat struct {
+	Itag      int `json:"itag"`
+	ColorInfo struct {
+		TransferCharacteristics string `json:"transferCharacteristics"`
+	} `json:"colorInfo"`
+	ProjectionType   string `json:"projectionType"`
+	ApproxDurationMs string `json:"approxDurationMs"`
+	AverageBitrate   int    `json:"averageBitrate"`
+	AudioTrack       *struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+		IsDefault   bool   `json:"audioIsDefault"`
+	} `json:"audioTrack"`
+}
+
+//AudioTrack : One of the audio tracks of a video, the original or a dub.
+type AudioTrack struct {
+	ID string
+	//Language is the language tag, e.g. en or pt-BR.
+	Language string
+	//Name is the displayed name, e.g. "English (United States) original".
+	Name    string
+	Default bool
+}
+
+//AudioTracks : The audio tracks of the decoded video, empty when it has only one.
+func (y *Youtube) AudioTracks() []AudioTrack {
+	var tracks []AudioTrack
+	seen := make(map[string]bool)
+	for _, f := range y.Formats {
+		if f.AudioTrack != nil && !seen[f.AudioTrack.ID] {
+			seen[f.AudioTrack.ID] = true
+			tracks = append(tracks, *f.AudioTrack)
+		}
+	}
+	return tracks
+}
+
+//applyStreamingInfo completes the formats with the color and projection
+//information of the player response streaming data, which the url encoded
+//formats don't have.
+func applyStreamingInfo(formats []Format, playerResponse string) {
+	var pr struct {
+		StreamingData struct {
+			Formats         []streamingFormat `json:"formats"`
+			AdaptiveFormats []streamingFormat `json:"adaptiveFormats"`
+		} `json:"streamingData"`
+	}
+	if err := json.Unmarshal([]byte(playerResponse), &pr); err != nil {
+		return
+	}
+	//the audio tracks of a video share their itags, both lists have them in the same order
+	byItag := make(map[int][]streamingFormat)
+	for _, sf := range append(pr.StreamingData.Formats, pr.StreamingData.AdaptiveFormats...) {
+		byItag[sf.Itag] = append(byItag[sf.Itag], sf)
+	}
+	for i := range formats {
+		same := byItag[formats[i].Itag]
+		if len(same) == 0 {
+			continue
+		}
+		sf := same[0]
+		byItag[formats[i].Itag] = same[1:]
+		if t, ok := colorTransfers[sf.ColorInfo.TransferCharacteristics]; ok {
+			formats[i].ColorTransfer = t
+			formats[i].HDR = formats[i].HDR || t == "smpte2084" || t == "arib-std-b67"
+		}
+		if sf.ProjectionType != "" && sf.ProjectionType != "RECTANGULAR" {
+			formats[i].Projection = sf.ProjectionType
+		}
+		if ms, err := strconv.ParseInt(sf.ApproxDurationMs, 10, 64); err == nil {
+			formats[i].Duration = time.Duration(ms) * time.Millisecond
+		}
+		formats[i].AverageBitrate = sf.AverageBitrate
+		if at := sf.AudioTrack; at != nil {
+			//ids are the language and a track number, e.g. "en.4"
+			formats[i].AudioTrack = &AudioTrack{
+				ID:       at.ID,
+				Language: strings.SplitN(at.ID, ".", 2)[0],
+				Name:     at.DisplayName,
+				Default:  at.IsDefault,
+			}
+		}
+	}
+}
+
+//parseFormats reads a comma separated list of url encoded formats.
+func parseFormats(list string, muxed bool) []Format {
+	var formats []Format
+	for _, raw := range strings.Split(list, ",") {
+		q, err := url.ParseQuery(raw)
+		if err != nil || q.Get("url") == "" {
+			continue
+		}
+		formats = append(formats, parseFormat(q, muxed))
+	}
+	return formats
+}
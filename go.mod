@@ -1,3 +1,3 @@
 module github.com/kkdai/youtube
 
-go 1.6
+go 1.20
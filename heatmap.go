@@ -0,0 +1,81 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+//HeatmapPoint : A segment of the "most replayed" graph of a video.
+type HeatmapPoint struct {
+	Start    time.Duration
+	Duration time.Duration
+	//Intensity is the normalized replay score, from 0 to 1.
+	Intensity float64
+}
+
+//GetHeatmap : Retrieval the most replayed heatmap of the decoded video, empty
+//when the video has none.
+func (y *Youtube) GetHeatmap() ([]HeatmapPoint, error) {
+	if y.VideoID == "" {
+		return nil, errors.New("no video decoded")
+	}
+	data, err := y.fetchInitialData("https://www.youtube.com/watch?v=" + y.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	points := parseHeatmap(data)
+	y.log(fmt.Sprintf("Found %d heatmap points", len(points)))
+	return points, nil
+}
+
+//parseHeatmap reads the heatmap markers, the macro markers entity of recent
+//pages or else the heat markers of the older player bar.
+func parseHeatmap(data interface{}) []HeatmapPoint {
+	var macro, heat []HeatmapPoint
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		switch key {
+		case "macroMarkersListEntity":
+			list, _ := v["markersList"].(map[string]interface{})
+			if t, _ := list["markerType"].(string); t != "" && t != "MARKER_TYPE_HEATMAP" {
+				return
+			}
+			markers, _ := list["markers"].([]interface{})
+			for _, m := range markers {
+				mm, _ := m.(map[string]interface{})
+				macro = append(macro, HeatmapPoint{
+					Start:     jsonMillis(mm["startMillis"]),
+					Duration:  jsonMillis(mm["durationMillis"]),
+					Intensity: jsonFloat(mm["intensityScoreNormalized"]),
+				})
+			}
+		case "heatMarkerRenderer":
+			heat = append(heat, HeatmapPoint{
+				Start:     jsonMillis(v["timeRangeStartMillis"]),
+				Duration:  jsonMillis(v["markerDurationMillis"]),
+				Intensity: jsonFloat(v["heatMarkerIntensityScoreNormalized"]),
+			})
+		}
+	})
+	if len(macro) > 0 {
+		return macro
+	}
+	return heat
+}
+
+//jsonFloat reads a number sent either as a json number or as a string.
+func jsonFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	}
+	return 0
+}
+
+func jsonMillis(v interface{}) time.Duration {
+	return time.Duration(jsonFloat(v)) * time.Millisecond
+}
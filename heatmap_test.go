@@ -0,0 +1,31 @@
+package youtube
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseHeatmap(t *testing.T) {
+	page := `<script>var ytInitialData = {"frameworkUpdates":{"entityBatchUpdate":{"mutations":[{"payload":{"macroMarkersListEntity":{"markersList":{
+"markerType":"MARKER_TYPE_HEATMAP","markers":[{"startMillis":"0","durationMillis":"2500","intensityScoreNormalized":1},
+{"startMillis":"2500","durationMillis":"2500","intensityScoreNormalized":0.25}]}}}}]}},
+"playerOverlays":{"heatMarkerRenderer":{"timeRangeStartMillis":0,"markerDurationMillis":5000,"heatMarkerIntensityScoreNormalized":0.5}}};</script>`
+	data, err := extractInitialData([]byte(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HeatmapPoint{
+		{Start: 0, Duration: 2500 * time.Millisecond, Intensity: 1},
+		{Start: 2500 * time.Millisecond, Duration: 2500 * time.Millisecond, Intensity: 0.25},
+	}
+	if got := parseHeatmap(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	old := map[string]interface{}{"heatMarkerRenderer": map[string]interface{}{
+		"timeRangeStartMillis": 5000.0, "markerDurationMillis": 5000.0, "heatMarkerIntensityScoreNormalized": 0.5}}
+	if got := parseHeatmap(old); len(got) != 1 || got[0].Start != 5*time.Second || got[0].Intensity != 0.5 {
+		t.Errorf("Wrong heat markers: %+v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package youtube
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+)
+
+//ConnHooks : Callbacks on the connections opened by the client, e.g. to record
+//the remote ip. Nil callbacks are skipped.
+type ConnHooks struct {
+	//OnDNSDone is called after resolving a host, with the Resolver option too.
+	OnDNSDone func(host string, addrs []net.IPAddr, err error)
+	//OnConnect is called after each tcp connection attempt.
+	OnConnect func(network, addr string, err error)
+	//OnTLSHandshake is called after the tls handshake of https connections.
+	OnTLSHandshake func(state tls.ConnectionState, err error)
+}
+
+//clientTrace turns the hooks into an httptrace.ClientTrace.
+func (h *ConnHooks) clientTrace() *httptrace.ClientTrace {
+	var host string
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			host = info.Host
+		},
+	}
+	if h.OnDNSDone != nil {
+		trace.DNSDone = func(info httptrace.DNSDoneInfo) {
+			h.OnDNSDone(host, info.Addrs, info.Err)
+		}
+	}
+	if h.OnConnect != nil {
+		trace.ConnectDone = h.OnConnect
+	}
+	if h.OnTLSHandshake != nil {
+		trace.TLSHandshakeDone = h.OnTLSHandshake
+	}
+	return trace
+}
+
+//hookTransport calls the hooks on the connections of its requests.
+type hookTransport struct {
+	base  http.RoundTripper
+	hooks *ConnHooks
+}
+
+func (t *hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := httptrace.WithClientTrace(req.Context(), t.hooks.clientTrace())
+	return t.base.RoundTrip(req.WithContext(ctx))
+}
@@ -0,0 +1,71 @@
+package youtube
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConnHooks(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	var connected string
+	var handshake bool
+	y := NewYoutube(false)
+	y.ConnHooks = &ConnHooks{
+		OnConnect: func(network, addr string, err error) {
+			if err == nil {
+				connected = addr
+			}
+		},
+		OnTLSHandshake: func(state tls.ConnectionState, err error) {
+			handshake = err == nil && state.HandshakeComplete
+		},
+	}
+	y.getClient().Transport.(*hookTransport).base.(*http.Transport).TLSClientConfig = ts.Client().Transport.(*http.Transport).TLSClientConfig
+	if _, err := y.fetch(ts.URL); err != nil {
+		t.Fatal(err)
+	}
+	if connected != ts.Listener.Addr().String() || !handshake {
+		t.Errorf("Hooks not called: connected=%q handshake=%v", connected, handshake)
+	}
+}
+
+func TestConnHooksResolver(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	var resolved string
+	y := NewYoutube(false)
+	y.Resolver = staticResolver{"127.0.0.1"}
+	y.ConnHooks = &ConnHooks{
+		OnDNSDone: func(host string, addrs []net.IPAddr, err error) {
+			if err == nil && len(addrs) == 1 {
+				resolved = host + "=" + addrs[0].IP.String()
+			}
+		},
+	}
+	if _, err := y.fetch("http://video.test:" + port); err != nil {
+		t.Fatal(err)
+	}
+	if resolved != "video.test=127.0.0.1" {
+		t.Errorf("Wrong resolution hook %q", resolved)
+	}
+}
+
+type staticResolver struct {
+	ip string
+}
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return []net.IPAddr{{IP: net.ParseIP(r.ip)}}, nil
+}
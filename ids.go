@@ -0,0 +1,171 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	//video ids are 64 bits in base64url, so the last of the 11 characters only
+	//carries 4 bits
+	videoIDRe    = regexp.MustCompile(`^[A-Za-z0-9_-]{10}[AEIMQUYcgkosw048]$`)
+	playlistIDRe = regexp.MustCompile(`^(?:PL|UU|LL|FL|RD|UL|OL|PU|EL)[A-Za-z0-9_-]{10,}$`)
+	idCharsRe    = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+)
+
+//specialPlaylistIDs are the short ids of the account playlists.
+var specialPlaylistIDs = map[string]bool{"WL": true, "LL": true, "LM": true}
+
+//videoPathPrefixes are the url paths followed by the video id.
+var videoPathPrefixes = []string{"/embed/", "/v/", "/e/", "/shorts/", "/live/"}
+
+//ValidateVideoID : Check the video id is 11 base64url characters.
+func ValidateVideoID(id string) error {
+	if !idCharsRe.MatchString(id) {
+		return errors.New("invalid characters in video id")
+	}
+	if len(id) != 11 {
+		return fmt.Errorf("the video id must be 11 characters long, got %d", len(id))
+	}
+	if !videoIDRe.MatchString(id) {
+		return errors.New("invalid last character in video id")
+	}
+	return nil
+}
+
+//ValidatePlaylistID : Check the playlist id has a known prefix and valid characters.
+func ValidatePlaylistID(id string) error {
+	if specialPlaylistIDs[id] {
+		return nil
+	}
+	if !idCharsRe.MatchString(id) {
+		return errors.New("invalid characters in playlist id")
+	}
+	if !playlistIDRe.MatchString(id) {
+		return fmt.Errorf("invalid playlist id '%s'", id)
+	}
+	return nil
+}
+
+//CanonicalWatchURL : The www.youtube.com watch url of a valid video id.
+func CanonicalWatchURL(id string) (string, error) {
+	if err := ValidateVideoID(id); err != nil {
+		return "", err
+	}
+	return "https://www.youtube.com/watch?v=" + id, nil
+}
+
+//ExtractVideoID : The video id of a youtube url or bare id.
+func ExtractVideoID(target string) (string, error) {
+	videoID, _, err := parseVideoURL(target)
+	if err == nil && videoID == "" {
+		err = fmt.Errorf("no video id in '%s'", target)
+	}
+	return videoID, err
+}
+
+//ExtractAllVideoIDs : The video ids of all the youtube urls in a text, e.g. pasted
+//from a chat, in order and without duplicates. The text is a bare id only when
+//it is a single word, since many words look like ids.
+func ExtractAllVideoIDs(text string) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, target := range findURLs(text) {
+		videoID, _, err := parseVideoURL(target)
+		if err == nil && videoID != "" && !seen[videoID] {
+			seen[videoID] = true
+			ids = append(ids, videoID)
+		}
+	}
+	return ids
+}
+
+//urlSeparators split the urls of a text, they are never part of a youtube url.
+const urlSeparators = " \t\r\n,;<>()[]{}\"'|"
+
+//findURLs returns the youtube urls of the text, or the text when it is a single word.
+func findURLs(text string) []string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(urlSeparators, r)
+	})
+	if len(words) == 1 {
+		return words
+	}
+	var urls []string
+	for _, word := range words {
+		if _, _, err := parseVideoURL(word); err == nil && strings.Contains(strings.ToLower(word), "youtu") {
+			urls = append(urls, word)
+		}
+	}
+	return urls
+}
+
+//videoHosts are the domains of the video urls.
+var videoHosts = []string{"youtube.com", "youtu.be", "youtube-nocookie.com"}
+
+//isVideoHost tells if the host is one of videoHosts or their subdomains, not
+//hosts only containing them like notyoutube.com or youtube.com.example.org.
+func isVideoHost(host string) bool {
+	for _, domain := range videoHosts {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+//parseVideoURL extracts the video and playlist ids of a youtube url or bare id.
+func parseVideoURL(target string) (videoID, playlistID string, err error) {
+	target = strings.TrimSpace(target)
+	if ValidateVideoID(target) == nil {
+		return target, "", nil
+	}
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", "", err
+	}
+	host := strings.ToLower(u.Hostname())
+	if !isVideoHost(host) {
+		return "", "", fmt.Errorf("not a youtube url: '%s'", target)
+	}
+
+	q := u.Query()
+	playlistID = q.Get("list")
+	if strings.HasPrefix(u.Path, "/browse/"+albumBrowsePrefix) {
+		//music.youtube.com/browse/MPREb_... album pages
+		playlistID = strings.TrimPrefix(u.Path, "/browse/")
+	}
+	switch {
+	case q.Get("v") != "":
+		videoID = q.Get("v")
+	case host == "youtu.be" || strings.HasSuffix(host, ".youtu.be"):
+		videoID = strings.Trim(u.Path, "/")
+	default:
+		for _, prefix := range videoPathPrefixes {
+			if strings.HasPrefix(u.Path, prefix) {
+				videoID = strings.SplitN(strings.TrimPrefix(u.Path, prefix), "/", 2)[0]
+			}
+		}
+	}
+
+	if videoID != "" {
+		if err = ValidateVideoID(videoID); err != nil {
+			return "", "", err
+		}
+	}
+	if playlistID != "" && !strings.HasPrefix(playlistID, albumBrowsePrefix) {
+		if err = ValidatePlaylistID(playlistID); err != nil {
+			return "", "", err
+		}
+	}
+	if videoID == "" && playlistID == "" {
+		return "", "", fmt.Errorf("no video id found in '%s'", target)
+	}
+	return videoID, playlistID, nil
+}
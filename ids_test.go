@@ -0,0 +1,93 @@
+package youtube
+
+import "testing"
+
+func TestValidateVideoID(t *testing.T) {
+	for _, id := range []string{"rFejpH_tAHM", "FHpvI8oGsuQ", "XbNghLqsVwU"} {
+		if err := ValidateVideoID(id); err != nil {
+			t.Errorf("%s should be valid: %s", id, err)
+		}
+	}
+	for _, id := range []string{"I8oGsuQ", "rFejpH_tAHMx", "rFejpH?tAHM", "rFejpH_tAHN", ""} {
+		if ValidateVideoID(id) == nil {
+			t.Errorf("%s should be invalid", id)
+		}
+	}
+}
+
+func TestValidatePlaylistID(t *testing.T) {
+	for _, id := range []string{"PL59FEE129ADFF2B12", "UUSRhwaM00ay0fasnsw6EXKA", "OLAK5uy_kdfbDw5OBLd4ZU94Bw6hOSNOzbHeoKnXw", "WL"} {
+		if err := ValidatePlaylistID(id); err != nil {
+			t.Errorf("%s should be valid: %s", id, err)
+		}
+	}
+	for _, id := range []string{"PL", "XX59FEE129ADFF2B12", "PL59FEE129&DFF2B12"} {
+		if ValidatePlaylistID(id) == nil {
+			t.Errorf("%s should be invalid", id)
+		}
+	}
+}
+
+func TestParseVideoURL(t *testing.T) {
+	for _, target := range []string{
+		"rFejpH_tAHM",
+		"https://www.youtube.com/watch?v=rFejpH_tAHM",
+		"youtube.com/watch?feature=share&v=rFejpH_tAHM",
+		"https://youtu.be/rFejpH_tAHM",
+		"https://www.youtube.com/embed/rFejpH_tAHM?start=10",
+		"https://www.youtube.com/shorts/rFejpH_tAHM",
+		"https://m.youtube.com/v/rFejpH_tAHM",
+		"https://www.youtube-nocookie.com/embed/rFejpH_tAHM",
+	} {
+		videoID, _, err := parseVideoURL(target)
+		if err != nil || videoID != "rFejpH_tAHM" {
+			t.Errorf("%s: wrong video id '%s', err=%v", target, videoID, err)
+		}
+	}
+	for _, target := range []string{
+		"https://www.youtube.com/watch?v=I8oGsuQ",
+		"https://vimeo.com/rFejpH_tAHM1",
+		"https://www.youtube.com/",
+		"https://notyoutube.com/watch?v=rFejpH_tAHM",
+		"https://youtube.com.example.org/watch?v=rFejpH_tAHM",
+		"https://youtu.berlin/rFejpH_tAHM",
+	} {
+		if _, _, err := parseVideoURL(target); err == nil {
+			t.Errorf("%s should fail", target)
+		}
+	}
+
+	if url, _ := CanonicalWatchURL("rFejpH_tAHM"); url != "https://www.youtube.com/watch?v=rFejpH_tAHM" {
+		t.Errorf("Wrong canonical url: %s", url)
+	}
+}
+
+func TestExtractVideoID(t *testing.T) {
+	if id, err := ExtractVideoID("https://youtu.be/rFejpH_tAHM?t=10"); err != nil || id != "rFejpH_tAHM" {
+		t.Errorf("got %s, err=%v", id, err)
+	}
+	if _, err := ExtractVideoID("https://www.youtube.com/playlist?list=PL59FEE129ADFF2B12"); err == nil {
+		t.Error("Playlist url without video should fail")
+	}
+}
+
+func TestExtractAllVideoIDs(t *testing.T) {
+	text := `Watch this (https://youtu.be/rFejpH_tAHM?t=10), and
+"https://www.youtube.com/watch?v=FHpvI8oGsuQ&list=PL59FEE129ADFF2B12"; programming is fun,
+again youtube.com/shorts/rFejpH_tAHM, not https://example.com/watch?v=XbNghLqsVwU`
+	ids := ExtractAllVideoIDs(text)
+	if len(ids) != 2 || ids[0] != "rFejpH_tAHM" || ids[1] != "FHpvI8oGsuQ" {
+		t.Errorf("Wrong ids %v", ids)
+	}
+	if ids := ExtractAllVideoIDs(" rFejpH_tAHM\n"); len(ids) != 1 {
+		t.Errorf("Wrong bare id %v", ids)
+	}
+
+	y := NewYoutube(false)
+	if err := y.findVideoID(text); err == nil {
+		t.Error("Several video urls should fail")
+	}
+	if err := y.findVideoID("see https://youtu.be/rFejpH_tAHM please"); err != nil || y.VideoID != "rFejpH_tAHM" {
+		t.Errorf("got %s, err=%v", y.VideoID, err)
+	}
+}
@@ -0,0 +1,47 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInnertubeNegotiation(t *testing.T) {
+	var fetches int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write([]byte(`ytcfg.set({"INNERTUBE_API_KEY":"homekey","INNERTUBE_CLIENT_VERSION":"2.20990101.00.00"});`))
+	}))
+	defer ts.Close()
+	defer func(u string) { innertubeHomeURL = u }(innertubeHomeURL)
+	innertubeHomeURL = ts.URL
+	storeInnertubeConfig(innertubeConfig{})
+	defer storeInnertubeConfig(innertubeConfig{})
+
+	y := NewYoutube(false)
+	ctx := context.Background()
+	cfg := y.innertubeConfigOf(ctx, []byte("<html>consent page</html>"))
+	if cfg.APIKey != "homekey" || cfg.ClientVersion != "2.20990101.00.00" {
+		t.Errorf("Wrong negotiated config %+v", cfg)
+	}
+	y.innertubeConfigOf(ctx, nil)
+	if fetches != 1 {
+		t.Errorf("The negotiated config isn't cached, %d fetches", fetches)
+	}
+
+	page := []byte(`"INNERTUBE_API_KEY":"pagekey","INNERTUBE_CLIENT_VERSION":"2.21000101.00.00"`)
+	if cfg = y.innertubeConfigOf(ctx, page); cfg.APIKey != "pagekey" {
+		t.Errorf("The page config isn't used: %+v", cfg)
+	}
+	if cfg = y.innertubeConfigOf(ctx, nil); cfg.APIKey != "pagekey" || fetches != 1 {
+		t.Errorf("The page config isn't cached: %+v", cfg)
+	}
+
+	negotiated.at = time.Now().Add(-innertubeConfigTTL)
+	ts.Close()
+	if cfg = y.innertubeConfigOf(ctx, nil); cfg.APIKey != "pagekey" {
+		t.Errorf("The expired config should be kept when the negotiation fails: %+v", cfg)
+	}
+}
@@ -0,0 +1,48 @@
+package youtube
+
+import (
+	"fmt"
+	"strconv"
+)
+
+//itagAllowed tells if the itag passes AllowedItags and BlockedItags.
+func (y *Youtube) itagAllowed(itag int) bool {
+	for _, blocked := range y.BlockedItags {
+		if itag == blocked {
+			return false
+		}
+	}
+	if len(y.AllowedItags) == 0 {
+		return true
+	}
+	for _, allowed := range y.AllowedItags {
+		if itag == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+//filterItags removes the streams and formats of the unwanted itags.
+func (y *Youtube) filterItags() {
+	if len(y.AllowedItags) == 0 && len(y.BlockedItags) == 0 {
+		return
+	}
+	formats := y.Formats[:0]
+	for _, f := range y.Formats {
+		if y.itagAllowed(f.Itag) {
+			formats = append(formats, f)
+		}
+	}
+	y.Formats = formats
+	streams := y.StreamList[:0]
+	for _, s := range y.StreamList {
+		itag, _ := strconv.Atoi(s["itag"])
+		if y.itagAllowed(itag) {
+			streams = append(streams, s)
+		} else {
+			y.log(fmt.Sprintf("Stream itag=%d excluded", itag))
+		}
+	}
+	y.StreamList = streams
+}
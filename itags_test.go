@@ -0,0 +1,32 @@
+package youtube
+
+import "testing"
+
+func TestFilterItags(t *testing.T) {
+	y := NewYoutube(false)
+	y.Formats = append([]Format(nil), testFormats...)
+	y.StreamList = []stream{{"itag": "22"}, {"itag": "18"}, {"itag": "43"}}
+	y.BlockedItags = []int{22}
+	y.filterItags()
+	if len(y.StreamList) != 2 || y.StreamList[0]["itag"] != "18" {
+		t.Errorf("Wrong streams: %v", y.StreamList)
+	}
+	for _, f := range y.Formats {
+		if f.Itag == 22 {
+			t.Error("Blocked format kept")
+		}
+	}
+
+	y.AllowedItags = []int{18, 22}
+	y.filterItags()
+	if len(y.StreamList) != 1 || y.StreamList[0]["itag"] != "18" {
+		t.Errorf("Wrong allowed streams: %v", y.StreamList)
+	}
+
+	y = NewYoutube(false)
+	y.AllowedItags = []int{18}
+	y.videoInfo = videoInfoFixture("")
+	if err := y.parseVideoInfo(); err == nil {
+		t.Error("Excluding every stream should fail")
+	}
+}
@@ -0,0 +1,342 @@
+package youtube
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//LiveRecording : What RecordLive wrote.
+type LiveRecording struct {
+	Segments int
+	Bytes    int64
+	//Duration is the media duration of the recorded segments.
+	Duration time.Duration
+	//Ended is set when the live stream ended, rather than the recording being stopped.
+	Ended bool
+	//Gaps are the segments missed while reconnecting.
+	Gaps []LiveGap
+	//ManifestRefreshes counts the manifests fetched again after their url expired.
+	ManifestRefreshes int
+}
+
+//LiveGap : Segments of a live stream missed by a recording, they left the live
+//playlist while the connection was lost.
+type LiveGap struct {
+	//From and To are the first and last missing sequence numbers.
+	From int64
+	To   int64
+	Time time.Time
+}
+
+//errLiveExpired is returned for the manifests and segments whose signed url
+//has expired, youtube rotates them every few hours of a live stream.
+var errLiveExpired = errors.New("live url expired")
+
+//DefaultLiveReconnect : How long a live recording retries after losing the connection.
+const DefaultLiveReconnect = 2 * time.Minute
+
+//hlsSegment is a media segment of a live playlist, by its sequence number.
+type hlsSegment struct {
+	seq      int64
+	duration time.Duration
+	url      string
+}
+
+//hlsMedia is a media playlist, which a live stream refreshes every target duration.
+type hlsMedia struct {
+	targetDuration time.Duration
+	segments       []hlsSegment
+	ended          bool
+}
+
+//RecordLive : Record the decoded live stream to destFile from its HLS manifest,
+//in the best variant, until the stream ends, the ctx is done, LiveDuration
+//has passed or LiveUntil is reached. The segments are MPEG-TS appended to the
+//file, it plays as is or can be remuxed. The recording is kept when it stops early.
+//After a network error, it retries for LiveReconnect from the last recorded
+//segment, the segments which left the playlist meanwhile are reported to OnLiveGap.
+//The manifest is fetched again from the video information when its url expires.
+func (y *Youtube) RecordLive(ctx context.Context, destFile string) (rec *LiveRecording, err error) {
+	if y.manifests.HLS == "" {
+		return nil, errors.New("no HLS manifest, the video is not live")
+	}
+	deadline := y.liveDeadline()
+	variant, err := y.liveVariant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := y.createOutput(destFile, false)
+	if err != nil {
+		return nil, err
+	}
+	//the upload of a Storage only succeeds or fails on Close
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	rec = &LiveRecording{}
+	next := int64(-1)
+	wait := 5 * time.Second
+	//failing is the time of the first error since the last progress, the
+	//errors are retried until LiveReconnect has passed
+	var failing time.Time
+	fail := func(err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if failing.IsZero() {
+			failing = time.Now()
+		}
+		if time.Since(failing) >= y.LiveReconnect {
+			return err
+		}
+		y.log(fmt.Sprintf("Live recording interrupted, reconnecting, err=%s", err))
+		return nil
+	}
+	expired := false
+	for {
+		var media *hlsMedia
+		if expired {
+			var refreshed string
+			if refreshed, err = y.refreshLiveVariant(ctx); err == nil {
+				variant, expired = refreshed, false
+				rec.ManifestRefreshes++
+			}
+		}
+		if !expired {
+			media, err = y.liveMedia(ctx, variant)
+		}
+		if err != nil {
+			expired = expired || errors.Is(err, errLiveExpired)
+			if err = fail(err); err != nil {
+				return rec, err
+			}
+		} else {
+			wait = media.targetDuration
+			if len(media.segments) > 0 && next >= 0 && media.segments[0].seq > next {
+				y.liveGap(rec, next, media.segments[0].seq-1)
+				next = media.segments[0].seq
+			}
+			for _, s := range media.segments {
+				if s.seq < next {
+					continue
+				}
+				if liveStopped(deadline) {
+					y.log(fmt.Sprintf("Live recording stopped after %d segments", rec.Segments))
+					return rec, nil
+				}
+				data, err := y.liveGet(ctx, s.url)
+				if err != nil {
+					//resume from this segment with the next playlist
+					expired = errors.Is(err, errLiveExpired)
+					err = fail(fmt.Errorf("get live segment %d error=%w", s.seq, err))
+					if err != nil {
+						return rec, err
+					}
+					break
+				}
+				if _, err = out.Write(data); err != nil {
+					return rec, err
+				}
+				failing = time.Time{}
+				rec.Segments++
+				rec.Bytes += int64(len(data))
+				rec.Duration += s.duration
+				next = s.seq + 1
+			}
+			if media.ended && (len(media.segments) == 0 || next > media.segments[len(media.segments)-1].seq) {
+				y.log(fmt.Sprintf("Live stream ended after %d segments", rec.Segments))
+				rec.Ended = true
+				return rec, nil
+			}
+		}
+		if liveStopped(deadline) {
+			return rec, nil
+		}
+		d := wait
+		if !deadline.IsZero() && time.Until(deadline) < d {
+			d = time.Until(deadline)
+		}
+		select {
+		case <-ctx.Done():
+			return rec, ctx.Err()
+		case <-time.After(d):
+		}
+	}
+}
+
+//liveVariant gets the HLS manifest and returns its best variant.
+func (y *Youtube) liveVariant(ctx context.Context) (string, error) {
+	master, err := y.liveGet(ctx, y.manifests.HLS)
+	if err != nil {
+		return "", fmt.Errorf("get HLS manifest error=%s", err)
+	}
+	variant, err := bestHLSVariant(y.manifests.HLS, master)
+	if err != nil {
+		return "", err
+	}
+	y.log(fmt.Sprintf("Record live variant=%s", variant))
+	return variant, nil
+}
+
+//refreshLiveVariant fetches the video information again for a new manifest
+//url, the sequence numbers of the segments go on in the new one.
+func (y *Youtube) refreshLiveVariant(ctx context.Context) (string, error) {
+	y.log("Live manifest url expired, fetching a new one")
+	y.manifests = ManifestURLs{}
+	if err := y.getVideoInfo(); err != nil {
+		return "", fmt.Errorf("getVideoInfo error=%s", err)
+	}
+	if err := y.parseVideoInfo(); err != nil {
+		return "", fmt.Errorf("parse video info failed, err=%s", err)
+	}
+	if y.manifests.HLS == "" {
+		return "", errors.New("no HLS manifest anymore, the live stream is over")
+	}
+	return y.liveVariant(ctx)
+}
+
+//liveMedia gets and parses the media playlist of the variant.
+func (y *Youtube) liveMedia(ctx context.Context, variant string) (*hlsMedia, error) {
+	body, err := y.liveGet(ctx, variant)
+	if err != nil {
+		return nil, fmt.Errorf("get live playlist error=%w", err)
+	}
+	return parseHLSMedia(variant, body)
+}
+
+//liveGap reports the segments from and to, included, which left the live
+//playlist before they could be fetched.
+func (y *Youtube) liveGap(rec *LiveRecording, from, to int64) {
+	gap := LiveGap{From: from, To: to, Time: time.Now()}
+	rec.Gaps = append(rec.Gaps, gap)
+	y.log(fmt.Sprintf("Live segments %d to %d are missing", from, to))
+	if y.OnLiveGap != nil {
+		y.OnLiveGap(gap)
+	}
+}
+
+//liveDeadline is the earliest of LiveUntil and LiveDuration from now, zero without limit.
+func (y *Youtube) liveDeadline() time.Time {
+	deadline := y.LiveUntil
+	if y.LiveDuration > 0 {
+		if end := time.Now().Add(y.LiveDuration); deadline.IsZero() || end.Before(deadline) {
+			deadline = end
+		}
+	}
+	return deadline
+}
+
+func liveStopped(deadline time.Time) bool {
+	return !deadline.IsZero() && !time.Now().Before(deadline)
+}
+
+//liveGet gets a manifest or a segment, without the hl and gl parameters of
+//the youtube pages since the googlevideo urls are signed.
+func (y *Youtube) liveGet(ctx context.Context, target string) ([]byte, error) {
+	if y.Timeouts.Request > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, y.Timeouts.Request)
+		defer cancel()
+	}
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := y.do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case 200:
+		return ioutil.ReadAll(resp.Body)
+	case 403, 404, 410:
+		return nil, fmt.Errorf("%w: status code %d", errLiveExpired, resp.StatusCode)
+	}
+	return nil, fmt.Errorf("non 200 status code received: %d", resp.StatusCode)
+}
+
+//bestHLSVariant picks the variant of the master playlist with the highest bandwidth.
+func bestHLSVariant(base string, master []byte) (string, error) {
+	var best string
+	bandwidth := -1
+	current := -1
+	scanner := bufio.NewScanner(bytes.NewReader(master))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF:"):
+			current = 0
+			for _, attr := range strings.Split(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"), ",") {
+				if strings.HasPrefix(attr, "BANDWIDTH=") {
+					current, _ = strconv.Atoi(strings.TrimPrefix(attr, "BANDWIDTH="))
+				}
+			}
+		case line != "" && !strings.HasPrefix(line, "#") && current >= 0:
+			if current > bandwidth {
+				best, bandwidth = resolveURL(base, line), current
+			}
+			current = -1
+		}
+	}
+	if best == "" {
+		return "", errors.New("no variant in the HLS manifest")
+	}
+	return best, nil
+}
+
+//parseHLSMedia reads the segments of a media playlist with their sequence numbers.
+func parseHLSMedia(base string, body []byte) (*hlsMedia, error) {
+	media := &hlsMedia{targetDuration: 5 * time.Second}
+	var seq int64
+	var duration time.Duration
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"):
+			seq, _ = strconv.ParseInt(strings.TrimPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"), 10, 64)
+		case strings.HasPrefix(line, "#EXT-X-TARGETDURATION:"):
+			if secs, err := strconv.ParseFloat(strings.TrimPrefix(line, "#EXT-X-TARGETDURATION:"), 64); err == nil && secs > 0 {
+				media.targetDuration = time.Duration(secs * float64(time.Second))
+			}
+		case strings.HasPrefix(line, "#EXTINF:"):
+			secs, _ := strconv.ParseFloat(strings.SplitN(strings.TrimPrefix(line, "#EXTINF:"), ",", 2)[0], 64)
+			duration = time.Duration(secs * float64(time.Second))
+		case line == "#EXT-X-ENDLIST":
+			media.ended = true
+		case line != "" && !strings.HasPrefix(line, "#"):
+			media.segments = append(media.segments, hlsSegment{seq: seq, duration: duration, url: resolveURL(base, line)})
+			seq++
+		}
+	}
+	if !strings.HasPrefix(string(body), "#EXTM3U") {
+		return nil, errors.New("invalid HLS playlist")
+	}
+	return media, nil
+}
+
+//resolveURL resolves the uri of a playlist line against the playlist url.
+func resolveURL(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := b.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return r.String()
+}
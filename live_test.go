@@ -0,0 +1,224 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const liveMaster = `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=290000,RESOLUTION=256x144
+low/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=4600000,RESOLUTION=1920x1080
+high/index.m3u8
+`
+
+//liveServer serves a live playlist which moves forward by one segment per
+//request, it ends after the segment end when end is positive. The playlist
+//requests for which down is true fail, the playlist still moves forward.
+func liveServer(first, end int64, down func(request int) bool) *httptest.Server {
+	var mu sync.Mutex
+	seq := first
+	var requests int
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/master.m3u8":
+			fmt.Fprint(w, liveMaster)
+		case r.URL.Path == "/high/index.m3u8":
+			mu.Lock()
+			defer mu.Unlock()
+			requests++
+			if down != nil && down(requests) {
+				seq++
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintf(w, "#EXTM3U\n#EXT-X-TARGETDURATION:0.02\n#EXT-X-MEDIA-SEQUENCE:%d\n", seq)
+			for i := seq; i < seq+2; i++ {
+				fmt.Fprintf(w, "#EXTINF:2.0,\n/seg/%d.ts\n", i)
+			}
+			if end > 0 && seq+1 >= end {
+				fmt.Fprint(w, "#EXT-X-ENDLIST\n")
+			}
+			seq++
+		case strings.HasPrefix(r.URL.Path, "/seg/"):
+			fmt.Fprintf(w, "seg%s;", strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/seg/"), ".ts"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestRecordLive(t *testing.T) {
+	ts := liveServer(10, 12, nil)
+	defer ts.Close()
+	dest := filepath.Join(t.TempDir(), "live.ts")
+	y := NewYoutube(false)
+	y.manifests.HLS = ts.URL + "/master.m3u8"
+	rec, err := y.RecordLive(context.Background(), dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(dest)
+	if string(b) != "seg10;seg11;seg12;" || !rec.Ended || rec.Segments != 3 || rec.Duration != 6*time.Second {
+		t.Errorf("Wrong recording %q: %+v", b, rec)
+	}
+}
+
+func TestDecodeLiveURL(t *testing.T) {
+	ts := liveServer(10, 12, nil)
+	defer ts.Close()
+	//a live answer has the manifests and no stream map
+	info := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, url.Values{"status": {"ok"}, "title": {"Live"}, "hlsvp": {ts.URL + "/master.m3u8"}}.Encode())
+	}))
+	defer info.Close()
+	defer func(u string) { videoInfoURL = u }(videoInfoURL)
+	videoInfoURL = info.URL + "/get_video_info"
+
+	dest := filepath.Join(t.TempDir(), "live.ts")
+	y := NewYoutube(false)
+	if err := y.DecodeURL("https://www.youtube.com/watch?v=rFejpH_tAHM"); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := y.RecordLive(context.Background(), dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(dest)
+	if string(b) != "seg10;seg11;seg12;" || !rec.Ended {
+		t.Errorf("Wrong recording %q: %+v", b, rec)
+	}
+}
+
+func TestRecordLiveDuration(t *testing.T) {
+	ts := liveServer(0, 0, nil)
+	defer ts.Close()
+	y := NewYoutube(false)
+	y.manifests.HLS = ts.URL + "/master.m3u8"
+	y.LiveDuration = 100 * time.Millisecond
+	start := time.Now()
+	rec, err := y.RecordLive(context.Background(), filepath.Join(t.TempDir(), "live.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Ended || rec.Segments == 0 || time.Since(start) > 2*time.Second {
+		t.Errorf("Wrong stopped recording: %+v after %s", rec, time.Since(start))
+	}
+}
+
+func TestRecordLiveReconnect(t *testing.T) {
+	ts := liveServer(0, 5, func(request int) bool { return request >= 2 && request <= 4 })
+	defer ts.Close()
+	dest := filepath.Join(t.TempDir(), "live.ts")
+	y := NewYoutube(false)
+	y.manifests.HLS = ts.URL + "/master.m3u8"
+	var gaps []LiveGap
+	y.OnLiveGap = func(g LiveGap) { gaps = append(gaps, g) }
+	rec, err := y.RecordLive(context.Background(), dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(dest)
+	if string(b) != "seg0;seg1;seg4;seg5;" || !rec.Ended {
+		t.Errorf("Wrong recording %q: %+v", b, rec)
+	}
+	if len(gaps) != 1 || gaps[0].From != 2 || gaps[0].To != 3 || len(rec.Gaps) != 1 {
+		t.Errorf("Wrong gaps: %+v", gaps)
+	}
+
+	//the connection doesn't come back
+	ts = liveServer(0, 0, func(request int) bool { return request >= 2 })
+	defer ts.Close()
+	y.manifests.HLS = ts.URL + "/master.m3u8"
+	y.LiveReconnect = 100 * time.Millisecond
+	if rec, err = y.RecordLive(context.Background(), dest); err == nil || rec.Segments != 2 {
+		t.Errorf("Lost connection should fail after the reconnect timeout, err=%v, %+v", err, rec)
+	}
+}
+
+func TestRecordLiveManifestRefresh(t *testing.T) {
+	var mu sync.Mutex
+	var seq int64
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/get_video_info":
+			fmt.Fprint(w, videoInfoFixture(`{"streamingData":{"hlsManifestUrl":"`+ts.URL+`/key2/master.m3u8"}}`))
+		case strings.HasSuffix(r.URL.Path, "/master.m3u8"):
+			fmt.Fprint(w, liveMaster)
+		case strings.HasSuffix(r.URL.Path, "/index.m3u8"):
+			//the first key expires at the segment 2
+			if strings.HasPrefix(r.URL.Path, "/key1/") && seq >= 2 {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprintf(w, "#EXTM3U\n#EXT-X-TARGETDURATION:0.02\n#EXT-X-MEDIA-SEQUENCE:%d\n", seq)
+			for i := seq; i < seq+2; i++ {
+				fmt.Fprintf(w, "#EXTINF:2.0,\n/seg/%d.ts\n", i)
+			}
+			if seq >= 3 {
+				fmt.Fprint(w, "#EXT-X-ENDLIST\n")
+			}
+			seq++
+		case strings.HasPrefix(r.URL.Path, "/seg/"):
+			fmt.Fprintf(w, "seg%s;", strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/seg/"), ".ts"))
+		}
+	}))
+	defer ts.Close()
+	defer func(u string) { videoInfoURL = u }(videoInfoURL)
+	videoInfoURL = ts.URL + "/get_video_info"
+
+	dest := filepath.Join(t.TempDir(), "live.ts")
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.manifests.HLS = ts.URL + "/key1/master.m3u8"
+	rec, err := y.RecordLive(context.Background(), dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(dest)
+	if string(b) != "seg0;seg1;seg2;seg3;seg4;" || rec.ManifestRefreshes != 1 || len(rec.Gaps) != 0 || !rec.Ended {
+		t.Errorf("Wrong recording %q: %+v", b, rec)
+	}
+}
+
+func TestBestHLSVariant(t *testing.T) {
+	v, err := bestHLSVariant("https://manifest.googlevideo.com/api/master.m3u8", []byte(liveMaster))
+	if err != nil || v != "https://manifest.googlevideo.com/api/high/index.m3u8" {
+		t.Errorf("Wrong variant %s, err=%v", v, err)
+	}
+}
+
+//failingCloseStorage accepts the writes and fails on Close, like a failed upload.
+type failingCloseStorage struct{}
+
+func (failingCloseStorage) Create(name string) (io.WriteCloser, error) { return failingClose{}, nil }
+
+type failingClose struct{}
+
+func (failingClose) Write(p []byte) (int, error) { return len(p), nil }
+func (failingClose) Close() error                { return errors.New("upload failed") }
+
+func TestRecordLiveCloseError(t *testing.T) {
+	ts := liveServer(10, 12, nil)
+	defer ts.Close()
+	y := NewYoutube(false)
+	y.Storage = failingCloseStorage{}
+	y.manifests.HLS = ts.URL + "/master.m3u8"
+	if _, err := y.RecordLive(context.Background(), "live.ts"); err == nil || err.Error() != "upload failed" {
+		t.Errorf("The Close error should be returned, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+//ChatKind : Type of a chat message.
+type ChatKind int
+
+const (
+	//ChatText : A plain text message.
+	ChatText ChatKind = iota
+	//ChatSuperChat : A paid message, the amount is in Amount.
+	ChatSuperChat
+	//ChatSuperSticker : A paid sticker, the amount is in Amount.
+	ChatSuperSticker
+	//ChatMembership : A new or renewed channel membership.
+	ChatMembership
+)
+
+//chatRenderers maps the chat item renderers to their kind.
+var chatRenderers = map[string]ChatKind{
+	"liveChatTextMessageRenderer":    ChatText,
+	"liveChatPaidMessageRenderer":    ChatSuperChat,
+	"liveChatPaidStickerRenderer":    ChatSuperSticker,
+	"liveChatMembershipItemRenderer": ChatMembership,
+}
+
+//DefaultChatPollInterval : Delay between live chat requests when youtube doesn't give one.
+var DefaultChatPollInterval = 5 * time.Second
+
+//ChatMessage : A message of a live chat or of its replay.
+type ChatMessage struct {
+	Kind            ChatKind
+	ID              string
+	Author          string
+	AuthorChannelID string
+	Message         string
+	//Amount is the displayed price of super chats and stickers, e.g. "$5.00".
+	Amount    string
+	Timestamp time.Time
+	//VideoOffset is the position in the video, only set in replays.
+	VideoOffset time.Duration
+}
+
+//GetLiveChatReplay : Retrieval every chat message of a finished live stream, in order.
+func (y *Youtube) GetLiveChatReplay(videoID string) ([]ChatMessage, error) {
+	ctx := context.Background()
+	page, err := y.request(ctx, "GET", "https://www.youtube.com/watch?v="+videoID, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := extractInitialData(page)
+	if err != nil {
+		return nil, err
+	}
+	token := liveChatToken(data)
+	if token == "" {
+		return nil, errors.New("no chat replay found for the video")
+	}
+	cfg := y.innertubeConfigOf(ctx, page)
+	var messages []ChatMessage
+	for pages := 1; token != ""; pages++ {
+		if data, err = y.innertubeContinuation(ctx, cfg, "live_chat/get_live_chat_replay", token); err != nil {
+			return nil, fmt.Errorf("chat replay page %d: %s", pages, err)
+		}
+		found := parseChatMessages(data)
+		if len(found) == 0 {
+			break
+		}
+		messages = append(messages, found...)
+		token = chatContinuationToken(data)
+	}
+	y.log(fmt.Sprintf("Found %d chat replay messages", len(messages)))
+	return messages, nil
+}
+
+//StreamLiveChat : Stream the chat of the decoded live video as messages arrive.
+//The messages channel is closed when the stream ends, ctx is done or a request
+//fails, in which case the error is sent on the error channel first.
+func (y *Youtube) StreamLiveChat(ctx context.Context) (<-chan ChatMessage, <-chan error) {
+	messages := make(chan ChatMessage, 100)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(messages)
+		defer close(errs)
+		if err := y.streamLiveChat(ctx, messages); err != nil && ctx.Err() == nil {
+			errs <- err
+		}
+	}()
+	return messages, errs
+}
+
+func (y *Youtube) streamLiveChat(ctx context.Context, messages chan<- ChatMessage) error {
+	if y.VideoID == "" {
+		return errors.New("no video decoded")
+	}
+	page, err := y.request(ctx, "GET", "https://www.youtube.com/watch?v="+y.VideoID, nil)
+	if err != nil {
+		return err
+	}
+	data, err := extractInitialData(page)
+	if err != nil {
+		return err
+	}
+	token := liveChatToken(data)
+	if token == "" {
+		return errors.New("no live chat found for the video")
+	}
+	cfg := y.innertubeConfigOf(ctx, page)
+	for token != "" {
+		if data, err = y.innertubeContinuation(ctx, cfg, "live_chat/get_live_chat", token); err != nil {
+			return err
+		}
+		for _, m := range parseChatItems(data) {
+			select {
+			case messages <- m:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		token = chatContinuationToken(data)
+		select {
+		case <-time.After(chatPollInterval(data)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	y.log("Live chat ended")
+	return nil
+}
+
+//chatPollInterval reads the delay youtube asks before the next chat request.
+func chatPollInterval(data interface{}) time.Duration {
+	interval := DefaultChatPollInterval
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		if key != "invalidationContinuationData" && key != "timedContinuationData" {
+			return
+		}
+		if ms, ok := v["timeoutMs"].(float64); ok && ms > 0 {
+			interval = time.Duration(ms) * time.Millisecond
+		}
+	})
+	return interval
+}
+
+//liveChatToken finds the token of the chat in the watch page. The last one of
+//the chat renderer is the complete chat, the first one the top chat.
+func liveChatToken(data interface{}) string {
+	var token string
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		if key != "liveChatRenderer" {
+			return
+		}
+		walkJSON(v, func(key string, v map[string]interface{}) {
+			if key == "reloadContinuationData" {
+				if t, ok := v["continuation"].(string); ok {
+					token = t
+				}
+			}
+		})
+	})
+	return token
+}
+
+//chatContinuationToken finds the token of the next chat page.
+func chatContinuationToken(data interface{}) string {
+	var token string
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		switch key {
+		case "liveChatReplayContinuationData", "invalidationContinuationData", "timedContinuationData":
+			if token == "" {
+				token, _ = v["continuation"].(string)
+			}
+		}
+	})
+	return token
+}
+
+//parseChatMessages collects the messages of a chat page. Replay messages are
+//wrapped in actions giving their offset in the video.
+func parseChatMessages(data interface{}) []ChatMessage {
+	var messages []ChatMessage
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		if key != "replayChatItemAction" {
+			return
+		}
+		offset, _ := strconv.ParseInt(jsonString(v["videoOffsetTimeMsec"]), 10, 64)
+		for _, m := range parseChatItems(v["actions"]) {
+			m.VideoOffset = time.Duration(offset) * time.Millisecond
+			messages = append(messages, m)
+		}
+	})
+	if len(messages) == 0 {
+		messages = parseChatItems(data)
+	}
+	return messages
+}
+
+//parseChatItems reads the chat renderers of the actions.
+func parseChatItems(actions interface{}) []ChatMessage {
+	var messages []ChatMessage
+	walkJSON(actions, func(key string, v map[string]interface{}) {
+		kind, ok := chatRenderers[key]
+		if !ok {
+			return
+		}
+		m := ChatMessage{
+			Kind:            kind,
+			ID:              jsonString(v["id"]),
+			Author:          jsonText(v["authorName"]),
+			AuthorChannelID: jsonString(v["authorExternalChannelId"]),
+			Message:         chatText(v["message"]),
+			Amount:          jsonText(v["purchaseAmountText"]),
+		}
+		if kind == ChatMembership && m.Message == "" {
+			m.Message = chatText(v["headerSubtext"])
+		}
+		if usec, err := strconv.ParseInt(jsonString(v["timestampUsec"]), 10, 64); err == nil {
+			m.Timestamp = time.UnixMicro(usec)
+		}
+		messages = append(messages, m)
+	})
+	return messages
+}
+
+//chatText reads a chat message, emojis are replaced by their shortcut.
+func chatText(v interface{}) string {
+	m, _ := v.(map[string]interface{})
+	runs, _ := m["runs"].([]interface{})
+	var text string
+	for _, r := range runs {
+		rm, _ := r.(map[string]interface{})
+		if t, ok := rm["text"].(string); ok {
+			text += t
+			continue
+		}
+		emoji, _ := rm["emoji"].(map[string]interface{})
+		if shortcuts, _ := emoji["shortcuts"].([]interface{}); len(shortcuts) > 0 {
+			s, _ := shortcuts[0].(string)
+			text += s
+		} else if id, ok := emoji["emojiId"].(string); ok {
+			text += id
+		}
+	}
+	if text == "" {
+		return jsonText(v)
+	}
+	return text
+}
+
+func jsonString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,82 @@
+package youtube
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const chatWatchPage = `<script>var ytInitialData = {"contents":{"twoColumnWatchNextResults":{"conversationBar":{"liveChatRenderer":{
+"continuations":[{"reloadContinuationData":{"continuation":"top"}}],
+"header":{"liveChatHeaderRenderer":{"viewSelector":{"sortFilterSubMenuRenderer":{"subMenuItems":[
+{"title":"Top chat replay","continuation":{"reloadContinuationData":{"continuation":"top"}}},
+{"title":"Live chat replay","continuation":{"reloadContinuationData":{"continuation":"all"}}}]}}}}}}}}};</script>`
+
+const chatReplayPage = `{"continuationContents":{"liveChatContinuation":{
+"continuations":[{"liveChatReplayContinuationData":{"continuation":"next"}}],
+"actions":[{"replayChatItemAction":{"videoOffsetTimeMsec":"1500","actions":[{"addChatItemAction":{"item":{"liveChatTextMessageRenderer":{
+"id":"msg1","authorName":{"simpleText":"Gopher"},"authorExternalChannelId":"UC123","timestampUsec":"1600000000000000",
+"message":{"runs":[{"text":"hello "},{"emoji":{"emojiId":"UCk","shortcuts":[":wave:"]}}]}}}}}]}}]}}}`
+
+func TestLiveChatReplay(t *testing.T) {
+	data, err := extractInitialData([]byte(chatWatchPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token := liveChatToken(data); token != "all" {
+		t.Errorf("Wrong chat token: %s", token)
+	}
+
+	var page interface{}
+	if err := json.Unmarshal([]byte(chatReplayPage), &page); err != nil {
+		t.Fatal(err)
+	}
+	messages := parseChatMessages(page)
+	if len(messages) != 1 {
+		t.Fatalf("Wrong messages: %+v", messages)
+	}
+	want := ChatMessage{
+		ID:              "msg1",
+		Author:          "Gopher",
+		AuthorChannelID: "UC123",
+		Message:         "hello :wave:",
+		Timestamp:       time.Unix(1600000000, 0),
+		VideoOffset:     1500 * time.Millisecond,
+	}
+	if m := messages[0]; m != want {
+		t.Errorf("got %+v, want %+v", m, want)
+	}
+	if token := chatContinuationToken(page); token != "next" {
+		t.Errorf("Wrong continuation token: %s", token)
+	}
+}
+
+const liveChatPage = `{"continuationContents":{"liveChatContinuation":{
+"continuations":[{"invalidationContinuationData":{"continuation":"next","timeoutMs":2000}}],
+"actions":[{"addChatItemAction":{"item":{"liveChatPaidMessageRenderer":{"id":"paid","authorName":{"simpleText":"Fan"},
+"purchaseAmountText":{"simpleText":"$5.00"},"message":{"runs":[{"text":"thanks"}]}}}}},
+{"addChatItemAction":{"item":{"liveChatMembershipItemRenderer":{"id":"member","authorName":{"simpleText":"New"},
+"headerSubtext":{"runs":[{"text":"Welcome to the club"}]}}}}}]}}}`
+
+func TestLiveChatKinds(t *testing.T) {
+	var page interface{}
+	if err := json.Unmarshal([]byte(liveChatPage), &page); err != nil {
+		t.Fatal(err)
+	}
+	messages := parseChatMessages(page)
+	if len(messages) != 2 {
+		t.Fatalf("Wrong messages: %+v", messages)
+	}
+	if m := messages[0]; m.Kind != ChatSuperChat || m.Amount != "$5.00" || m.Message != "thanks" {
+		t.Errorf("Wrong super chat: %+v", m)
+	}
+	if m := messages[1]; m.Kind != ChatMembership || m.Message != "Welcome to the club" {
+		t.Errorf("Wrong membership: %+v", m)
+	}
+	if token := chatContinuationToken(page); token != "next" {
+		t.Errorf("Wrong continuation token: %s", token)
+	}
+	if interval := chatPollInterval(page); interval != 2*time.Second {
+		t.Errorf("Wrong poll interval: %s", interval)
+	}
+}
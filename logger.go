@@ -0,0 +1,96 @@
+package youtube
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+//Logger : Receives the debug logs instead of the standard log package, e.g. to
+//forward them to a structured logger.
+type Logger interface {
+	Log(msg string)
+	LogRequest(r RequestLog)
+}
+
+//RequestLog : One http request of the client, logged in debug mode once its
+//answer body is closed.
+type RequestLog struct {
+	DownloadID string
+	Method     string
+	Host       string
+	//Status is zero when the request failed.
+	Status   int
+	Duration time.Duration
+	//Bytes of the answer body read.
+	Bytes int64
+	Err   error
+}
+
+func (r RequestLog) String() string {
+	s := fmt.Sprintf("%s %s status=%d duration=%s bytes=%d", r.Method, r.Host, r.Status, r.Duration.Round(time.Millisecond), r.Bytes)
+	if r.Err != nil {
+		s += fmt.Sprintf(" error=%s", r.Err)
+	}
+	return s
+}
+
+//logRequest sends the record to the Logger, or the standard log package.
+func (y *Youtube) logRequest(r RequestLog) {
+	if !y.DebugMode {
+		return
+	}
+	r.DownloadID = y.DownloadID
+	if y.Logger != nil {
+		y.Logger.LogRequest(r)
+		return
+	}
+	y.log(r.String())
+}
+
+//logResponse logs the request once the answer body is closed, in debug mode.
+func (y *Youtube) logResponse(req *http.Request, start time.Time, resp *http.Response, err error) {
+	if !y.DebugMode {
+		return
+	}
+	r := RequestLog{Method: req.Method, Host: req.URL.Host}
+	if err != nil {
+		r.Duration, r.Err = time.Since(start), err
+		y.logRequest(r)
+		return
+	}
+	r.Status = resp.StatusCode
+	resp.Body = &loggedBody{ReadCloser: resp.Body, done: func(bytes int64, err error) {
+		r.Duration, r.Bytes = time.Since(start), bytes
+		if err != io.EOF {
+			r.Err = err
+		}
+		y.logRequest(r)
+	}}
+}
+
+//loggedBody counts the bytes read and reports them on Close.
+type loggedBody struct {
+	io.ReadCloser
+	bytes int64
+	err   error
+	done  func(bytes int64, err error)
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes += int64(n)
+	if err != nil {
+		b.err = err
+	}
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	if b.done != nil {
+		b.done(b.bytes, b.err)
+		b.done = nil
+	}
+	return b.ReadCloser.Close()
+}
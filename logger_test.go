@@ -0,0 +1,57 @@
+package youtube
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingLogger struct {
+	mu       sync.Mutex
+	msgs     []string
+	requests []RequestLog
+}
+
+func (l *recordingLogger) Log(msg string) {
+	l.mu.Lock()
+	l.msgs = append(l.msgs, msg)
+	l.mu.Unlock()
+}
+
+func (l *recordingLogger) LogRequest(r RequestLog) {
+	l.mu.Lock()
+	l.requests = append(l.requests, r)
+	l.mu.Unlock()
+}
+
+func TestLoggerRequests(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+
+	l := &recordingLogger{}
+	y := NewYoutube(true)
+	y.Logger = l
+	y.DownloadID = "dl1"
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	if err := y.StartDownload(filepath.Join(t.TempDir(), "dl.mp4")); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.requests) != 1 {
+		t.Fatalf("Logged %d requests, want 1", len(l.requests))
+	}
+	r := l.requests[0]
+	if r.Method != "GET" || r.Status != 200 || r.Bytes != 10 || r.DownloadID != "dl1" || r.Err != nil {
+		t.Errorf("Wrong request log %+v", r)
+	}
+	if len(l.msgs) == 0 || !strings.HasPrefix(l.msgs[0], "[dl1] ") {
+		t.Errorf("Wrong messages %q", l.msgs)
+	}
+
+	l.requests = nil
+	y.DebugMode = false
+	y.StartDownload(filepath.Join(t.TempDir(), "dl.mp4"))
+	if len(l.requests) != 0 {
+		t.Error("Requests are logged out of debug mode")
+	}
+}
@@ -0,0 +1,90 @@
+package youtube
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//Loudness : Target of the EBU R128 loudness normalization.
+type Loudness struct {
+	//TargetLUFS is the integrated loudness, e.g. -16 for podcasts or -14 for music.
+	TargetLUFS float64
+	//TruePeak is the maximum true peak in dBTP.
+	TruePeak float64
+	//LRA is the loudness range target in LU.
+	LRA float64
+}
+
+//Loudness presets
+var (
+	LoudnessPodcast = Loudness{TargetLUFS: -16, TruePeak: -1.5, LRA: 11}
+	LoudnessMusic   = Loudness{TargetLUFS: -14, TruePeak: -1, LRA: 11}
+)
+
+//loudnormMeasure is the json printed by the first pass of the loudnorm filter.
+type loudnormMeasure struct {
+	InputI      string `json:"input_i"`
+	InputTP     string `json:"input_tp"`
+	InputLRA    string `json:"input_lra"`
+	InputThresh string `json:"input_thresh"`
+	Offset      string `json:"target_offset"`
+}
+
+//audioEncoders re-encode the normalized audio in a codec of the container.
+var audioEncoders = map[string]string{
+	".webm": "libopus", ".opus": "libopus", ".ogg": "libopus", ".mka": "libopus",
+	".mp3": "libmp3lame",
+}
+
+//normalize applies the loudness in two passes of the loudnorm filter, the
+//first measures the file so the second is a linear gain. The video and the
+//other streams are copied, the audio is re-encoded.
+func (y *Youtube) normalize(file string, l Loudness) (string, error) {
+	filter := fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=%g", l.TargetLUFS, l.TruePeak, l.LRA)
+	out, err := y.ffmpegOutput("info", "-i", file, "-vn", "-af", filter+":print_format=json", "-f", "null", "-")
+	if err != nil {
+		return "", err
+	}
+	m, err := parseLoudnorm(out)
+	if err != nil {
+		return "", err
+	}
+	y.log(fmt.Sprintf("Measured loudness=%s LUFS, true peak=%s dBTP", m.InputI, m.InputTP))
+	filter += fmt.Sprintf(":measured_I=%s:measured_TP=%s:measured_LRA=%s:measured_thresh=%s:offset=%s:linear=true",
+		m.InputI, m.InputTP, m.InputLRA, m.InputThresh, m.Offset)
+
+	ext := filepath.Ext(file)
+	encoder := audioEncoders[strings.ToLower(ext)]
+	if encoder == "" {
+		encoder = "aac"
+	}
+	tmp := strings.TrimSuffix(file, ext) + ".loudnorm" + ext
+	//loudnorm resamples to 192kHz, go back to the usual rate
+	err = y.runFFmpeg("-i", file, "-map", "0", "-c", "copy", "-af", filter, "-c:a", encoder, "-ar", "48000", tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return file, os.Rename(tmp, file)
+}
+
+//parseLoudnorm reads the last json object of the ffmpeg output.
+func parseLoudnorm(out []byte) (*loudnormMeasure, error) {
+	s := string(out)
+	start, end := strings.LastIndex(s, "{"), strings.LastIndex(s, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("no loudnorm measure in the ffmpeg output")
+	}
+	m := &loudnormMeasure{}
+	if err := json.Unmarshal([]byte(s[start:end+1]), m); err != nil {
+		return nil, fmt.Errorf("parse loudnorm measure error=%s", err)
+	}
+	if m.InputI == "-inf" {
+		return nil, errors.New("the audio is silent, it can't be normalized")
+	}
+	return m, nil
+}
@@ -0,0 +1,47 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const loudnormLog = `[Parsed_loudnorm_0 @ 0x1]
+{
+	"input_i" : "-27.61",
+	"input_tp" : "-4.47",
+	"input_lra" : "18.06",
+	"input_thresh" : "-39.20",
+	"output_i" : "-16.58",
+	"target_offset" : "0.58"
+}
+`
+
+func TestNormalize(t *testing.T) {
+	dir := t.TempDir()
+	y := NewYoutube(false)
+	y.FFmpegPath = filepath.Join(dir, "ffmpeg")
+	args := filepath.Join(dir, "args")
+	//the first pass prints the measure, the second copies the input
+	script := "#!/bin/sh\necho \"$@\" >> " + args + "\ncase \"$*\" in *print_format=json*) cat >&2 <<'EOF'\n" + loudnormLog +
+		"EOF\n;; *) for a; do last=\"$a\"; done; cp \"$5\" \"$last\";; esac\n"
+	if err := ioutil.WriteFile(y.FFmpegPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(dir, "talk.webm")
+	ioutil.WriteFile(src, []byte("audio"), 0644)
+
+	dest, err := y.normalize(src, LoudnessPodcast)
+	if err != nil || dest != src {
+		t.Fatalf("Wrong normalized file=%s, err=%v", dest, err)
+	}
+	data, _ := ioutil.ReadFile(args)
+	if !strings.Contains(string(data), "measured_I=-27.61:measured_TP=-4.47") || !strings.Contains(string(data), "-c:a libopus") {
+		t.Errorf("Wrong second pass: %s", data)
+	}
+
+	if _, err := parseLoudnorm([]byte(`{"input_i" : "-inf"}`)); err == nil {
+		t.Error("Silent audio should fail")
+	}
+}
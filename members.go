@@ -0,0 +1,15 @@
+package youtube
+
+import "strings"
+
+//membersOnlyBadge tells if the badges of a video renderer have the members
+//only one, e.g. {"metadataBadgeRenderer": {"style": "BADGE_STYLE_TYPE_MEMBERS_ONLY"}}.
+func membersOnlyBadge(badges interface{}) bool {
+	found := false
+	walkJSON(badges, func(key string, v map[string]interface{}) {
+		if style, ok := v["style"].(string); ok && strings.Contains(style, "MEMBERS_ONLY") {
+			found = true
+		}
+	})
+	return found
+}
@@ -0,0 +1,27 @@
+package youtube
+
+import "testing"
+
+const membersPlaylistPage = `<script>var ytInitialData = {"contents":[
+{"playlistVideoRenderer":{"videoId":"rFejpH_tAHM","title":{"simpleText":"Public"}}},
+{"playlistVideoRenderer":{"videoId":"FHpvI8oGsuQ","title":{"simpleText":"Perk"},
+	"badges":[{"metadataBadgeRenderer":{"style":"BADGE_STYLE_TYPE_MEMBERS_ONLY","label":"Members only"}}]}}]};</script>`
+
+func TestMembersOnlyEntries(t *testing.T) {
+	data, err := extractInitialData([]byte(membersPlaylistPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Playlist{}
+	parsePlaylistPage(data, p)
+	if len(p.Videos) != 2 || p.Videos[0].MembersOnly || !p.Videos[1].MembersOnly {
+		t.Fatalf("Wrong members only tags: %+v", p.Videos)
+	}
+
+	//without cookies the members only videos are skipped, before any request
+	y := NewYoutube(false)
+	y.Playlist = &Playlist{Videos: p.Videos[1:]}
+	if err := y.StartPlaylistDownload(t.TempDir()); err != nil {
+		t.Errorf("Members only video not skipped, err=%s", err)
+	}
+}
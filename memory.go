@@ -0,0 +1,43 @@
+package youtube
+
+import "fmt"
+
+//TooLargeError : Returned by DownloadToMemory when the video is bigger than the limit.
+type TooLargeError struct {
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("the download is larger than %d bytes", e.Limit)
+}
+
+//DownloadToMemory : Download the video into memory, failing with a *TooLargeError
+//as soon as it grows over maxBytes. Nothing is written to disk unless ffmpeg is
+//needed, and the subtitle sidecars are dropped. The next streams aren't tried
+//when one fails, its bytes are already in the buffer.
+func (y *Youtube) DownloadToMemory(maxBytes int64) ([]byte, error) {
+	name := y.VideoID + ".mp4"
+	buf := &memoryBuffer{limit: maxBytes}
+	storage := y.Storage
+	y.Storage = singleFileStorage{name: name, w: buf}
+	y.noStreamFallback = true
+	defer func() { y.Storage, y.noStreamFallback = storage, false }()
+	if _, err := y.Download(name); err != nil {
+		return nil, err
+	}
+	return buf.data, nil
+}
+
+//memoryBuffer refuses the writes going over its limit.
+type memoryBuffer struct {
+	data  []byte
+	limit int64
+}
+
+func (b *memoryBuffer) Write(p []byte) (int, error) {
+	if int64(len(b.data)+len(p)) > b.limit {
+		return 0, &TooLargeError{Limit: b.limit}
+	}
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
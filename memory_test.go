@@ -0,0 +1,67 @@
+package youtube
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+//truncatedServer announces "video data" but cuts the answer after "video".
+func truncatedServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "10")
+		io.WriteString(w, "video")
+	}))
+}
+
+func TestDownloadToMemory(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	b, err := y.DownloadToMemory(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "video data" {
+		t.Errorf("Downloaded %q", b)
+	}
+	if y.Storage != nil {
+		t.Error("The storage wasn't restored")
+	}
+
+	var tooLarge *TooLargeError
+	if _, err := y.DownloadToMemory(5); !errors.As(err, &tooLarge) || tooLarge.Limit != 5 {
+		t.Errorf("got error %v, want a TooLargeError", err)
+	}
+}
+
+func TestDownloadToMemoryNoFallback(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	cut := truncatedServer()
+	defer cut.Close()
+
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.StreamList = []stream{{"itag": "22", "url": cut.URL}, {"itag": "18", "url": ts.URL}}
+	if b, err := y.DownloadToMemory(100); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("got %q, error %v, want the error of the first stream", b, err)
+	}
+
+	//too large on the first stream, the second isn't tried
+	big := NewYoutube(false)
+	big.VideoID = "rFejpH_tAHM"
+	big.StreamList = []stream{{"itag": "22", "url": ts.URL}, {"itag": "18", "url": ts.URL}}
+	var tooLarge *TooLargeError
+	if _, err := big.DownloadToMemory(5); !errors.As(err, &tooLarge) || err.Error() != tooLarge.Error() {
+		t.Errorf("got error %v, want only a TooLargeError", err)
+	}
+	if big.noStreamFallback {
+		t.Error("The stream fallback wasn't restored")
+	}
+}
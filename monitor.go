@@ -0,0 +1,79 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//AvailabilityEvent : A change of the availability of a monitored video.
+type AvailabilityEvent struct {
+	VideoID string
+	Time    time.Time
+	//Available is false when the video can no longer be played, Err tells why.
+	Available bool
+	//Err is the PlayabilityError of an unavailable video, match its kind with
+	//errors.Is, e.g. ErrPrivateVideo or ErrVideoUnavailable for a deleted one.
+	Err error
+}
+
+//Monitor : Check the availability of the videos every interval until ctx is done,
+//then the channel is closed. The first check reports the videos already
+//unavailable, the next ones report the videos whose availability changed, e.g.
+//turned private, deleted or restored. Checks failing on the network are logged
+//and skipped.
+func (y *Youtube) Monitor(ctx context.Context, ids []string, interval time.Duration) <-chan AvailabilityEvent {
+	out := make(chan AvailabilityEvent)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		last := map[string]error{}
+		for {
+			for _, id := range ids {
+				kind, err := y.checkAvailability(id)
+				if err != nil && kind == nil {
+					y.log(fmt.Sprintf("Check availability of video %s error=%s", id, err))
+					continue
+				}
+				previous, seen := last[id]
+				last[id] = kind
+				if previous == kind && (seen || kind == nil) {
+					continue
+				}
+				select {
+				case out <- AvailabilityEvent{VideoID: id, Time: time.Now(), Available: kind == nil, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+//checkAvailability gets the video info with a copy of the downloader, the kind
+//of the PlayabilityError is nil when the video plays. Upcoming videos count
+//as available.
+func (y *Youtube) checkAvailability(id string) (kind error, err error) {
+	c := *y
+	c.VideoID = id
+	if err = c.getVideoInfo(); err != nil {
+		return nil, err
+	}
+	err = c.parseVideoInfo()
+	var perr *PlayabilityError
+	if !errors.As(err, &perr) {
+		return nil, err
+	}
+	if perr.Kind == ErrUpcoming {
+		return nil, nil
+	}
+	return perr.Kind, perr
+}
@@ -0,0 +1,50 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMonitor(t *testing.T) {
+	var private int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Get("video_id") == "FHpvI8oGsuQ":
+			w.Write([]byte("status=fail&reason=Video+unavailable"))
+		case atomic.LoadInt32(&private) == 1:
+			w.Write([]byte("status=fail&reason=This+video+is+private"))
+		default:
+			w.Write([]byte(videoInfoFixture("")))
+		}
+	}))
+	defer ts.Close()
+	defer func(u string) { videoInfoURL = u }(videoInfoURL)
+	videoInfoURL = ts.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := NewYoutube(false).Monitor(ctx, []string{"rFejpH_tAHM", "FHpvI8oGsuQ"}, 10*time.Millisecond)
+
+	e := <-events
+	if e.VideoID != "FHpvI8oGsuQ" || e.Available || !errors.Is(e.Err, ErrVideoUnavailable) {
+		t.Errorf("Wrong first event %+v", e)
+	}
+	atomic.StoreInt32(&private, 1)
+	e = <-events
+	if e.VideoID != "rFejpH_tAHM" || e.Available || !errors.Is(e.Err, ErrPrivateVideo) {
+		t.Errorf("Wrong private event %+v", e)
+	}
+	atomic.StoreInt32(&private, 0)
+	e = <-events
+	if e.VideoID != "rFejpH_tAHM" || !e.Available || e.Err != nil {
+		t.Errorf("Wrong restored event %+v", e)
+	}
+	cancel()
+	for range events {
+	}
+}
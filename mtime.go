@@ -0,0 +1,30 @@
+package youtube
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+//videoDate is the upload date of the video, or its publish date.
+func (y *Youtube) videoDate() time.Time {
+	if y.Microformat == nil {
+		return time.Time{}
+	}
+	if !y.Microformat.UploadDate.IsZero() {
+		return y.Microformat.UploadDate
+	}
+	return y.Microformat.PublishDate
+}
+
+//setMtime sets the modification time of the downloaded file to the video date.
+func (y *Youtube) setMtime(file string) {
+	date := y.videoDate()
+	if date.IsZero() {
+		y.log("No upload date to set as modification time")
+		return
+	}
+	if err := os.Chtimes(file, time.Now(), date); err != nil {
+		y.log(fmt.Sprintf("Set modification time of file=%s failed, error=%s", file, err))
+	}
+}
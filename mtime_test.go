@@ -0,0 +1,35 @@
+package youtube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSetMtime(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	dest := filepath.Join(t.TempDir(), "dl.mp4")
+
+	y := NewYoutube(false)
+	y.SetMtime = true
+	y.Microformat = &Microformat{
+		PublishDate: parseMicroformatDate("2020-10-21"),
+		UploadDate:  parseMicroformatDate("2020-10-20T01:02:03-07:00"),
+	}
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	if err := y.StartDownload(dest); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2020, 10, 20, 8, 2, 3, 0, time.UTC); !info.ModTime().Equal(want) {
+		t.Errorf("Modification time %s, want %s", info.ModTime(), want)
+	}
+	if d := y.Microformat.PublishDate; d != time.Date(2020, 10, 21, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Wrong publish date %s", d)
+	}
+}
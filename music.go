@@ -0,0 +1,29 @@
+package youtube
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	//albumBrowsePrefix starts the browse ids of youtube music album pages.
+	albumBrowsePrefix = "MPREb_"
+	//albumPlaylistPrefix starts the playlist ids holding the album tracks.
+	albumPlaylistPrefix = "OLAK5uy_"
+)
+
+var albumPlaylistRe = regexp.MustCompile(albumPlaylistPrefix + `[\w-]+`)
+
+//resolveAlbum finds the track playlist id of a youtube music album browse id.
+func (y *Youtube) resolveAlbum(browseID string) (string, error) {
+	body, err := y.fetch("https://music.youtube.com/browse/" + browseID)
+	if err != nil {
+		return "", err
+	}
+	playlistID := albumPlaylistRe.Find(body)
+	if playlistID == nil {
+		return "", fmt.Errorf("no album playlist found for '%s'", browseID)
+	}
+	y.log(fmt.Sprintf("Album %s resolved to playlist %s", browseID, playlistID))
+	return string(playlistID), nil
+}
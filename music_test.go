@@ -0,0 +1,27 @@
+package youtube
+
+import "testing"
+
+func TestFindAlbumID(t *testing.T) {
+	y := NewYoutube(false)
+	if err := y.findVideoID("https://music.youtube.com/browse/MPREb_4pL8gzRtw1p"); err != nil {
+		t.Fatal(err)
+	}
+	if y.VideoID != "" || y.PlaylistID != "MPREb_4pL8gzRtw1p" {
+		t.Errorf("Wrong ids, video=%s playlist=%s", y.VideoID, y.PlaylistID)
+	}
+
+	if err := y.findVideoID("https://music.youtube.com/playlist?list=OLAK5uy_kdfbDw5OBLd4ZU94Bw6hOSNOzbHeoKnXw"); err != nil {
+		t.Fatal(err)
+	}
+	if y.PlaylistID != "OLAK5uy_kdfbDw5OBLd4ZU94Bw6hOSNOzbHeoKnXw" {
+		t.Errorf("Wrong playlist id=%s", y.PlaylistID)
+	}
+}
+
+func TestAlbumPlaylistRe(t *testing.T) {
+	page := `"audioPlaylistId\x22:\x22OLAK5uy_kdfbDw5OBLd4ZU94Bw6hOSNOzbHeoKnXw\x22`
+	if id := albumPlaylistRe.FindString(page); id != "OLAK5uy_kdfbDw5OBLd4ZU94Bw6hOSNOzbHeoKnXw" {
+		t.Errorf("Wrong album playlist id=%s", id)
+	}
+}
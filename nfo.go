@@ -0,0 +1,65 @@
+package youtube
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+//nfoMovie is the Kodi movie nfo, read by Jellyfin, Emby and Plex with the
+//XBMCnfoMoviesImporter agent.
+type nfoMovie struct {
+	XMLName   xml.Name  `xml:"movie"`
+	Title     string    `xml:"title"`
+	Plot      string    `xml:"plot,omitempty"`
+	Premiered string    `xml:"premiered,omitempty"`
+	Year      int       `xml:"year,omitempty"`
+	Studio    string    `xml:"studio,omitempty"`
+	Genre     string    `xml:"genre,omitempty"`
+	Tags      []string  `xml:"tag"`
+	Runtime   int       `xml:"runtime,omitempty"`
+	Thumb     string    `xml:"thumb,omitempty"`
+	UniqueID  nfoUnique `xml:"uniqueid"`
+}
+
+type nfoUnique struct {
+	Type    string `xml:"type,attr"`
+	Default bool   `xml:"default,attr"`
+	ID      string `xml:",chardata"`
+}
+
+//nfoPath is the file name with the .nfo extension, which media servers match with the video.
+func nfoPath(file string) string {
+	return strings.TrimSuffix(file, filepath.Ext(file)) + ".nfo"
+}
+
+//writeNFO writes the nfo file of the downloaded file from the video details.
+func (y *Youtube) writeNFO(file string) error {
+	m := nfoMovie{UniqueID: nfoUnique{Type: "youtube", Default: true, ID: y.VideoID}}
+	if d := y.Details; d != nil {
+		m.Title, m.Plot, m.Studio, m.Tags, m.Thumb = d.Title, d.Description, d.Author, d.Keywords, d.Thumbnail
+		m.Runtime = int(d.Duration.Minutes() + 0.5)
+	}
+	if m.Title == "" {
+		m.Title = y.VideoID
+	}
+	if date := y.videoDate(); !date.IsZero() {
+		m.Premiered, m.Year = date.Format("2006-01-02"), date.Year()
+	}
+	if y.Microformat != nil {
+		m.Genre = y.Microformat.Category
+	}
+	data, err := xml.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := nfoPath(file)
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	if err = ioutil.WriteFile(name, data, 0644); err != nil {
+		return fmt.Errorf("write nfo of file=%s failed, error=%s", file, err)
+	}
+	y.log(fmt.Sprintf("Wrote nfo file=%s", name))
+	return nil
+}
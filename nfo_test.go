@@ -0,0 +1,32 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteNFO(t *testing.T) {
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.Details = &VideoDetails{Title: "Simplicity & Go", Author: "dotconferences", Description: "Rob Pike",
+		Keywords: []string{"go", "talk"}, Duration: 1382 * time.Second, Thumbnail: "https://i.ytimg.com/vi/rFejpH_tAHM/maxresdefault.jpg"}
+	y.Microformat = &Microformat{Category: "Education", UploadDate: time.Date(2015, 12, 2, 0, 0, 0, 0, time.UTC)}
+	file := filepath.Join(t.TempDir(), "dl.mp4")
+	if err := y.writeNFO(file); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(strings.TrimSuffix(file, ".mp4") + ".nfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<title>Simplicity &amp; Go</title>", "<premiered>2015-12-02</premiered>", "<studio>dotconferences</studio>",
+		"<tag>go</tag>\n  <tag>talk</tag>", "<runtime>23</runtime>", "<genre>Education</genre>",
+		`<uniqueid type="youtube" default="true">rFejpH_tAHM</uniqueid>`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("nfo has no %s:\n%s", want, b)
+		}
+	}
+}
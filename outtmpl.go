@@ -0,0 +1,82 @@
+package youtube
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//DefaultOutputTemplate : The names of the playlist videos when OutputTemplate is empty.
+const DefaultOutputTemplate = "{{.ID}}.{{.Ext}}"
+
+//OutputFields : The values of an output template. The strings are safe file
+//names once expanded, e.g. a "/" in a title doesn't create a directory.
+type OutputFields struct {
+	ID            string
+	Title         string
+	Channel       string
+	ChannelID     string
+	PlaylistID    string
+	PlaylistTitle string
+	//Index is the 1-based position in the playlist.
+	Index int
+	//Ext is the extension without the dot, e.g. mp4.
+	Ext string
+}
+
+//OutputPath : Expand a text/template of the path under dir, the "/" of the
+//template separate the directories, e.g. "{{.Channel}}/{{.PlaylistTitle}}/{{.Index}} - {{.Title}}.{{.Ext}}"
+//or "{{printf "%03d" .Index}} - {{.Title}}.{{.Ext}}". Each directory and the
+//file name are safe file names, "_" when empty, and the path can't leave dir.
+func OutputPath(dir, tmpl string, f OutputFields) (string, error) {
+	t, err := template.New("output").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid output template error=%s", err)
+	}
+	safe := f
+	for _, s := range []*string{&safe.ID, &safe.Title, &safe.Channel, &safe.ChannelID, &safe.PlaylistID, &safe.PlaylistTitle, &safe.Ext} {
+		*s = SafeFileName(*s)
+	}
+	var b strings.Builder
+	if err = t.Execute(&b, safe); err != nil {
+		return "", fmt.Errorf("output template error=%s", err)
+	}
+	parts := strings.Split(filepath.ToSlash(b.String()), "/")
+	for i, part := range parts {
+		part = SafeFileName(part)
+		if part == "" || part == "." || part == ".." {
+			part = "_"
+		}
+		parts[i] = part
+	}
+	return filepath.Join(append([]string{dir}, parts...)...), nil
+}
+
+//UniquePath : The path, or the path with " [id]" before its extension when it
+//is already in used, e.g. by another video of the same title, then it is added to used.
+func UniquePath(path, id string, used map[string]bool) string {
+	if used[path] {
+		ext := filepath.Ext(path)
+		path = fmt.Sprintf("%s [%s]%s", strings.TrimSuffix(path, ext), id, ext)
+	}
+	used[path] = true
+	return path
+}
+
+//outputFields are the values of the playlist entry, with the video details
+//once its information is parsed.
+func (y *Youtube) outputFields(v PlaylistEntry) OutputFields {
+	f := OutputFields{ID: v.ID, Title: v.Title, PlaylistID: y.Playlist.ID, PlaylistTitle: y.Playlist.Title,
+		Index: v.Index, Ext: "mp4"}
+	if d := y.Details; d != nil {
+		if d.Title != "" {
+			f.Title = d.Title
+		}
+		f.Channel, f.ChannelID = d.Author, d.ChannelID
+	}
+	if f.Title == "" {
+		f.Title = v.ID
+	}
+	return f
+}
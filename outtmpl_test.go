@@ -0,0 +1,36 @@
+package youtube
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOutputPath(t *testing.T) {
+	f := OutputFields{ID: "rFejpH_tAHM", Title: "Simplicity/Complexity", Channel: "dotconferences",
+		PlaylistTitle: "dotGo 2015", Index: 3, Ext: "mp4"}
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"{{.Channel}}/{{.PlaylistTitle}}/{{.Index}} - {{.Title}}.{{.Ext}}", "dotconferences/dotGo 2015/3 - Simplicity_Complexity.mp4"},
+		{`{{printf "%03d" .Index}}.{{.Ext}}`, "003.mp4"},
+		{"../{{.ChannelID}}/{{.ID}}.{{.Ext}}", "_/_/rFejpH_tAHM.mp4"},
+	}
+	for _, test := range tests {
+		got, err := OutputPath("videos", test.tmpl, f)
+		if err != nil || got != filepath.Join("videos", filepath.FromSlash(test.want)) {
+			t.Errorf("%s: got %s, err=%v, want %s", test.tmpl, got, err, test.want)
+		}
+	}
+	if _, err := OutputPath("videos", "{{.Uploader}}", f); err == nil {
+		t.Error("Unknown fields should fail")
+	}
+
+	used := map[string]bool{}
+	if p := UniquePath("a/Same.mp4", "rFejpH_tAHM", used); p != "a/Same.mp4" {
+		t.Errorf("Wrong first path %s", p)
+	}
+	if p := UniquePath("a/Same.mp4", "FHpvI8oGsuQ", used); p != "a/Same [FHpvI8oGsuQ].mp4" {
+		t.Errorf("Wrong colliding path %s", p)
+	}
+}
@@ -0,0 +1,35 @@
+package youtube
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+//PipeTo : Download the video into the stdin of the command, e.g. "mpv -" to play
+//it without saving or "ffmpeg -i - ..." to transcode it on the fly. The download
+//goes at the pace the command reads, then the command is waited for. The next
+//streams aren't tried when one fails, the command already read a part of it.
+func (y *Youtube) PipeTo(cmd *exec.Cmd) error {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("start %s error=%s", cmd.Path, err)
+	}
+	name := y.VideoID + ".mp4"
+	storage := y.Storage
+	y.Storage = singleFileStorage{name: name, w: stdin}
+	y.noStreamFallback = true
+	_, err = y.Download(name)
+	y.Storage, y.noStreamFallback = storage, false
+	stdin.Close()
+	waitErr := cmd.Wait()
+	if err != nil {
+		return err
+	}
+	if waitErr != nil {
+		return fmt.Errorf("%s error=%s", cmd.Path, waitErr)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package youtube
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestPipeTo(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	out := filepath.Join(t.TempDir(), "out")
+
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	if err := y.PipeTo(exec.Command("sh", "-c", "cat > "+out)); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(out); string(b) != "video data" {
+		t.Errorf("The command read %q", b)
+	}
+	if err := y.PipeTo(exec.Command("sh", "-c", "cat > /dev/null; exit 3")); err == nil {
+		t.Error("The command failure isn't reported")
+	}
+}
+
+func TestPipeToFirstStreamFails(t *testing.T) {
+	ts := existsServer()
+	defer ts.Close()
+	cut := truncatedServer()
+	defer cut.Close()
+	out := filepath.Join(t.TempDir(), "out")
+
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.StreamList = []stream{{"itag": "22", "url": cut.URL}, {"itag": "18", "url": ts.URL}}
+	if err := y.PipeTo(exec.Command("sh", "-c", "cat > "+out)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("got error %v, want the error of the first stream", err)
+	}
+	if b, _ := ioutil.ReadFile(out); string(b) != "video" {
+		t.Errorf("The command read %q, want only the first stream", b)
+	}
+}
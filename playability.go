@@ -0,0 +1,104 @@
+package youtube
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+//Kinds of PlayabilityError, to test with errors.Is.
+var (
+	ErrLoginRequired    = errors.New("login required")
+	ErrAgeRestricted    = errors.New("age restricted")
+	ErrPrivateVideo     = errors.New("private video")
+	ErrRegionBlocked    = errors.New("blocked in this region")
+	ErrUpcoming         = errors.New("upcoming video")
+	ErrVideoUnavailable = errors.New("video unavailable")
+	ErrMembersOnly      = errors.New("members only video")
+)
+
+//PlayabilityError : Why youtube refuses to play the video, from the playabilityStatus
+//of its player response, with a Hint on what can be done about it.
+type PlayabilityError struct {
+	//Status is LOGIN_REQUIRED, UNPLAYABLE, ERROR, LIVE_STREAM_OFFLINE...
+	Status    string
+	Reason    string
+	Subreason string
+	Hint      string
+	//Kind is one of the Err variables of the playability errors.
+	Kind error
+}
+
+func (e *PlayabilityError) Error() string {
+	msg := e.Reason
+	if msg == "" {
+		msg = e.Kind.Error()
+	}
+	if e.Subreason != "" {
+		msg += " (" + e.Subreason + ")"
+	}
+	if e.Hint != "" {
+		msg += ", " + e.Hint
+	}
+	return msg
+}
+
+//Is : Match the Kind of the error.
+func (e *PlayabilityError) Is(target error) bool {
+	return e.Kind == target
+}
+
+//playability is the playabilityStatus of the player response.
+type playability struct {
+	status    string
+	reason    string
+	subreason string
+	ageGate   bool
+}
+
+//playabilityError maps the playability status to a typed error and a hint.
+func (y *Youtube) playabilityError() error {
+	p := y.playability
+	e := &PlayabilityError{Status: p.status, Reason: p.reason, Subreason: p.subreason}
+	reason := strings.ToLower(p.reason + " " + p.subreason)
+	switch {
+	case p.status == "AGE_CHECK_REQUIRED" || p.ageGate || strings.Contains(reason, "confirm your age") ||
+		strings.Contains(reason, "age-restricted") || strings.Contains(reason, "inappropriate"):
+		e.Kind, e.Hint = ErrAgeRestricted, "provide the cookies of a signed in adult account"
+	case strings.Contains(reason, "members-only") || strings.Contains(reason, "members only"):
+		e.Kind, e.Hint = ErrMembersOnly, "provide the cookies of an account that is a member of the channel"
+	case strings.Contains(reason, "private"):
+		e.Kind, e.Hint = ErrPrivateVideo, "only the accounts the owner shared it with can download it, provide their cookies"
+	case strings.Contains(reason, "bot"):
+		e.Kind, e.Hint = ErrLoginRequired, "youtube suspects a bot, slow down or provide the cookies of a signed in account"
+	case p.status == "LOGIN_REQUIRED":
+		e.Kind, e.Hint = ErrLoginRequired, "provide the cookies of a signed in account"
+	case strings.Contains(reason, "country") || strings.Contains(reason, "region"):
+		e.Kind, e.Hint = ErrRegionBlocked, "use a proxy or a source address in another country"
+		if y.Microformat != nil && len(y.Microformat.AvailableCountries) > 0 && len(y.Microformat.AvailableCountries) <= 10 {
+			e.Hint = "use a proxy in one of " + strings.Join(y.Microformat.AvailableCountries, ", ")
+		}
+	case p.status == "LIVE_STREAM_OFFLINE":
+		e.Kind, e.Hint = ErrUpcoming, "the live stream is offline, retry once it starts"
+	default:
+		e.Kind, e.Hint = ErrVideoUnavailable, "check the video plays in a browser"
+	}
+	return e
+}
+
+//upcomingError explains why an upcoming premiere or live stream has no stream.
+func (y *Youtube) upcomingError() error {
+	e := &PlayabilityError{Status: y.playability.status, Reason: y.playability.reason, Kind: ErrUpcoming}
+	if e.Reason == "" {
+		e.Reason = "the video is an upcoming premiere or live stream"
+	}
+	var hints []string
+	if !y.ScheduledStart.IsZero() {
+		hints = append(hints, "scheduled at "+y.ScheduledStart.Format(time.RFC3339))
+	}
+	if y.TrailerVideoID != "" {
+		hints = append(hints, "its trailer "+y.TrailerVideoID+" can be downloaded with DownloadTrailer")
+	}
+	e.Hint = strings.Join(hints, ", ")
+	return e
+}
@@ -0,0 +1,35 @@
+package youtube
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestPlayabilityErrors(t *testing.T) {
+	tests := []struct {
+		status string
+		kind   error
+		hint   string
+	}{
+		{`{"status":"LOGIN_REQUIRED","reason":"Sign in to confirm your age","desktopLegacyAgeGateReason":1}`, ErrAgeRestricted, "adult"},
+		{`{"status":"LOGIN_REQUIRED","reason":"Sign in to confirm you’re not a bot"}`, ErrLoginRequired, "slow down"},
+		{`{"status":"LOGIN_REQUIRED","reason":"This video is private"}`, ErrPrivateVideo, "owner"},
+		{`{"status":"UNPLAYABLE","reason":"Video unavailable","errorScreen":{"playerErrorMessageRenderer":{
+			"subreason":{"runs":[{"text":"The uploader has not made this video available in your country"}]}}}}`, ErrRegionBlocked, "proxy"},
+		{`{"status":"ERROR","reason":"Video unavailable"}`, ErrVideoUnavailable, "browser"},
+		{`{"status":"UNPLAYABLE","reason":"Join this channel to get access to members-only content like this video, and other exclusive perks."}`, ErrMembersOnly, "member"},
+	}
+	for _, test := range tests {
+		v, _ := url.ParseQuery(videoInfoFixture(`{"playabilityStatus":` + test.status + `}`))
+		v.Del("url_encoded_fmt_stream_map")
+		y := NewYoutube(false)
+		y.videoInfo = v.Encode()
+		err := y.parseVideoInfo()
+		var pe *PlayabilityError
+		if !errors.Is(err, test.kind) || !errors.As(err, &pe) || !strings.Contains(pe.Hint, test.hint) {
+			t.Errorf("%s: got %v, want %v with a hint about %s", test.status, err, test.kind, test.hint)
+		}
+	}
+}
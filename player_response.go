@@ -0,0 +1,193 @@
+package youtube
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//playerResponse is the json found in the player_response field of the video information.
+type playerResponse struct {
+	PlayabilityStatus struct {
+		Status                     string `json:"status"`
+		Reason                     string `json:"reason"`
+		DesktopLegacyAgeGateReason int    `json:"desktopLegacyAgeGateReason"`
+		ErrorScreen                struct {
+			ErrorMessage struct {
+				Subreason interface{} `json:"subreason"`
+			} `json:"playerErrorMessageRenderer"`
+			LegacyTrailer struct {
+				TrailerVideoID string `json:"trailerVideoId"`
+			} `json:"playerLegacyDesktopYpcTrailerRenderer"`
+			Trailer struct {
+				PlayerVars string `json:"playerVars"`
+			} `json:"ypcTrailerRenderer"`
+		} `json:"errorScreen"`
+		LiveStreamability struct {
+			Renderer struct {
+				OfflineSlate struct {
+					Renderer struct {
+						ScheduledStartTime string `json:"scheduledStartTime"`
+					} `json:"liveStreamOfflineSlateRenderer"`
+				} `json:"offlineSlate"`
+			} `json:"liveStreamabilityRenderer"`
+		} `json:"liveStreamability"`
+	} `json:"playabilityStatus"`
+	VideoDetails struct {
+		Title            string   `json:"title"`
+		Author           string   `json:"author"`
+		ChannelID        string   `json:"channelId"`
+		LengthSeconds    string   `json:"lengthSeconds"`
+		ShortDescription string   `json:"shortDescription"`
+		Keywords         []string `json:"keywords"`
+		Thumbnail        struct {
+			Thumbnails []struct {
+				URL    string `json:"url"`
+				Width  int    `json:"width"`
+				Height int    `json:"height"`
+			} `json:"thumbnails"`
+		} `json:"thumbnail"`
+		IsUpcoming bool `json:"isUpcoming"`
+	} `json:"videoDetails"`
+	StreamingData struct {
+		DashManifestURL string `json:"dashManifestUrl"`
+		HlsManifestURL  string `json:"hlsManifestUrl"`
+	} `json:"streamingData"`
+	Captions struct {
+		Renderer struct {
+			CaptionTracks []struct {
+				BaseURL        string      `json:"baseUrl"`
+				Name           interface{} `json:"name"`
+				LanguageCode   string      `json:"languageCode"`
+				Kind           string      `json:"kind"`
+				IsTranslatable bool        `json:"isTranslatable"`
+			} `json:"captionTracks"`
+			TranslationLanguages []struct {
+				LanguageCode string `json:"languageCode"`
+			} `json:"translationLanguages"`
+		} `json:"playerCaptionsTracklistRenderer"`
+	} `json:"captions"`
+	Microformat struct {
+		Renderer struct {
+			AvailableCountries []string `json:"availableCountries"`
+			IsFamilySafe       bool     `json:"isFamilySafe"`
+			IsUnlisted         bool     `json:"isUnlisted"`
+			Category           string   `json:"category"`
+			PublishDate        string   `json:"publishDate"`
+			UploadDate         string   `json:"uploadDate"`
+		} `json:"playerMicroformatRenderer"`
+	} `json:"microformat"`
+}
+
+//VideoDetails : Title and channel of the video.
+type VideoDetails struct {
+	Title     string
+	Author    string
+	ChannelID string
+	//Duration is zero for the live streams.
+	Duration    time.Duration
+	Description string
+	Keywords    []string
+	//Thumbnail is the url of the largest thumbnail.
+	Thumbnail string
+}
+
+//Microformat : Region, rating and dates of the video.
+type Microformat struct {
+	//AvailableCountries lists the ISO 3166 codes of the countries allowed to
+	//watch the video, empty when youtube doesn't restrict it.
+	AvailableCountries []string
+	IsFamilySafe       bool
+	//AgeRestricted is set when youtube requires to sign in to confirm the age.
+	AgeRestricted bool
+	IsUnlisted    bool
+	Category      string
+	//PublishDate and UploadDate are zero when youtube doesn't give them.
+	PublishDate time.Time
+	UploadDate  time.Time
+}
+
+//AvailableIn : Check if the video can be watched from the country code.
+func (m *Microformat) AvailableIn(country string) bool {
+	if len(m.AvailableCountries) == 0 {
+		return true
+	}
+	for _, c := range m.AvailableCountries {
+		if strings.EqualFold(c, country) {
+			return true
+		}
+	}
+	return false
+}
+
+func (y *Youtube) parsePlayerResponse(raw string) {
+	var pr playerResponse
+	if err := json.Unmarshal([]byte(raw), &pr); err != nil {
+		y.log(fmt.Sprintf("An error occured while decoding the player response: %s", err))
+		return
+	}
+	ps := pr.PlayabilityStatus
+	y.playability = playability{
+		status:    ps.Status,
+		reason:    ps.Reason,
+		subreason: jsonText(ps.ErrorScreen.ErrorMessage.Subreason),
+		ageGate:   ps.DesktopLegacyAgeGateReason != 0,
+	}
+	d := pr.VideoDetails
+	y.Details = &VideoDetails{Title: d.Title, Author: d.Author, ChannelID: d.ChannelID,
+		Description: d.ShortDescription, Keywords: d.Keywords}
+	if secs, err := strconv.ParseInt(d.LengthSeconds, 10, 64); err == nil {
+		y.Details.Duration = time.Duration(secs) * time.Second
+	}
+	var area int
+	for _, t := range d.Thumbnail.Thumbnails {
+		if t.Width*t.Height >= area {
+			area, y.Details.Thumbnail = t.Width*t.Height, t.URL
+		}
+	}
+	y.IsUpcoming = d.IsUpcoming
+	if secs, err := strconv.ParseInt(ps.LiveStreamability.Renderer.OfflineSlate.Renderer.ScheduledStartTime, 10, 64); err == nil {
+		y.ScheduledStart = time.Unix(secs, 0)
+	}
+	y.TrailerVideoID = ps.ErrorScreen.LegacyTrailer.TrailerVideoID
+	y.manifests = ManifestURLs{DASH: pr.StreamingData.DashManifestURL, HLS: pr.StreamingData.HlsManifestURL}
+	if vars, err := url.ParseQuery(ps.ErrorScreen.Trailer.PlayerVars); err == nil && y.TrailerVideoID == "" {
+		y.TrailerVideoID = vars.Get("video_id")
+	}
+
+	for _, ct := range pr.Captions.Renderer.CaptionTracks {
+		y.CaptionTracks = append(y.CaptionTracks, CaptionTrack{
+			Language:      ct.LanguageCode,
+			Name:          jsonText(ct.Name),
+			AutoGenerated: ct.Kind == "asr",
+			Translatable:  ct.IsTranslatable,
+			URL:           ct.BaseURL,
+		})
+	}
+	for _, tl := range pr.Captions.Renderer.TranslationLanguages {
+		y.TranslationLanguages = append(y.TranslationLanguages, tl.LanguageCode)
+	}
+
+	r := pr.Microformat.Renderer
+	y.Microformat = &Microformat{
+		AvailableCountries: r.AvailableCountries,
+		IsFamilySafe:       r.IsFamilySafe,
+		AgeRestricted:      pr.PlayabilityStatus.DesktopLegacyAgeGateReason != 0,
+		IsUnlisted:         r.IsUnlisted,
+		Category:           r.Category,
+		PublishDate:        parseMicroformatDate(r.PublishDate),
+		UploadDate:         parseMicroformatDate(r.UploadDate),
+	}
+}
+
+//parseMicroformatDate reads the "2006-01-02" dates, or RFC 3339 times.
+func parseMicroformatDate(date string) time.Time {
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		return t
+	}
+	t, _ := time.Parse("2006-01-02", date)
+	return t
+}
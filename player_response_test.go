@@ -0,0 +1,67 @@
+package youtube
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const streamMapFixture = "itag=22&quality=hd720&type=video%2Fmp4&url=https%3A%2F%2Fr1.googlevideo.com%2Fvideoplayback%3Fitag%3D22"
+
+func videoInfoFixture(playerResponse string) string {
+	v := url.Values{}
+	v.Set("status", "ok")
+	v.Set("title", "Simplicity is Complicated")
+	v.Set("author", "dotconferences")
+	v.Set("url_encoded_fmt_stream_map", streamMapFixture)
+	if playerResponse != "" {
+		v.Set("player_response", playerResponse)
+	}
+	return v.Encode()
+}
+
+func TestParseMicroformat(t *testing.T) {
+	y := NewYoutube(false)
+	y.videoInfo = videoInfoFixture(`{"microformat":{"playerMicroformatRenderer":{
+		"availableCountries":["TW","US"],"isFamilySafe":true,"category":"Science & Technology"}}}`)
+	if err := y.parseVideoInfo(); err != nil {
+		t.Fatal(err)
+	}
+	m := y.Microformat
+	if m == nil || !m.IsFamilySafe || m.Category != "Science & Technology" {
+		t.Fatalf("Wrong microformat: %+v", m)
+	}
+	if !m.AvailableIn("tw") || m.AvailableIn("DE") {
+		t.Errorf("Wrong availability for %v", m.AvailableCountries)
+	}
+}
+
+func TestParseUpcomingPremiere(t *testing.T) {
+	v, _ := url.ParseQuery(videoInfoFixture(`{"videoDetails":{"isUpcoming":true},"playabilityStatus":{"status":"LIVE_STREAM_OFFLINE",
+		"liveStreamability":{"liveStreamabilityRenderer":{"offlineSlate":{"liveStreamOfflineSlateRenderer":{"scheduledStartTime":"1600000000"}}}},
+		"errorScreen":{"ypcTrailerRenderer":{"playerVars":"video_id=FHpvI8oGsuQ&autoplay=1"}}}}`))
+	v.Del("url_encoded_fmt_stream_map")
+	y := NewYoutube(false)
+	y.videoInfo = v.Encode()
+	err := y.parseVideoInfo()
+	if err == nil || !strings.Contains(err.Error(), "upcoming premiere") || !strings.Contains(err.Error(), "FHpvI8oGsuQ") {
+		t.Errorf("Wrong upcoming error: %v", err)
+	}
+	if !y.IsUpcoming || y.ScheduledStart.Unix() != 1600000000 || y.TrailerVideoID != "FHpvI8oGsuQ" {
+		t.Errorf("Wrong premiere info: %v %v %s", y.IsUpcoming, y.ScheduledStart, y.TrailerVideoID)
+	}
+}
+
+func TestVideoDetails(t *testing.T) {
+	y := NewYoutube(false)
+	y.parsePlayerResponse(`{"videoDetails":{"title":"Simplicity is Complicated","author":"dotconferences",
+		"channelId":"UCSRhwaM00ay0fasnsw6EXKA","lengthSeconds":"1382","keywords":["go"],"thumbnail":{"thumbnails":[
+		{"url":"https://i.ytimg.com/vi/rFejpH_tAHM/sddefault.jpg","width":640,"height":480},
+		{"url":"https://i.ytimg.com/vi/rFejpH_tAHM/default.jpg","width":120,"height":90}]}}}`)
+	d := y.Details
+	if d.Title != "Simplicity is Complicated" || d.Author != "dotconferences" || d.ChannelID != "UCSRhwaM00ay0fasnsw6EXKA" ||
+		d.Duration != 1382*time.Second || len(d.Keywords) != 1 || d.Thumbnail != "https://i.ytimg.com/vi/rFejpH_tAHM/sddefault.jpg" {
+		t.Errorf("Wrong details: %+v", d)
+	}
+}
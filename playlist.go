@@ -0,0 +1,227 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//PlaylistEntry : One video of a playlist.
+type PlaylistEntry struct {
+	ID    string
+	Title string
+	//Index is the 1-based position in the playlist, the track number of albums.
+	Index int
+	//MembersOnly is set for the videos reserved to the members of the channel.
+	MembersOnly bool
+}
+
+//Playlist : Playlist id, title and the videos it contains.
+type Playlist struct {
+	ID     string
+	Title  string
+	Videos []PlaylistEntry
+	//IsAlbum is set for youtube music albums, OLAK5uy_ playlists.
+	IsAlbum bool
+}
+
+var initialDataRe = regexp.MustCompile(`(?s)ytInitialData"?\]?\s*=\s*(\{.+?\});\s*(?:var |window|</script>)`)
+
+//PlaylistProgress : Progress of a playlist enumeration, reported after each page.
+type PlaylistProgress struct {
+	PlaylistID string
+	Pages      int
+	Videos     int
+}
+
+//GetPlaylist : Retrieval the videos of a playlist.
+func (y *Youtube) GetPlaylist(playlistID string) (*Playlist, error) {
+	return y.GetPlaylistContext(context.Background(), playlistID)
+}
+
+//GetPlaylistContext : Retrieval the videos of a playlist, following every page
+//until the end or the context is done, reporting each page to OnPlaylistProgress.
+//The Data API is asked first when APIKey is set, scraping is the fallback.
+func (y *Youtube) GetPlaylistContext(ctx context.Context, playlistID string) (p *Playlist, err error) {
+	if strings.HasPrefix(playlistID, albumBrowsePrefix) {
+		if playlistID, err = y.resolveAlbum(playlistID); err != nil {
+			return nil, err
+		}
+	}
+	span := y.startSpan("youtube.playlist", "playlist_id", playlistID)
+	defer func() { span.end(err) }()
+	if y.useDataAPI() {
+		if p, err = y.apiPlaylist(ctx, playlistID); err == nil {
+			return p, nil
+		}
+		y.dataAPIFallback(err)
+	}
+	return y.getPlaylist(ctx, playlistID)
+}
+
+func (y *Youtube) getPlaylist(ctx context.Context, playlistID string) (*Playlist, error) {
+	page, err := y.request(ctx, "GET", "https://www.youtube.com/playlist?list="+playlistID, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := extractInitialData(page)
+	if err != nil {
+		return nil, err
+	}
+	cfg := y.innertubeConfigOf(ctx, page)
+	p := &Playlist{ID: playlistID, IsAlbum: strings.HasPrefix(playlistID, albumPlaylistPrefix)}
+	seen := make(map[string]bool)
+	for pages := 1; ; pages++ {
+		before := len(p.Videos)
+		parsePlaylistPage(data, p)
+		if y.OnPlaylistProgress != nil {
+			y.OnPlaylistProgress(PlaylistProgress{PlaylistID: playlistID, Pages: pages, Videos: len(p.Videos)})
+		}
+		token := continuationToken(data)
+		if token == "" {
+			break
+		}
+		//a token seen before or an empty page would continue forever
+		if seen[token] || len(p.Videos) == before {
+			y.log(fmt.Sprintf("Playlist continuation stopped at page %d", pages))
+			break
+		}
+		seen[token] = true
+		if data, err = y.browseContinuation(ctx, cfg, token); err != nil {
+			return nil, fmt.Errorf("playlist page %d: %s", pages+1, err)
+		}
+	}
+	y.log(fmt.Sprintf("Playlist found: '%s' with %d videos", p.Title, len(p.Videos)))
+	if len(p.Videos) == 0 {
+		return nil, errors.New("no video found in the playlist")
+	}
+	return p, nil
+}
+
+//StartPlaylistDownload : Download every video of the decoded playlist into destDir,
+//named with the OutputTemplate.
+func (y *Youtube) StartPlaylistDownload(destDir string) error {
+	if y.Playlist == nil {
+		return errors.New("no playlist decoded")
+	}
+	tmpl := y.OutputTemplate
+	if tmpl == "" {
+		tmpl = DefaultOutputTemplate
+		if y.Playlist.IsAlbum {
+			tmpl = `{{printf "%02d" .Index}} - ` + tmpl
+		}
+	}
+	if _, err := OutputPath(destDir, tmpl, OutputFields{}); err != nil {
+		return err
+	}
+	used := map[string]bool{}
+	var failed int
+	var lastErr error
+	for _, v := range y.Playlist.Videos {
+		if y.Archive != nil && y.Archive.Contains(v.ID) {
+			y.log(fmt.Sprintf("Skip video %s, already in the archive", v.ID))
+			continue
+		}
+		if v.MembersOnly && len(y.Cookies) == 0 {
+			y.log(fmt.Sprintf("Skip video %s, members only and no Cookies", v.ID))
+			continue
+		}
+		y.VideoID, y.Details = v.ID, nil
+		err := y.getVideoInfo()
+		if err == nil {
+			err = y.parseVideoInfo()
+		}
+		var dest string
+		if err == nil {
+			dest, err = OutputPath(destDir, tmpl, y.outputFields(v))
+		}
+		if err == nil {
+			err = y.StartDownload(UniquePath(dest, v.ID, used))
+		}
+		if err != nil {
+			y.log(fmt.Sprintf("Download playlist video %s failed, err=%s", v.ID, err))
+			failed++
+			lastErr = err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d playlist videos failed, last err=%s", failed, len(y.Playlist.Videos), lastErr)
+	}
+	return nil
+}
+
+func extractInitialData(page []byte) (interface{}, error) {
+	subs := initialDataRe.FindSubmatch(page)
+	if subs == nil {
+		return nil, errors.New("no initial data found in the page")
+	}
+	var data interface{}
+	if err := json.Unmarshal(subs[1], &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+//parsePlaylistPage appends the videos of one page to the playlist.
+func parsePlaylistPage(data interface{}, p *Playlist) {
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		switch key {
+		case "playlistVideoRenderer":
+			id, _ := v["videoId"].(string)
+			if id != "" {
+				p.Videos = append(p.Videos, PlaylistEntry{ID: id, Title: jsonText(v["title"]), Index: len(p.Videos) + 1,
+					MembersOnly: membersOnlyBadge(v["badges"])})
+			}
+		case "playlistMetadataRenderer":
+			p.Title, _ = v["title"].(string)
+		}
+	})
+}
+
+//walkJSON calls fn for every object found under a key in the decoded json.
+func walkJSON(data interface{}, fn func(key string, v map[string]interface{})) {
+	switch d := data.(type) {
+	case map[string]interface{}:
+		//sorted keys keep the walk order stable
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := d[k]
+			if m, ok := child.(map[string]interface{}); ok {
+				fn(k, m)
+			}
+			walkJSON(child, fn)
+		}
+	case []interface{}:
+		for _, child := range d {
+			walkJSON(child, fn)
+		}
+	}
+}
+
+//jsonText reads youtube text objects, {"simpleText": ..} or {"runs": [{"text": ..}]}.
+func jsonText(v interface{}) string {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if t, ok := m["simpleText"].(string); ok {
+		return t
+	}
+	runs, _ := m["runs"].([]interface{})
+	var text string
+	for _, r := range runs {
+		if rm, ok := r.(map[string]interface{}); ok {
+			t, _ := rm["text"].(string)
+			text += t
+		}
+	}
+	return text
+}
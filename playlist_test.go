@@ -0,0 +1,114 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const playlistPage = `<script>var ytInitialData = {"metadata":{"playlistMetadataRenderer":{"title":"Go talks"}},
+"contents":[{"playlistVideoRenderer":{"videoId":"rFejpH_tAHM","title":{"runs":[{"text":"Simplicity"},{"text":" is Complicated"}]}}},
+{"playlistVideoRenderer":{"videoId":"FHpvI8oGsuQ","title":{"simpleText":"Second"}}}]};</script>`
+
+func TestFindVideoIDWithList(t *testing.T) {
+	y := NewYoutube(false)
+	if err := y.findVideoID("https://www.youtube.com/watch?list=PL59FEE129ADFF2B12&v=rFejpH_tAHM"); err != nil {
+		t.Fatal(err)
+	}
+	if y.VideoID != "rFejpH_tAHM" || y.PlaylistID != "PL59FEE129ADFF2B12" {
+		t.Errorf("Wrong ids, video=%s playlist=%s", y.VideoID, y.PlaylistID)
+	}
+
+	if err := y.findVideoID("https://www.youtube.com/playlist?list=PL59FEE129ADFF2B12"); err != nil {
+		t.Fatal(err)
+	}
+	if y.VideoID != "" || y.PlaylistID != "PL59FEE129ADFF2B12" {
+		t.Errorf("Wrong ids, video=%s playlist=%s", y.VideoID, y.PlaylistID)
+	}
+}
+
+func TestParsePlaylist(t *testing.T) {
+	data, err := extractInitialData([]byte(playlistPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Playlist{}
+	parsePlaylistPage(data, p)
+	if p.Title != "Go talks" || len(p.Videos) != 2 {
+		t.Fatalf("Wrong playlist parsed: %+v", p)
+	}
+	if p.Videos[0].ID != "rFejpH_tAHM" || p.Videos[0].Title != "Simplicity is Complicated" || p.Videos[1].Index != 2 {
+		t.Errorf("Wrong first video: %+v", p.Videos[0])
+	}
+}
+
+func TestPlaylistContinuation(t *testing.T) {
+	page := []byte(`<script>ytcfg.set({"INNERTUBE_API_KEY":"key123","INNERTUBE_CLIENT_VERSION":"2.20240101.00.00"});</script>` + playlistPage)
+	cfg := parseInnertubeConfig(page)
+	if cfg.APIKey != "key123" || cfg.ClientVersion != "2.20240101.00.00" {
+		t.Errorf("Wrong innertube config: %+v", cfg)
+	}
+
+	data, _ := extractInitialData(page)
+	p := &Playlist{}
+	parsePlaylistPage(data, p)
+	if token := continuationToken(data); token != "" {
+		t.Errorf("Last page should have no token: %s", token)
+	}
+
+	next := map[string]interface{}{}
+	json.Unmarshal([]byte(`{"onResponseReceivedActions":[{"appendContinuationItemsAction":{"continuationItems":[
+		{"playlistVideoRenderer":{"videoId":"XbNghLqsVwU","title":{"simpleText":"Third"}}},
+		{"continuationItemRenderer":{"continuationEndpoint":{"continuationCommand":{"token":"4qmFsgJh"}}}}]}}]}`), &next)
+	parsePlaylistPage(next, p)
+	if len(p.Videos) != 3 || p.Videos[2].ID != "XbNghLqsVwU" || p.Videos[2].Index != 3 {
+		t.Errorf("Wrong continued playlist: %+v", p.Videos)
+	}
+	if token := continuationToken(next); token != "4qmFsgJh" {
+		t.Errorf("Wrong continuation token: %s", token)
+	}
+}
+
+func TestPlaylistContinuationLoop(t *testing.T) {
+	tests := []struct {
+		name  string
+		page  func(n int) string
+		posts int
+	}{
+		{"repeated token", func(n int) string {
+			return `{"contents":[{"playlistVideoRenderer":{"videoId":"XbNghLqsVwU","title":{"simpleText":"Again"}}},
+				{"continuationItemRenderer":{"continuationEndpoint":{"continuationCommand":{"token":"tok1"}}}}]}`
+		}, 1},
+		{"empty page", func(n int) string {
+			return fmt.Sprintf(`{"contents":[{"continuationItemRenderer":{"continuationEndpoint":{"continuationCommand":{"token":"tok%d"}}}}]}`, n+1)
+		}, 1},
+	}
+	for _, tt := range tests {
+		var posts int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" {
+				posts++
+				fmt.Fprint(w, tt.page(posts))
+				return
+			}
+			fmt.Fprint(w, `<script>ytcfg.set({"INNERTUBE_API_KEY":"key123","INNERTUBE_CLIENT_VERSION":"2.20240101.00.00"});</script>`+
+				`<script>var ytInitialData = {"contents":[{"playlistVideoRenderer":{"videoId":"rFejpH_tAHM","title":{"simpleText":"First"}}},
+				{"continuationItemRenderer":{"continuationEndpoint":{"continuationCommand":{"token":"tok1"}}}}]};</script>`)
+		}))
+		u, _ := url.Parse(ts.URL)
+		y := NewYoutube(false)
+		y.client = &http.Client{Transport: hostRedirect{u}}
+		_, err := y.getPlaylist(context.Background(), "PL59FEE129ADFF2B12")
+		ts.Close()
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+		}
+		if posts != tt.posts {
+			t.Errorf("%s: %d continuation requests, want %d", tt.name, posts, tt.posts)
+		}
+	}
+}
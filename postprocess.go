@@ -0,0 +1,164 @@
+package youtube
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//EncodePreset : Re-encoding to h264 and aac for devices with limited playback support.
+type EncodePreset struct {
+	//Profile is the h264 profile, e.g. baseline, main or high.
+	Profile string
+	//MaxHeight scales down taller videos, zero keeps the resolution.
+	MaxHeight int
+	//CRF is the x264 constant rate factor, lower is better quality.
+	CRF int
+	//Speed is the x264 preset, e.g. veryfast or medium.
+	Speed        string
+	AudioBitrate string
+}
+
+//Encode presets
+var (
+	PresetBaseline720 = EncodePreset{Profile: "baseline", MaxHeight: 720, CRF: 23, Speed: "veryfast", AudioBitrate: "128k"}
+	PresetMain1080    = EncodePreset{Profile: "main", MaxHeight: 1080, CRF: 21, Speed: "medium", AudioBitrate: "160k"}
+)
+
+//postProcess runs the post-processing steps in order and returns the final file name.
+func (y *Youtube) postProcess(file string) (string, error) {
+	for _, step := range y.postProcessors() {
+		out, err := step.PostProcess(y, file)
+		if err != nil {
+			return "", err
+		}
+		y.log(fmt.Sprintf("Post-processed %T to file=%s", step, out))
+		file = out
+	}
+	return file, nil
+}
+
+//runFFmpeg runs ffmpeg, FFmpegPath or the one found in PATH.
+func (y *Youtube) runFFmpeg(args ...string) error {
+	_, err := y.ffmpegOutput("error", args...)
+	return err
+}
+
+//ffmpegOutput runs ffmpeg at the log level and returns its output, the
+//filters like silencedetect log their results at the info level.
+func (y *Youtube) ffmpegOutput(level string, args ...string) ([]byte, error) {
+	ffmpeg := y.FFmpegPath
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	args = append([]string{"-y", "-loglevel", level}, args...)
+	y.log(fmt.Sprintf("Run: %s %s", ffmpeg, strings.Join(args, " ")))
+	span := y.startSpan("youtube.ffmpeg", "args", strings.Join(args, " "))
+	out, err := exec.Command(ffmpeg, args...).CombinedOutput()
+	if err != nil {
+		err = fmt.Errorf("ffmpeg failed, err=%s, output=%s", err, out)
+	}
+	span.end(err)
+	return out, err
+}
+
+func (y *Youtube) mergeFiles(destFile string, parts []string) error {
+	var args []string
+	for _, p := range parts {
+		args = append(args, "-i", p)
+	}
+	args = append(args, "-c", "copy")
+	args = append(args, y.sphericalArgs()...)
+	return y.runFFmpeg(append(args, destFile)...)
+}
+
+//sphericalArgs keep the projection metadata of 360 videos, the mp4 muxer
+//only writes it at the unofficial strictness.
+func (y *Youtube) sphericalArgs() []string {
+	if y.Projection == "" {
+		return nil
+	}
+	return []string{"-strict", "unofficial"}
+}
+
+//remux copies the streams of the file into the container without re-encoding,
+//replacing the file extension, and returns the new file name.
+func (y *Youtube) remux(file, container string) (string, error) {
+	dest := strings.TrimSuffix(file, filepath.Ext(file)) + "." + container
+	out := dest
+	if out == file {
+		out = file + ".remux." + container
+	}
+	args := []string{"-i", file, "-map", "0", "-c", "copy"}
+	if container == "mp4" {
+		//opus in mp4 is still flagged experimental by older ffmpeg
+		args = append(args, "-strict", "experimental")
+	} else {
+		args = append(args, y.sphericalArgs()...)
+	}
+	if err := y.runFFmpeg(append(args, out)...); err != nil {
+		os.Remove(out)
+		return "", err
+	}
+	if out != dest {
+		return dest, os.Rename(out, dest)
+	}
+	return dest, os.Remove(file)
+}
+
+//encode re-encodes the file to an mp4 with the preset and returns the new file name.
+func (y *Youtube) encode(file string, p EncodePreset) (string, error) {
+	dest := strings.TrimSuffix(file, filepath.Ext(file)) + ".mp4"
+	out := dest + ".encode.mp4"
+	args := []string{"-i", file, "-c:v", "libx264", "-pix_fmt", "yuv420p"}
+	if p.Profile != "" {
+		args = append(args, "-profile:v", p.Profile)
+	}
+	if p.Speed != "" {
+		args = append(args, "-preset", p.Speed)
+	}
+	if p.CRF > 0 {
+		args = append(args, "-crf", strconv.Itoa(p.CRF))
+	}
+	if p.MaxHeight > 0 {
+		args = append(args, "-vf", fmt.Sprintf("scale=-2:'min(%d,ih)'", p.MaxHeight))
+	}
+	args = append(args, "-c:a", "aac")
+	if p.AudioBitrate != "" {
+		args = append(args, "-b:a", p.AudioBitrate)
+	}
+	args = append(args, y.sphericalArgs()...)
+	args = append(args, "-movflags", "+faststart", out)
+	if err := y.runFFmpeg(args...); err != nil {
+		os.Remove(out)
+		return "", err
+	}
+	if err := os.Rename(out, dest); err != nil {
+		return "", err
+	}
+	if dest != file {
+		os.Remove(file)
+	}
+	return dest, nil
+}
+
+//ExtractFrame : Save the video frame at the given time as an image, e.g. a jpg or png file.
+//ffmpeg seeks in the stream with range requests, so the video is not downloaded.
+func (y *Youtube) ExtractFrame(destFile string, at time.Duration) error {
+	formats, err := y.SelectFormats("best/bestvideo")
+	if err != nil {
+		return err
+	}
+	target, err := y.requestURL(formats[0])
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(destFile), 0755); err != nil {
+		return err
+	}
+	return y.runFFmpeg("-ss", formatSeconds(at), "-i", target, "-frames:v", "1", "-q:v", "2", destFile)
+}
@@ -0,0 +1,108 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+//fakeFFmpeg writes a script copying the first input to the output.
+func fakeFFmpeg(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	script := "#!/bin/sh\nfor a; do last=\"$a\"; done\ncp \"$5\" \"$last\"\n"
+	if err := ioutil.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRemux(t *testing.T) {
+	y := NewYoutube(false)
+	y.FFmpegPath = fakeFFmpeg(t)
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "dl.webm")
+	ioutil.WriteFile(src, []byte("video"), 0644)
+	dest, err := y.remux(src, "mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest != filepath.Join(dir, "dl.mp4") {
+		t.Errorf("Wrong remux file: %s", dest)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("Source file should be removed")
+	}
+
+	dest, err = y.remux(dest, "mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != "video" {
+		t.Errorf("Same name remux should replace the file, got %q", b)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	y := NewYoutube(false)
+	y.FFmpegPath = fakeFFmpeg(t)
+	y.Encode = &PresetBaseline720
+	src := filepath.Join(t.TempDir(), "dl.webm")
+	ioutil.WriteFile(src, []byte("video"), 0644)
+	dest, err := y.postProcess(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(dest) != "dl.mp4" {
+		t.Errorf("Wrong encoded file: %s", dest)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("Source file should be removed")
+	}
+}
+
+func TestExtractFrame(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "ffmpeg")
+	ioutil.WriteFile(script, []byte("#!/bin/sh\nfor a; do last=\"$a\"; done\necho \"$@\" > \"$last\"\n"), 0755)
+
+	y := NewYoutube(false)
+	y.FFmpegPath = script
+	y.Formats = testFormats
+	dest := filepath.Join(dir, "thumb.jpg")
+	if err := y.ExtractFrame(dest, 90*time.Second+500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(dest)
+	if want := "-ss 90.500 -i  -frames:v 1"; !strings.Contains(string(b), want) {
+		t.Errorf("Wrong ffmpeg arguments: %s", b)
+	}
+}
+
+func TestSphericalMerge(t *testing.T) {
+	dir := t.TempDir()
+	ffmpeg := filepath.Join(dir, "ffmpeg")
+	script := "#!/bin/sh\necho \"$@\" > " + filepath.Join(dir, "args") + "\n"
+	if err := ioutil.WriteFile(ffmpeg, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	formats := []Format{{Itag: 266}, {Itag: 140}}
+	applyStreamingInfo(formats, `{"streamingData":{"adaptiveFormats":[{"itag":266,"projectionType":"EQUIRECTANGULAR"},{"itag":140,"projectionType":"RECTANGULAR"}]}}`)
+	if formats[0].Projection != "EQUIRECTANGULAR" || formats[1].Projection != "" {
+		t.Errorf("Wrong projections: %+v", formats)
+	}
+
+	y := NewYoutube(false)
+	y.FFmpegPath = ffmpeg
+	y.Projection = formats[0].Projection
+	if err := y.mergeFiles(filepath.Join(dir, "dl.mp4"), []string{"v.mp4", "a.m4a"}); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(dir, "args")); !strings.Contains(string(b), "-c copy -strict unofficial") {
+		t.Errorf("Projection metadata not kept: %s", b)
+	}
+}
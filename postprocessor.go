@@ -0,0 +1,135 @@
+package youtube
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//PostProcessor : A step of the post-processing, it gets the downloaded file or
+//the output of the previous step and returns the name of its own output.
+type PostProcessor interface {
+	PostProcess(y *Youtube, file string) (string, error)
+}
+
+//PostProcessorFunc : Use a function as a PostProcessor.
+type PostProcessorFunc func(y *Youtube, file string) (string, error)
+
+//PostProcess : Call the function.
+func (f PostProcessorFunc) PostProcess(y *Youtube, file string) (string, error) {
+	return f(y, file)
+}
+
+//RemuxStep : Copy the streams to another container without re-encoding, e.g. mp4 or mkv.
+type RemuxStep struct {
+	Container string
+}
+
+//PostProcess : Remux the file, replacing its extension.
+func (s RemuxStep) PostProcess(y *Youtube, file string) (string, error) {
+	return y.remux(file, s.Container)
+}
+
+//EncodeStep : Re-encode to an mp4 with the preset.
+type EncodeStep struct {
+	Preset EncodePreset
+}
+
+//PostProcess : Encode the file.
+func (s EncodeStep) PostProcess(y *Youtube, file string) (string, error) {
+	return y.encode(file, s.Preset)
+}
+
+//NormalizeStep : Normalize the loudness of the audio.
+type NormalizeStep struct {
+	Loudness Loudness
+}
+
+//PostProcess : Normalize the file in place.
+func (s NormalizeStep) PostProcess(y *Youtube, file string) (string, error) {
+	return y.normalize(file, s.Loudness)
+}
+
+//EmbedSubtitlesStep : Embed the Subtitles in an mkv file.
+type EmbedSubtitlesStep struct{}
+
+//PostProcess : Download the subtitles and embed them.
+func (EmbedSubtitlesStep) PostProcess(y *Youtube, file string) (string, error) {
+	return y.embedSubtitles(file)
+}
+
+//TagStep : Write metadata tags in the file, e.g. title, artist or comment.
+type TagStep struct {
+	Metadata map[string]string
+}
+
+//PostProcess : Copy the file with the tags.
+func (s TagStep) PostProcess(y *Youtube, file string) (string, error) {
+	keys := make([]string, 0, len(s.Metadata))
+	for k := range s.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	args := []string{"-i", file, "-map", "0", "-c", "copy"}
+	for _, k := range keys {
+		args = append(args, "-metadata", k+"="+s.Metadata[k])
+	}
+	ext := filepath.Ext(file)
+	tmp := strings.TrimSuffix(file, ext) + ".tag" + ext
+	if err := y.runFFmpeg(append(args, tmp)...); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return file, os.Rename(tmp, file)
+}
+
+//SplitChaptersStep : Copy each chapter to its own file and keep the file, the
+//chapters and their files are added to the DownloadResult. The chapter files
+//are not copied to the Storage.
+type SplitChaptersStep struct{}
+
+//PostProcess : Split the file at the chapters.
+func (SplitChaptersStep) PostProcess(y *Youtube, file string) (string, error) {
+	chapters, files, err := y.splitChapters(file)
+	if err != nil {
+		return "", err
+	}
+	if y.result != nil {
+		y.result.Chapters, y.result.ChapterFiles = chapters, files
+	}
+	return file, nil
+}
+
+//ExecStep : Run a command on the file, "{file}" in Args is replaced by its name.
+type ExecStep struct {
+	Command string
+	Args    []string
+}
+
+//PostProcess : Run the command, the file is kept.
+func (s ExecStep) PostProcess(y *Youtube, file string) (string, error) {
+	args := make([]string, len(s.Args))
+	for i, a := range s.Args {
+		args[i] = strings.Replace(a, "{file}", file, -1)
+	}
+	return file, y.runCommand(s.Command, args...)
+}
+
+//postProcessors is the chain of the post-processing options followed by the PostProcessors.
+func (y *Youtube) postProcessors() []PostProcessor {
+	var steps []PostProcessor
+	if y.RemuxTo != "" {
+		steps = append(steps, RemuxStep{Container: y.RemuxTo})
+	}
+	if y.Encode != nil {
+		steps = append(steps, EncodeStep{Preset: *y.Encode})
+	}
+	if y.Normalize != nil {
+		steps = append(steps, NormalizeStep{Loudness: *y.Normalize})
+	}
+	if y.EmbedSubtitles && len(y.Subtitles) > 0 {
+		steps = append(steps, EmbedSubtitlesStep{})
+	}
+	return append(steps, y.PostProcessors...)
+}
@@ -0,0 +1,43 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestPostProcessors(t *testing.T) {
+	y := NewYoutube(false)
+	y.FFmpegPath = fakeFFmpeg(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "dl.webm")
+	ioutil.WriteFile(src, []byte("video"), 0644)
+	marker := filepath.Join(dir, "scanned")
+
+	var got []string
+	y.RemuxTo = "mkv"
+	y.PostProcessors = []PostProcessor{
+		TagStep{Metadata: map[string]string{"title": "Simplicity"}},
+		PostProcessorFunc(func(y *Youtube, file string) (string, error) {
+			got = append(got, file)
+			return file, nil
+		}),
+		ExecStep{Command: "cp", Args: []string{"{file}", marker}},
+	}
+	dest, err := y.postProcess(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the options run before the PostProcessors
+	if want := filepath.Join(dir, "dl.mkv"); dest != want || len(got) != 1 || got[0] != want {
+		t.Errorf("Wrong chain output=%s, custom step got %v", dest, got)
+	}
+	if b, _ := ioutil.ReadFile(marker); string(b) != "video" {
+		t.Errorf("Exec step not run on the file, got %q", b)
+	}
+
+	y.PostProcessors = []PostProcessor{ExecStep{Command: "false"}}
+	if _, err := y.postProcess(dest); err == nil {
+		t.Error("A failing step should stop the chain")
+	}
+}
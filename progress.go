@@ -0,0 +1,80 @@
+package youtube
+
+//Download phases
+const (
+	PhaseDownload      = "download"
+	PhaseDownloadVideo = "download video"
+	PhaseDownloadAudio = "download audio"
+	PhaseMerge         = "merge"
+)
+
+//ProgressEvent : Download progress reported to OnProgress.
+//DownloadID tags the events and log lines of concurrent downloads.
+//Percent covers the whole download while PhasePercent covers the current
+//phase, e.g. the audio part of a merged download.
+//When the server doesn't tell the size, SizeUnknown is set, Total is -1 and the
+//percents stay at the start of the phase while Bytes grows, every megabyte.
+type ProgressEvent struct {
+	DownloadID   string
+	VideoID      string
+	Percent      int64
+	Phase        string
+	PhasePercent int64
+	Bytes        int64
+	Total        int64
+	SizeUnknown  bool
+}
+
+//unknownSizeStep is the number of bytes between the events of a download of unknown size.
+const unknownSizeStep = 1 << 20
+
+//beginPhase starts the phase index of count, each phase weights the same.
+func (y *Youtube) beginPhase(index, count int, phase string) {
+	if index == 0 {
+		y.lastPercent = 0
+	}
+	y.phase, y.phaseIndex, y.phaseCount = phase, index, count
+	y.downloadLevel = 0
+}
+
+//endPhases resets to a single download phase.
+func (y *Youtube) endPhases() {
+	y.phase, y.phaseIndex, y.phaseCount = "", 0, 0
+}
+
+//reportProgress sends the progress of the current phase to DownloadPercent and OnProgress.
+func (y *Youtube) reportProgress() {
+	count := int64(y.phaseCount)
+	if count < 1 {
+		count = 1
+	}
+	percent := (int64(y.phaseIndex)*100 + int64(y.downloadLevel)) / count
+	if percent > y.lastPercent {
+		y.lastPercent = percent
+		//never stall the download when nobody reads the channel
+		select {
+		case y.DownloadPercent <- percent:
+		default:
+		}
+	}
+	if y.OnProgress != nil {
+		total := int64(y.contentLength)
+		if total <= 0 {
+			total = -1
+		}
+		phase := y.phase
+		if phase == "" {
+			phase = PhaseDownload
+		}
+		y.OnProgress(ProgressEvent{
+			DownloadID:   y.DownloadID,
+			VideoID:      y.VideoID,
+			Percent:      percent,
+			Phase:        phase,
+			PhasePercent: int64(y.downloadLevel),
+			Bytes:        int64(y.totalWrittenBytes),
+			Total:        total,
+			SizeUnknown:  total < 0,
+		})
+	}
+}
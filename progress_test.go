@@ -0,0 +1,95 @@
+package youtube
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMergedProgress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 500))
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.FFmpegPath = fakeFFmpeg(t)
+	y.Formats = []Format{
+		{Itag: 137, MimeType: "video/mp4", Height: 1080, HasVideo: true, URL: ts.URL + "/video"},
+		{Itag: 140, MimeType: "audio/mp4", Bitrate: 128000, HasAudio: true, URL: ts.URL + "/audio"},
+	}
+	y.FormatSelector = "bestvideo+bestaudio"
+	var phases []string
+	var last int64
+	y.OnProgress = func(ev ProgressEvent) {
+		if ev.Percent < last {
+			t.Errorf("Progress went back from %d to %d", last, ev.Percent)
+		}
+		last = ev.Percent
+		if len(phases) == 0 || phases[len(phases)-1] != ev.Phase {
+			phases = append(phases, ev.Phase)
+		}
+	}
+	if err := y.StartDownload(filepath.Join(t.TempDir(), "dl.mp4")); err != nil {
+		t.Fatal(err)
+	}
+	if len(phases) != 3 || phases[0] != PhaseDownloadVideo || phases[1] != PhaseDownloadAudio || phases[2] != PhaseMerge {
+		t.Errorf("Wrong phases: %v", phases)
+	}
+	if last != 100 {
+		t.Errorf("Progress should end at 100, got %d", last)
+	}
+}
+
+func TestEstimatedProgress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//flushing before the end makes a chunked answer, without Content-Length
+		w.Write(make([]byte, 500))
+		w.(http.Flusher).Flush()
+		w.Write(make([]byte, 500))
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.Formats = []Format{{Itag: 251, MimeType: "audio/webm", HasAudio: true, AverageBitrate: 8000, Duration: time.Second, URL: ts.URL}}
+	y.FormatSelector = "bestaudio"
+	var last ProgressEvent
+	y.OnProgress = func(ev ProgressEvent) { last = ev }
+	if err := y.StartDownload(filepath.Join(t.TempDir(), "dl.webm")); err != nil {
+		t.Fatal(err)
+	}
+	if last.Percent != 100 || last.Total != 1000 {
+		t.Errorf("Wrong last progress %+v", last)
+	}
+}
+
+func TestUnknownSizeProgress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 3; i++ {
+			w.Write(make([]byte, unknownSizeStep))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	var events []ProgressEvent
+	y.OnProgress = func(ev ProgressEvent) { events = append(events, ev) }
+	if err := y.StartDownload(filepath.Join(t.TempDir(), "dl.mp4")); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want 4: %+v", len(events), events)
+	}
+	for _, ev := range events[:3] {
+		if !ev.SizeUnknown || ev.Total != -1 || ev.Percent != 0 {
+			t.Errorf("Wrong unknown size progress %+v", ev)
+		}
+	}
+	if ev := events[3]; ev.SizeUnknown || ev.Percent != 100 || ev.Total != 3*unknownSizeStep {
+		t.Errorf("Wrong last progress %+v", ev)
+	}
+}
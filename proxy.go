@@ -0,0 +1,61 @@
+package youtube
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+//proxiedHeaders are copied from the stream answer to the client.
+var proxiedHeaders = []string{"Content-Length", "Content-Range", "Accept-Ranges", "Last-Modified", "Etag"}
+
+//StreamHandler : Http handler proxying the format to the client, seekable with Range requests.
+func (y *Youtube) StreamHandler(f Format) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" && r.Method != "HEAD" {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		target, err := y.requestURL(f)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		req, err := http.NewRequest(r.Method, target, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, h := range []string{"Range", "If-Range"} {
+			if v := r.Header.Get(h); v != "" {
+				req.Header.Set(h, v)
+			}
+		}
+		resp, err := y.do(req.WithContext(r.Context()))
+		if err != nil {
+			y.log(fmt.Sprintf("Proxy stream itag=%d error: %s", f.Itag, err))
+			http.Error(w, "stream unavailable", http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		for _, h := range proxiedHeaders {
+			if v := resp.Header.Get(h); v != "" {
+				w.Header().Set(h, v)
+			}
+		}
+		contentType := resp.Header.Get("Content-Type")
+		if f.MimeType != "" {
+			contentType = strings.TrimSpace(strings.SplitN(f.MimeType, ";", 2)[0])
+		}
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(resp.StatusCode)
+		if r.Method == "GET" {
+			io.Copy(w, resp.Body)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package youtube
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreamHandler(t *testing.T) {
+	content := []byte("0123456789")
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		http.ServeContent(w, r, "video", time.Time{}, bytes.NewReader(content))
+	}))
+	defer upstream.Close()
+
+	y := NewYoutube(false)
+	y.Bandwidth = NewBandwidth(0)
+	f := Format{Itag: 18, MimeType: `video/mp4; codecs="avc1.42001E, mp4a.40.2"`, URL: upstream.URL}
+	ts := httptest.NewServer(y.StreamHandler(f))
+	defer ts.Close()
+
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	req.Header.Set("Range", "bytes=2-5")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusPartialContent || string(body) != "2345" {
+		t.Errorf("Wrong partial answer: %d %q", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Content-Type") != "video/mp4" || !strings.HasPrefix(resp.Header.Get("Content-Range"), "bytes 2-5/10") {
+		t.Errorf("Wrong headers: %v", resp.Header)
+	}
+	if y.BytesReceived() != 4 {
+		t.Errorf("The proxied bytes are not metered: %d", y.BytesReceived())
+	}
+}
+
+func TestStreamHandlerConcurrent(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/iframe_api" {
+			w.Write([]byte(`player\/abcdef12\/`))
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/s/player/") {
+			w.Write([]byte(playerJS))
+			return
+		}
+		w.Write([]byte("video"))
+	}))
+	defer upstream.Close()
+	u, _ := url.Parse(upstream.URL)
+
+	get := func(h http.Handler) {
+		ts := httptest.NewServer(h)
+		defer ts.Close()
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := http.Get(ts.URL)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+				if body, _ := ioutil.ReadAll(resp.Body); string(body) != "video" {
+					t.Errorf("Wrong body %q", body)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	//the client is built by the first requests
+	y := NewYoutube(false)
+	get(y.StreamHandler(Format{Itag: 18, URL: upstream.URL + "/videoplayback"}))
+
+	//the player is deciphered by the first requests
+	y = NewYoutube(false)
+	y.CacheDir = t.TempDir()
+	y.client = &http.Client{Transport: hostRedirect{u}}
+	get(y.StreamHandler(Format{Itag: 18, signatureCipher: "s=abcdefg&url=" + url.QueryEscape("https://r1.googlevideo.com/videoplayback")}))
+}
@@ -0,0 +1,79 @@
+package youtube
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+//RateLimiter : Token bucket capping the download bandwidth, in bytes per second.
+//Share one between Youtube objects, e.g. with Batch, to cap their total.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+//NewRateLimiter : Initialize a limiter allowing bytesPerSecond, with one second of burst.
+//A rate of 0 or less returns nil, which doesn't limit.
+func NewRateLimiter(bytesPerSecond int64) *RateLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &RateLimiter{rate: float64(bytesPerSecond), tokens: float64(bytesPerSecond), last: time.Now()}
+}
+
+//WaitN : Take n bytes from the bucket, waiting until they are available.
+//A nil limiter or one without a rate returns at once.
+func (l *RateLimiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	//reserving ahead keeps the waiting readers in order
+	l.tokens -= float64(n)
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//maxLimitedRead keeps the reads small, so the bandwidth is shared smoothly.
+const maxLimitedRead = 32 * 1024
+
+//limitedReader waits for the limiter after each read.
+type limitedReader struct {
+	r       io.Reader
+	limiter *RateLimiter
+	ctx     context.Context
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > maxLimitedRead {
+		p = p[:maxLimitedRead]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.limiter.WaitN(r.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,49 @@
+package youtube
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterShared(t *testing.T) {
+	l := NewRateLimiter(100000)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			//the burst covers the first 100000 bytes, the next 50000 take 0.5s
+			for j := 0; j < 15; j++ {
+				l.WaitN(context.Background(), 5000)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("Shared limiter should take about 0.5s, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.WaitN(ctx, 1000000); err != context.Canceled {
+		t.Errorf("Canceled wait should fail, err=%v", err)
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	for _, rate := range []int64{0, -1} {
+		if l := NewRateLimiter(rate); l != nil {
+			t.Errorf("Rate %d should not limit: %+v", rate, l)
+		}
+	}
+	var l *RateLimiter
+	if err := l.WaitN(context.Background(), 1000000); err != nil {
+		t.Error(err)
+	}
+	if err := (&RateLimiter{}).WaitN(context.Background(), 1000000); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,96 @@
+package youtube
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+//restrictedModeFlag is the bit of the f2 flags of the PREF cookie that turns on
+//the Restricted Mode.
+const restrictedModeFlag = 0x8000000
+
+//isYoutubeHost tells if the requests to the host get the youtube preferences.
+func isYoutubeHost(host string) bool {
+	return host == "youtube.com" || strings.HasSuffix(host, ".youtube.com")
+}
+
+//regionURL adds the Region to the query of the youtube pages.
+func (y *Youtube) regionURL(target string) string {
+	if y.Region == "" {
+		return target
+	}
+	if u, err := url.Parse(target); err != nil || !isYoutubeHost(u.Hostname()) {
+		return target
+	}
+	return setQueryParam(target, "gl", y.Region)
+}
+
+//innertubeContext is the context of the innertube requests, with the
+//Language, the Region and the Restricted Mode.
+func (y *Youtube) innertubeContext(cfg innertubeConfig) map[string]interface{} {
+	client := map[string]string{"clientName": "WEB", "clientVersion": cfg.ClientVersion}
+	if y.Language != "" {
+		client["hl"] = y.Language
+	}
+	if y.Region != "" {
+		client["gl"] = y.Region
+	}
+	return map[string]interface{}{
+		"client": client,
+		"user":   map[string]bool{"enableSafetyMode": y.RestrictedMode},
+	}
+}
+
+//setPreferences rewrites the PREF cookie of the youtube requests with the Region
+//and the Restricted Mode, which overrides the ones of the imported account
+//cookies, e.g. a browser with the Restricted Mode on.
+func (y *Youtube) setPreferences(req *http.Request) {
+	if !isYoutubeHost(req.URL.Hostname()) {
+		return
+	}
+	cookies := req.Cookies()
+	pref := -1
+	for i, c := range cookies {
+		if c.Name == "PREF" {
+			pref = i
+		}
+	}
+	if pref < 0 && y.Region == "" && !y.RestrictedMode {
+		return
+	}
+	var values url.Values
+	if pref >= 0 {
+		values, _ = url.ParseQuery(cookies[pref].Value)
+	}
+	if values == nil {
+		values = url.Values{}
+	}
+	flags, _ := strconv.ParseInt(values.Get("f2"), 16, 64)
+	if y.RestrictedMode {
+		flags |= restrictedModeFlag
+	} else {
+		flags &^= restrictedModeFlag
+	}
+	if flags != 0 {
+		values.Set("f2", strconv.FormatInt(flags, 16))
+	} else {
+		values.Del("f2")
+	}
+	if y.Region != "" {
+		values.Set("gl", y.Region)
+	}
+	if y.Language != "" {
+		values.Set("hl", y.Language)
+	}
+	if pref < 0 {
+		cookies = append(cookies, &http.Cookie{Name: "PREF"})
+		pref = len(cookies) - 1
+	}
+	cookies[pref].Value = values.Encode()
+	req.Header.Del("Cookie")
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+}
@@ -0,0 +1,40 @@
+package youtube
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSetPreferences(t *testing.T) {
+	y := NewYoutube(false)
+	y.Cookies = []*http.Cookie{{Name: "SID", Value: "a"}, {Name: "PREF", Value: "f2=8000400&f6=40000000"}}
+	y.Region = "FR"
+	req, _ := http.NewRequest("GET", "https://www.youtube.com/watch?v=rFejpH_tAHM", nil)
+	y.setCookies(req)
+	y.setPreferences(req)
+	if got := req.Header.Get("Cookie"); got != "SID=a; PREF=f2=400&f6=40000000&gl=FR" {
+		t.Errorf("got cookies %q", got)
+	}
+
+	y.Cookies, y.Region, y.RestrictedMode = nil, "", true
+	req, _ = http.NewRequest("GET", "https://www.youtube.com/watch?v=rFejpH_tAHM", nil)
+	y.setPreferences(req)
+	if got := req.Header.Get("Cookie"); got != "PREF=f2=8000000" {
+		t.Errorf("got cookies %q", got)
+	}
+}
+
+func TestRegionURL(t *testing.T) {
+	y := NewYoutube(false)
+	y.Region = "JP"
+	if got := y.regionURL("https://www.youtube.com/playlist?list=PL59FEE129ADFF2B12"); got != "https://www.youtube.com/playlist?gl=JP&list=PL59FEE129ADFF2B12" {
+		t.Errorf("got %s", got)
+	}
+	if got := y.regionURL("https://www.googleapis.com/youtube/v3/videos"); got != "https://www.googleapis.com/youtube/v3/videos" {
+		t.Errorf("got %s", got)
+	}
+	ctx := y.innertubeContext(innertubeConfig{ClientVersion: "2.20240101"})
+	if ctx["client"].(map[string]string)["gl"] != "JP" || ctx["user"].(map[string]bool)["enableSafetyMode"] {
+		t.Errorf("Wrong context %v", ctx)
+	}
+}
@@ -0,0 +1,68 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+)
+
+//VideoSummary : A video listed in a feed, like the related videos or the trending list.
+type VideoSummary struct {
+	ID     string
+	Title  string
+	Author string
+	//MembersOnly is set for the videos reserved to the members of the channel.
+	MembersOnly bool
+}
+
+//GetRelatedVideos : Retrieval the videos recommended on the watch page of the decoded video.
+func (y *Youtube) GetRelatedVideos() ([]VideoSummary, error) {
+	if y.VideoID == "" {
+		return nil, errors.New("no video decoded")
+	}
+	data, err := y.fetchInitialData("https://www.youtube.com/watch?v=" + y.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	related := parseVideoSummaries(data, "compactVideoRenderer", y.VideoID)
+	y.log(fmt.Sprintf("Found %d related videos", len(related)))
+	return related, nil
+}
+
+func (y *Youtube) fetchInitialData(url string) (interface{}, error) {
+	body, err := y.fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	return extractInitialData(body)
+}
+
+//parseVideoSummaries collects the videos of the renderer type, without
+//duplicates nor the excluded ids.
+func parseVideoSummaries(data interface{}, renderer string, exclude ...string) []VideoSummary {
+	var videos []VideoSummary
+	seen := make(map[string]bool)
+	for _, id := range exclude {
+		seen[id] = true
+	}
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		if key != renderer {
+			return
+		}
+		id, _ := v["videoId"].(string)
+		if id == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		author := jsonText(v["longBylineText"])
+		if author == "" {
+			author = jsonText(v["ownerText"])
+		}
+		videos = append(videos, VideoSummary{
+			ID:          id,
+			Title:       jsonText(v["title"]),
+			Author:      author,
+			MembersOnly: membersOnlyBadge(v["badges"]),
+		})
+	})
+	return videos
+}
@@ -0,0 +1,22 @@
+package youtube
+
+import "testing"
+
+const watchPage = `<script>var ytInitialData = {"contents":{"twoColumnWatchNextResults":{"secondaryResults":{"secondaryResults":{"results":[
+{"compactVideoRenderer":{"videoId":"FHpvI8oGsuQ","title":{"simpleText":"Concurrency is not Parallelism"},"longBylineText":{"runs":[{"text":"Heroku"}]}}},
+{"compactVideoRenderer":{"videoId":"rFejpH_tAHM","title":{"simpleText":"Same video"}}},
+{"compactVideoRenderer":{"videoId":"FHpvI8oGsuQ","title":{"simpleText":"Duplicate"}}}]}}}}};</script>`
+
+func TestParseRelatedVideos(t *testing.T) {
+	data, err := extractInitialData([]byte(watchPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	related := parseVideoSummaries(data, "compactVideoRenderer", "rFejpH_tAHM")
+	if len(related) != 1 {
+		t.Fatalf("Wrong related videos: %+v", related)
+	}
+	if r := related[0]; r.ID != "FHpvI8oGsuQ" || r.Title != "Concurrency is not Parallelism" || r.Author != "Heroku" {
+		t.Errorf("Wrong related video: %+v", r)
+	}
+}
@@ -0,0 +1,139 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+//Resolver : Resolves the host names of the connections, *net.Resolver implements it.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+//DoHResolver : Resolver using the json api of a DNS-over-HTTPS server.
+type DoHResolver struct {
+	//Endpoint is the server url, e.g. https://cloudflare-dns.com/dns-query.
+	Endpoint string
+	Client   *http.Client
+}
+
+//NewDoHResolver : Initialize a DNS-over-HTTPS resolver for the server url.
+func NewDoHResolver(endpoint string) *DoHResolver {
+	return &DoHResolver{Endpoint: endpoint, Client: http.DefaultClient}
+}
+
+type dohAnswer struct {
+	Status int `json:"Status"`
+	Answer []struct {
+		Type int    `json:"type"`
+		Data string `json:"data"`
+	} `json:"Answer"`
+}
+
+//LookupIPAddr : Query the A and AAAA records of the host.
+func (r *DoHResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var addrs []net.IPAddr
+	var lastErr error
+	for _, qtype := range []int{1, 28} {
+		wg.Add(1)
+		go func(qtype int) {
+			defer wg.Done()
+			ips, err := r.query(ctx, host, qtype)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = err
+				return
+			}
+			addrs = append(addrs, ips...)
+		}(qtype)
+	}
+	wg.Wait()
+	if len(addrs) == 0 {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no address found for %s", host)
+		}
+		return nil, lastErr
+	}
+	return addrs, nil
+}
+
+func (r *DoHResolver) query(ctx context.Context, host string, qtype int) ([]net.IPAddr, error) {
+	params := url.Values{"name": {host}, "type": {strconv.Itoa(qtype)}}
+	req, err := http.NewRequest("GET", r.Endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/dns-json")
+	resp, err := r.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("DoH server answered status code %d", resp.StatusCode)
+	}
+	var answer dohAnswer
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return nil, err
+	}
+	if answer.Status != 0 {
+		return nil, fmt.Errorf("DoH lookup of %s failed, rcode=%d", host, answer.Status)
+	}
+	var addrs []net.IPAddr
+	for _, a := range answer.Answer {
+		if a.Type != qtype {
+			continue
+		}
+		if ip := net.ParseIP(a.Data); ip != nil {
+			addrs = append(addrs, net.IPAddr{IP: ip})
+		}
+	}
+	return addrs, nil
+}
+
+//dialResolved resolves the host with the Resolver option and dials its
+//addresses in turn, matching the network family.
+func (y *Youtube) dialResolved(ctx context.Context, d *net.Dialer, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		return d.DialContext(ctx, network, addr)
+	}
+	addrs, err := y.Resolver.LookupIPAddr(ctx, host)
+	if trace := httptrace.ContextClientTrace(ctx); trace != nil {
+		if trace.DNSStart != nil {
+			trace.DNSStart(httptrace.DNSStartInfo{Host: host})
+		}
+		if trace.DNSDone != nil {
+			trace.DNSDone(httptrace.DNSDoneInfo{Addrs: addrs, Err: err})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	lastErr := errors.New("no address of " + host + " matches " + network)
+	for _, a := range addrs {
+		if (network == "tcp4" && a.IP.To4() == nil) || (network == "tcp6" && a.IP.To4() != nil) {
+			continue
+		}
+		conn, err := d.DialContext(ctx, network, net.JoinHostPort(a.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,44 @@
+package youtube
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDoHResolver(t *testing.T) {
+	doh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/dns-json" || r.URL.Query().Get("name") != "video.test" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("type") == "1" {
+			w.Write([]byte(`{"Status":0,"Answer":[{"type":5,"data":"cname.test."},{"type":1,"data":"127.0.0.1"}]}`))
+			return
+		}
+		w.Write([]byte(`{"Status":0}`))
+	}))
+	defer doh.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	y := NewYoutube(false)
+	y.Resolver = NewDoHResolver(doh.URL)
+	body, err := y.fetch("http://video.test:" + port + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "video.test:"+port {
+		t.Errorf("Wrong host: %s", body)
+	}
+
+	y = NewYoutube(false)
+	y.Resolver = NewDoHResolver(doh.URL)
+	if _, err := y.fetch("http://unknown.test:" + port + "/"); err == nil {
+		t.Error("Unresolved host should fail")
+	}
+}
@@ -0,0 +1,42 @@
+package youtube
+
+import "os"
+
+//DownloadResult : What Download actually wrote.
+type DownloadResult struct {
+	//Itags of the downloaded streams, the video then the audio when merged.
+	Itags []int
+	//URLs the streams were downloaded from, after redirects.
+	URLs []string
+	//Path of the final file, after numbering, remuxing or encoding.
+	//It is the name in the Storage when one is set.
+	Path string
+	//Size of the final file in bytes.
+	Size int64
+	//SHA256 of the final file in hex, when Checksum is set.
+	SHA256 string
+	//Chapters the file was split at, when SplitChapters is set, they are
+	//detected in the silences when the video has none and SilenceSplit is set.
+	Chapters []Chapter
+	//ChapterFiles are the files of the chapters, when SplitChapters is set.
+	ChapterFiles []string
+	//Skipped is true when OnExists kept an existing file.
+	Skipped bool
+}
+
+//recordStream adds the stream that has just been downloaded to the result.
+func (y *Youtube) recordStream(itag int) {
+	if y.result == nil {
+		return
+	}
+	y.result.Itags = append(y.result.Itags, itag)
+	y.result.URLs = append(y.result.URLs, y.FinalURL)
+}
+
+func fileSize(name string) int64 {
+	info, err := os.Stat(name)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,43 @@
+package youtube
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDownloadResult(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("video data"))
+	}))
+	defer ts.Close()
+
+	dest := filepath.Join(t.TempDir(), "dl.mp4")
+	y := NewYoutube(false)
+	y.StreamList = []stream{
+		{"itag": "22", "url": ts.URL + "/broken"},
+		{"itag": "18", "url": ts.URL + "/ok"},
+	}
+	result, err := y.Download(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &DownloadResult{Itags: []int{18}, URLs: []string{ts.URL + "/ok"}, Path: dest, Size: 10}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("got %+v, want %+v", result, want)
+	}
+
+	y.OnExists = ExistsSkip
+	if result, err = y.Download(dest); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Skipped || result.Size != 10 || result.Itags != nil {
+		t.Errorf("Wrong skipped result %+v", result)
+	}
+}
@@ -0,0 +1,72 @@
+package youtube
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//Defaults of the rate limit options set by NewYoutube.
+const (
+	DefaultRateLimitRetries = 3
+	DefaultRateLimitBackoff = 2 * time.Second
+	//maxRetryAfter caps the wait asked by a Retry-After header.
+	maxRetryAfter = 10 * time.Minute
+)
+
+//do sends the request with the consent cookies, retrying on 429 too many requests after the
+//Retry-After delay or a jittered exponential backoff.
+func (y *Youtube) do(req *http.Request) (*http.Response, error) {
+	y.setCookies(req)
+	y.setPreferences(req)
+	y.setAuthorization(req)
+	setConsentCookies(req)
+	for attempt := 0; ; attempt++ {
+		if err := y.checkBudget(); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		resp, err := y.getClient().Do(req)
+		y.logResponse(req, start, resp, err)
+		y.meterResponse(resp)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= y.RateLimitRetries {
+			return resp, err
+		}
+		wait := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		resp.Body.Close()
+		if wait == 0 {
+			backoff := y.RateLimitBackoff << uint(attempt)
+			wait = backoff/2 + time.Duration(rand.Int63n(int64(backoff)+1))
+		}
+		if wait > maxRetryAfter {
+			wait = maxRetryAfter
+		}
+		y.log(fmt.Sprintf("429 too many requests from %s, retry in %s", req.URL.Host, wait))
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+//retryAfter reads the delay of a Retry-After header, in seconds or as a date.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
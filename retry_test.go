@@ -0,0 +1,47 @@
+package youtube
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d := retryAfter("120", now); d != 2*time.Minute {
+		t.Errorf("Wrong seconds delay: %s", d)
+	}
+	if d := retryAfter("Wed, 01 Jan 2020 00:00:30 GMT", now); d != 30*time.Second {
+		t.Errorf("Wrong date delay: %s", d)
+	}
+	if d := retryAfter("soon", now); d != 0 {
+		t.Errorf("Invalid header should not delay: %s", d)
+	}
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.RateLimitBackoff = time.Millisecond
+	body, err := y.fetch(ts.URL)
+	if err != nil || string(body) != "ok" || calls != 3 {
+		t.Errorf("Should succeed after 2 retries: %q, calls=%d, err=%v", body, calls, err)
+	}
+
+	calls = 0
+	y.RateLimitRetries = 1
+	if _, err := y.fetch(ts.URL); err == nil || calls != 2 {
+		t.Errorf("Should give up after 1 retry, calls=%d, err=%v", calls, err)
+	}
+}
@@ -0,0 +1,54 @@
+package youtube
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+//MirrorHost : RewriteURL function fetching the streams through an Invidious or
+//Piped instance, e.g. MirrorHost("https://invidious.example.com"). The instance
+//proxies /videoplayback and gets the original host in the host parameter.
+func MirrorHost(base string) func(string) (string, error) {
+	return func(target string) (string, error) {
+		mirror, err := url.Parse(base)
+		if err != nil {
+			return "", fmt.Errorf("invalid mirror '%s': %s", base, err)
+		}
+		u, err := url.Parse(target)
+		if err != nil {
+			return "", err
+		}
+		q := u.Query()
+		q.Set("host", u.Host)
+		u.RawQuery = q.Encode()
+		u.Scheme = mirror.Scheme
+		u.Host = mirror.Host
+		u.Path = strings.TrimSuffix(mirror.Path, "/") + u.Path
+		return u.String(), nil
+	}
+}
+
+//rewriteURL applies RewriteURL to a stream url.
+func (y *Youtube) rewriteURL(target string) (string, error) {
+	if y.RewriteURL == nil {
+		return target, nil
+	}
+	rewritten, err := y.RewriteURL(target)
+	if err != nil {
+		return "", fmt.Errorf("rewrite url error=%s", err)
+	}
+	if rewritten != target {
+		y.log(fmt.Sprintf("Stream url rewritten to=%s", rewritten))
+	}
+	return rewritten, nil
+}
+
+//requestURL returns the url to request the format from, signed and rewritten.
+func (y *Youtube) requestURL(f Format) (string, error) {
+	target, err := y.formatURL(f)
+	if err != nil {
+		return "", err
+	}
+	return y.rewriteURL(target)
+}
@@ -0,0 +1,61 @@
+package youtube
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestMirrorHost(t *testing.T) {
+	rewrite := MirrorHost("https://invidious.example.com/")
+	got, err := rewrite("https://r1---sn-abc.googlevideo.com/videoplayback?itag=18")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://invidious.example.com/videoplayback?host=r1---sn-abc.googlevideo.com&itag=18"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestRewriteURLDownload(t *testing.T) {
+	var host string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.URL.Query().Get("host")
+		w.Write([]byte("video data"))
+	}))
+	defer mirror.Close()
+
+	dest := filepath.Join(t.TempDir(), "dl.mp4")
+	y := NewYoutube(false)
+	y.RewriteURL = MirrorHost(mirror.URL)
+	y.StreamList = []stream{{"itag": "18", "url": "http://blocked.invalid/videoplayback?itag=18"}}
+	if err := y.StartDownload(dest); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != "video data" || host != "blocked.invalid" {
+		t.Errorf("Wrong mirrored download %q from host %q", b, host)
+	}
+}
+
+func TestRewriteURLValidate(t *testing.T) {
+	var host string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.URL.Query().Get("host")
+		w.WriteHeader(http.StatusPartialContent)
+	}))
+	defer mirror.Close()
+
+	y := NewYoutube(false)
+	y.RewriteURL = MirrorHost(mirror.URL)
+	f := Format{Itag: 18, URL: "http://blocked.invalid/videoplayback?itag=18"}
+	if err := y.ValidateFormat(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if host != "blocked.invalid" {
+		t.Errorf("Validated through host %q", host)
+	}
+}
@@ -0,0 +1,278 @@
+package youtube
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	selectorItemRe   = regexp.MustCompile(`^(\w+)((?:\[[^\]]*\])*)$`)
+	selectorFilterRe = regexp.MustCompile(`\[\s*(\w+)\s*(<=|>=|!=|<|>|=)\s*([^\]]*?)\s*\]`)
+)
+
+//SelectFormats : Pick formats with a selector, a subset of the youtube-dl format syntax.
+//Alternatives are separated by "/", formats to merge by "+", and each format is
+//best, worst, bestvideo, worstvideo, bestaudio, worstaudio or an itag, optionally
+//filtered on height, width, fps, bitrate, filesize, itag, hdr (0 or 1), ext or
+//lang, e.g. "bestvideo[height<=1080]+bestaudio/best". HDR is preferred with
+// "bestvideo[hdr=1]/bestvideo" and avoided with "bestvideo[hdr=0]". The default
+//audio track is picked unless lang asks for another one, e.g. "bestaudio[lang=pt-BR]".
+//Formats are ranked by resolution, frame rate then bitrate unless SortFormats is used.
+//With MaxFileSize, the best formats whose total size fits are picked, probing the
+//unknown sizes and leaving out the formats whose size can't be known.
+func (y *Youtube) SelectFormats(selector string) ([]Format, error) {
+	formats := y.Formats
+	if y.MaxFileSize > 0 {
+		formats = y.probeSizes(formats)
+	}
+	return selectFormats(formats, selector, y.formatRanking(), y.MaxFileSize)
+}
+
+func selectFormats(formats []Format, selector string, better func(a, b Format) bool, maxSize int64) ([]Format, error) {
+	for _, alt := range strings.Split(selector, "/") {
+		picked, err := selectAlternative(formats, alt, better, maxSize)
+		if err != nil {
+			return nil, err
+		}
+		if picked != nil {
+			return picked, nil
+		}
+	}
+	if maxSize > 0 {
+		return nil, fmt.Errorf("no format matches the selector '%s' under %d bytes", selector, maxSize)
+	}
+	return nil, fmt.Errorf("no format matches the selector '%s'", selector)
+}
+
+//selectAlternative picks the formats to merge, dropping the largest of them
+//from the candidates until their total size fits under maxSize.
+func selectAlternative(formats []Format, alt string, better func(a, b Format) bool, maxSize int64) ([]Format, error) {
+	for {
+		var picked []Format
+		for _, item := range strings.Split(alt, "+") {
+			f, found, err := selectFormat(formats, strings.TrimSpace(item), better)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				return nil, nil
+			}
+			picked = append(picked, f)
+		}
+		if maxSize <= 0 || totalSize(picked) <= maxSize {
+			return picked, nil
+		}
+		largest := picked[0]
+		for _, f := range picked {
+			if f.ContentLength > largest.ContentLength {
+				largest = f
+			}
+		}
+		formats = withoutItag(formats, largest.Itag)
+	}
+}
+
+func selectFormat(formats []Format, item string, better func(a, b Format) bool) (Format, bool, error) {
+	subs := selectorItemRe.FindStringSubmatch(item)
+	if subs == nil {
+		return Format{}, false, fmt.Errorf("invalid format selector '%s'", item)
+	}
+	name := subs[1]
+	filters := selectorFilterRe.FindAllStringSubmatch(subs[2], -1)
+	if len(selectorFilterRe.ReplaceAllString(subs[2], "")) > 0 {
+		return Format{}, false, fmt.Errorf("invalid format filter '%s'", subs[2])
+	}
+
+	var best Format
+	var found bool
+	for _, f := range formats {
+		ok, err := matchFormat(f, name)
+		if err != nil {
+			return Format{}, false, err
+		}
+		for _, flt := range filters {
+			if !ok {
+				break
+			}
+			if ok, err = filterFormat(f, flt[1], flt[2], flt[3]); err != nil {
+				return Format{}, false, err
+			}
+		}
+		if !ok {
+			continue
+		}
+		switch {
+		case !found, isDefaultTrack(f) && !isDefaultTrack(best):
+			best, found = f, true
+		case isDefaultTrack(f) != isDefaultTrack(best):
+			//keep the default audio track
+		case better(f, best) != strings.HasPrefix(name, "worst"):
+			best = f
+		}
+	}
+	return best, found, nil
+}
+
+func matchFormat(f Format, name string) (bool, error) {
+	switch name {
+	case "best", "worst":
+		return f.HasVideo && f.HasAudio, nil
+	case "bestvideo", "worstvideo":
+		return f.HasVideo && !f.HasAudio, nil
+	case "bestaudio", "worstaudio":
+		return f.HasAudio && !f.HasVideo, nil
+	}
+	itag, err := strconv.Atoi(name)
+	if err != nil {
+		return false, fmt.Errorf("unknown format '%s'", name)
+	}
+	return f.Itag == itag, nil
+}
+
+func filterFormat(f Format, field, op, value string) (bool, error) {
+	if field == "ext" || field == "lang" {
+		actual := f.Ext()
+		if field == "lang" {
+			actual = ""
+			if f.AudioTrack != nil {
+				actual = f.AudioTrack.Language
+			}
+		}
+		switch op {
+		case "=":
+			return actual == value, nil
+		case "!=":
+			return actual != value, nil
+		}
+		return false, fmt.Errorf("invalid operator '%s' for %s", op, field)
+	}
+
+	var actual int64
+	switch field {
+	case "height":
+		actual = int64(f.Height)
+	case "width":
+		actual = int64(f.Width)
+	case "fps":
+		actual = int64(f.FPS)
+	case "bitrate":
+		actual = int64(f.Bitrate)
+	case "filesize":
+		actual = f.ContentLength
+	case "itag":
+		actual = int64(f.Itag)
+	case "hdr":
+		if f.HDR {
+			actual = 1
+		}
+	default:
+		return false, fmt.Errorf("unknown format field '%s'", field)
+	}
+	expected, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid value '%s' for %s", value, field)
+	}
+	switch op {
+	case "<":
+		return actual < expected, nil
+	case "<=":
+		return actual <= expected, nil
+	case ">":
+		return actual > expected, nil
+	case ">=":
+		return actual >= expected, nil
+	case "=":
+		return actual == expected, nil
+	}
+	return actual != expected, nil
+}
+
+//isDefaultTrack tells if the format has the default audio track, or no
+//audio track information.
+func isDefaultTrack(f Format) bool {
+	return f.AudioTrack == nil || f.AudioTrack.Default
+}
+
+//betterFormat is the default ranking, by resolution, then frame rate, then bitrate.
+func betterFormat(a, b Format) bool {
+	if a.Height != b.Height {
+		return a.Height > b.Height
+	}
+	if a.FPS != b.FPS {
+		return a.FPS > b.FPS
+	}
+	if a.Bitrate != b.Bitrate {
+		return a.Bitrate > b.Bitrate
+	}
+	return a.ContentLength > b.ContentLength
+}
+
+//downloadSelected downloads the formats picked by FormatSelector, best by default, merging
+//separate video and audio formats with ffmpeg.
+func (y *Youtube) downloadSelected(destFile string) error {
+	selector := y.FormatSelector
+	if selector == "" {
+		selector = "best"
+	}
+	formats, err := y.SelectFormats(selector)
+	if err != nil {
+		return err
+	}
+	if len(formats) == 1 {
+		return y.downloadFormat(destFile, formats[0])
+	}
+
+	var parts []string
+	defer func() {
+		y.endPhases()
+		for _, p := range parts {
+			os.Remove(p)
+		}
+	}()
+	count := len(formats) + 1
+	for i, f := range formats {
+		phase := PhaseDownloadVideo
+		if !f.HasVideo {
+			phase = PhaseDownloadAudio
+		}
+		y.beginPhase(i, count, phase)
+		part := fmt.Sprintf("%s.f%d.%s", destFile, f.Itag, f.Ext())
+		parts = append(parts, part)
+		if err := y.downloadFormat(part, f); err != nil {
+			return err
+		}
+	}
+	y.beginPhase(count-1, count, PhaseMerge)
+	y.reportProgress()
+	if err := y.mergeFiles(destFile, parts); err != nil {
+		return err
+	}
+	y.downloadLevel = 100
+	y.reportProgress()
+	return nil
+}
+
+func (y *Youtube) downloadFormat(destFile string, f Format) error {
+	y.log(fmt.Sprintf("Download format itag=%d to file=%s", f.Itag, destFile))
+	target, err := y.formatURL(f)
+	if err != nil {
+		return err
+	}
+	y.expectedSize = f.ExpectedSize()
+	y.streamItag = f.Itag
+	defer func() { y.expectedSize, y.streamItag = 0, 0 }()
+	err = y.videoDLWorker(destFile, target)
+	if err == ErrStreamForbidden {
+		y.log(fmt.Sprintf("Download forbidden, refreshing format url for itag=%d", f.Itag))
+		if target, err = y.refreshStreamURL(strconv.Itoa(f.Itag)); err == nil {
+			err = y.videoDLWorker(destFile, target)
+		}
+	}
+	if err == nil {
+		y.recordStream(f.Itag)
+	}
+	return err
+}
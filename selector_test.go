@@ -0,0 +1,141 @@
+package youtube
+
+import (
+	"testing"
+	"time"
+)
+
+var testFormats = []Format{
+	{Itag: 22, MimeType: `video/mp4; codecs="avc1.64001F, mp4a.40.2"`, Height: 720, HasVideo: true, HasAudio: true},
+	{Itag: 18, MimeType: `video/mp4; codecs="avc1.42001E, mp4a.40.2"`, Height: 360, HasVideo: true, HasAudio: true},
+	{Itag: 137, MimeType: `video/mp4; codecs="avc1.640028"`, Height: 1080, FPS: 30, Bitrate: 4000000, HasVideo: true},
+	{Itag: 313, MimeType: `video/webm; codecs="vp9"`, Height: 2160, FPS: 30, Bitrate: 16000000, HasVideo: true},
+	{Itag: 140, MimeType: `audio/mp4; codecs="mp4a.40.2"`, Bitrate: 128000, HasAudio: true},
+	{Itag: 251, MimeType: `audio/webm; codecs="opus"`, Bitrate: 160000, HasAudio: true},
+}
+
+func TestSelectFormats(t *testing.T) {
+	tests := []struct {
+		selector string
+		itags    []int
+	}{
+		{"best", []int{22}},
+		{"worst", []int{18}},
+		{"bestvideo+bestaudio", []int{313, 251}},
+		{"bestvideo[height<=1080]+bestaudio[ext=m4a]/best", []int{137, 140}},
+		{"bestvideo[height<=1080]+bestaudio[ext=mp3]/best", []int{22}},
+		{"bestvideo[height>4000]/18", []int{18}},
+	}
+	for _, tt := range tests {
+		formats, err := selectFormats(testFormats, tt.selector, betterFormat, 0)
+		if err != nil {
+			t.Errorf("%s: %s", tt.selector, err)
+			continue
+		}
+		var itags []int
+		for _, f := range formats {
+			itags = append(itags, f.Itag)
+		}
+		if len(itags) != len(tt.itags) || itags[0] != tt.itags[0] || itags[len(itags)-1] != tt.itags[len(tt.itags)-1] {
+			t.Errorf("%s: selected %v, want %v", tt.selector, itags, tt.itags)
+		}
+	}
+
+	for _, selector := range []string{"bestvideo[height>4000]", "best[size<3]", "best[height<abc]", "good"} {
+		if _, err := selectFormats(testFormats, selector, betterFormat, 0); err == nil {
+			t.Errorf("%s: selector should fail", selector)
+		}
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	formats := parseFormats("itag=137&type=video%2Fmp4&size=1920x1080&fps=30&clen=1000&url=https%3A%2F%2Fr1.googlevideo.com%2F,"+
+		"itag=140&type=audio%2Fmp4&bitrate=128000&url=https%3A%2F%2Fr1.googlevideo.com%2F", false)
+	if len(formats) != 2 {
+		t.Fatalf("Wrong formats: %+v", formats)
+	}
+	if f := formats[0]; f.Height != 1080 || f.FPS != 30 || !f.HasVideo || f.HasAudio || f.Ext() != "mp4" || f.ContentLength != 1000 {
+		t.Errorf("Wrong video format: %+v", f)
+	}
+	if f := formats[1]; f.Bitrate != 128000 || f.HasVideo || !f.HasAudio {
+		t.Errorf("Wrong audio format: %+v", f)
+	}
+}
+
+func TestHDRFormats(t *testing.T) {
+	formats := parseFormats("itag=337&type=video%2Fwebm&size=3840x2160&quality_label=2160p60+HDR&url=https%3A%2F%2Fr1.googlevideo.com%2F,"+
+		"itag=315&type=video%2Fwebm&size=3840x2160&quality_label=2160p60&url=https%3A%2F%2Fr1.googlevideo.com%2F,"+
+		"itag=701&type=video%2Fmp4&size=3840x2160&fps=60&url=https%3A%2F%2Fr1.googlevideo.com%2F", false)
+	applyStreamingInfo(formats, `{"streamingData":{"adaptiveFormats":[
+		{"itag":315,"colorInfo":{"transferCharacteristics":"COLOR_TRANSFER_CHARACTERISTICS_BT709"}},
+		{"itag":701,"colorInfo":{"transferCharacteristics":"COLOR_TRANSFER_CHARACTERISTICS_SMPTEST2084"}}]}}`)
+	if f := formats[0]; !f.HDR || f.FPS != 60 {
+		t.Errorf("Wrong labelled HDR format: %+v", f)
+	}
+	if f := formats[1]; f.HDR || f.ColorTransfer != "bt709" {
+		t.Errorf("Wrong SDR format: %+v", f)
+	}
+	if f := formats[2]; !f.HDR || f.ColorTransfer != "smpte2084" {
+		t.Errorf("Wrong PQ format: %+v", f)
+	}
+
+	if picked, err := selectFormats(formats, "bestvideo[hdr=0][fps>=60]", betterFormat, 0); err != nil || picked[0].Itag != 315 {
+		t.Errorf("SDR selection picked %v, err=%v", picked, err)
+	}
+}
+
+func TestAudioTracks(t *testing.T) {
+	y := NewYoutube(false)
+	y.Formats = []Format{
+		{Itag: 140, MimeType: "audio/mp4", Bitrate: 128000, HasAudio: true},
+		{Itag: 140, MimeType: "audio/mp4", Bitrate: 130000, HasAudio: true},
+		{Itag: 251, MimeType: "audio/webm", Bitrate: 160000, HasAudio: true},
+	}
+	applyStreamingInfo(y.Formats, `{"streamingData":{"adaptiveFormats":[
+		{"itag":140,"audioTrack":{"id":"en.4","displayName":"English original","audioIsDefault":true}},
+		{"itag":140,"audioTrack":{"id":"pt-BR.3","displayName":"Portuguese"}},
+		{"itag":251,"audioTrack":{"id":"pt-BR.3","displayName":"Portuguese"}}]}}`)
+
+	tracks := y.AudioTracks()
+	if len(tracks) != 2 || tracks[0].Language != "en" || !tracks[0].Default || tracks[1].Language != "pt-BR" {
+		t.Errorf("Wrong audio tracks: %+v", tracks)
+	}
+	if f, err := y.SelectFormats("bestaudio"); err != nil || f[0].Bitrate != 128000 {
+		t.Errorf("Default track not selected: %+v, err=%v", f, err)
+	}
+	if f, err := y.SelectFormats("bestaudio[lang=pt-BR]"); err != nil || f[0].Itag != 251 {
+		t.Errorf("Dubbed track not selected: %+v, err=%v", f, err)
+	}
+}
+
+func TestSortFormats(t *testing.T) {
+	y := NewYoutube(false)
+	y.Formats = append([]Format(nil), testFormats...)
+	y.SortFormats(func(a, b Format) bool { return a.Bitrate < b.Bitrate })
+	if y.Formats[len(y.Formats)-1].Itag != 313 {
+		t.Errorf("Formats not sorted by bitrate: %v", y.Formats)
+	}
+	picked, err := y.SelectFormats("bestvideo+bestaudio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if picked[0].Itag != 137 || picked[1].Itag != 140 {
+		t.Errorf("Picked %d+%d, want the lowest bitrates 137+140", picked[0].Itag, picked[1].Itag)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	formats := parseFormats("itag=251&type=audio%2Fwebm&bitrate=160000&url=https%3A%2F%2Fr1.googlevideo.com%2F", false)
+	applyStreamingInfo(formats, `{"streamingData":{"adaptiveFormats":[
+		{"itag":251,"approxDurationMs":"60000","averageBitrate":128000}]}}`)
+	if f := formats[0]; f.Duration != time.Minute || f.AverageBitrate != 128000 {
+		t.Errorf("Wrong duration: %+v", f)
+	}
+	if size := formats[0].ExpectedSize(); size != 960000 {
+		t.Errorf("Wrong expected size %d", size)
+	}
+	formats[0].ContentLength = 1000
+	if size := formats[0].ExpectedSize(); size != 1000 {
+		t.Errorf("The content length should be the expected size, got %d", size)
+	}
+}
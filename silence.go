@@ -0,0 +1,70 @@
+package youtube
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+//SilenceDetect : Settings of the track boundaries found in the silences of the
+//audio, to split the long mixes that have no chapters.
+type SilenceDetect struct {
+	//NoiseDB is the level under which the audio is silent, e.g. -50.
+	NoiseDB float64
+	//MinSilence is the shortest silence between two tracks.
+	MinSilence time.Duration
+	//MinTrack merges the tracks shorter than this with the next one.
+	MinTrack time.Duration
+}
+
+//DefaultSilenceDetect : Settings that fit most music mixes.
+var DefaultSilenceDetect = SilenceDetect{NoiseDB: -50, MinSilence: 2 * time.Second, MinTrack: 30 * time.Second}
+
+var silenceRe = regexp.MustCompile(`silence_(start|end): (-?[0-9.]+)`)
+
+//DetectChapters : Find the tracks of an audio or video file in its silences
+//with ffmpeg, cut in the middle of each silence and titled "Track 01".., a
+//single chapter when there is no silence.
+func (y *Youtube) DetectChapters(file string, s SilenceDetect) ([]Chapter, error) {
+	filter := fmt.Sprintf("silencedetect=noise=%gdB:d=%s", s.NoiseDB, formatSeconds(s.MinSilence))
+	out, err := y.ffmpegOutput("info", "-i", file, "-vn", "-af", filter, "-f", "null", "-")
+	if err != nil {
+		return nil, err
+	}
+	chapters := parseSilences(string(out), s.MinTrack)
+	y.log(fmt.Sprintf("Detected %d tracks in the silences of file=%s", len(chapters), file))
+	return chapters, nil
+}
+
+//parseSilences turns the silencedetect logs into chapters. A silence still
+//running at the end of the file has no silence_end, it is not a boundary.
+func parseSilences(log string, minTrack time.Duration) []Chapter {
+	var cuts []time.Duration
+	var start time.Duration
+	for _, m := range silenceRe.FindAllStringSubmatch(log, -1) {
+		seconds, _ := strconv.ParseFloat(m[2], 64)
+		at := time.Duration(seconds * float64(time.Second))
+		if m[1] == "start" {
+			start = at
+			continue
+		}
+		cut := (start + at) / 2
+		last := time.Duration(0)
+		if len(cuts) > 0 {
+			last = cuts[len(cuts)-1]
+		}
+		if cut-last >= minTrack {
+			cuts = append(cuts, cut)
+		}
+	}
+	chapters := []Chapter{{}}
+	for _, cut := range cuts {
+		chapters[len(chapters)-1].End = cut
+		chapters = append(chapters, Chapter{Start: cut})
+	}
+	for i := range chapters {
+		chapters[i].Title = fmt.Sprintf("Track %02d", i+1)
+	}
+	return chapters
+}
@@ -0,0 +1,41 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const silenceLog = `[silencedetect @ 0x1] silence_start: 0
+[silencedetect @ 0x1] silence_end: 1.5 | silence_duration: 1.5
+[silencedetect @ 0x1] silence_start: 180
+[silencedetect @ 0x1] silence_end: 184 | silence_duration: 4
+[silencedetect @ 0x1] silence_start: 190
+[silencedetect @ 0x1] silence_end: 193 | silence_duration: 3
+[silencedetect @ 0x1] silence_start: 400.5
+`
+
+func TestParseSilences(t *testing.T) {
+	chapters := parseSilences(silenceLog, 30*time.Second)
+	if len(chapters) != 2 || chapters[0].End != 182*time.Second || chapters[1].Start != 182*time.Second ||
+		chapters[1].End != 0 || chapters[1].Title != "Track 02" {
+		t.Errorf("Wrong tracks: %+v", chapters)
+	}
+	if chapters := parseSilences("", 0); len(chapters) != 1 || chapters[0].End != 0 {
+		t.Errorf("Wrong tracks without silence: %+v", chapters)
+	}
+}
+
+func TestDetectChapters(t *testing.T) {
+	y := NewYoutube(false)
+	y.FFmpegPath = filepath.Join(t.TempDir(), "ffmpeg")
+	script := "#!/bin/sh\ncat >&2 <<'EOF'\n" + silenceLog + "EOF\n"
+	if err := ioutil.WriteFile(y.FFmpegPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	chapters, err := y.DetectChapters("mix.m4a", DefaultSilenceDetect)
+	if err != nil || len(chapters) != 2 {
+		t.Errorf("Wrong detected tracks: %+v, err=%v", chapters, err)
+	}
+}
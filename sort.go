@@ -0,0 +1,29 @@
+package youtube
+
+import "sort"
+
+//SortFormats : Rank the formats with less, which reports whether a is better than b,
+//e.g. the lowest bitrate first for bandwidth tests. Formats is kept in that order and
+//the best and worst selectors pick the first and last matching format by it.
+func (y *Youtube) SortFormats(less func(a, b Format) bool) {
+	y.formatLess = less
+	y.sortFormats()
+}
+
+//sortFormats orders Formats with the SortFormats ranking, if any.
+func (y *Youtube) sortFormats() {
+	if y.formatLess == nil {
+		return
+	}
+	sort.SliceStable(y.Formats, func(i, j int) bool {
+		return y.formatLess(y.Formats[i], y.Formats[j])
+	})
+}
+
+//formatRanking is the SortFormats ranking or the default one.
+func (y *Youtube) formatRanking() func(a, b Format) bool {
+	if y.formatLess != nil {
+		return y.formatLess
+	}
+	return betterFormat
+}
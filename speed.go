@@ -0,0 +1,60 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+//ErrTooSlow : Returned when the download speed stays under MinSpeed for MinSpeedDuration.
+var ErrTooSlow = errors.New("download speed under the minimum")
+
+//DefaultMinSpeedDuration : Window used when MinSpeed is set without MinSpeedDuration.
+const DefaultMinSpeedDuration = 30 * time.Second
+
+//speedReader cancels the download when less than minSpeed bytes per second
+//are read during a whole window, stalled reads included.
+type speedReader struct {
+	r        io.Reader
+	minBytes int64
+	cancel   context.CancelFunc
+	timer    *time.Timer
+
+	mu      sync.Mutex
+	read    int64
+	tooSlow bool
+}
+
+func newSpeedReader(r io.Reader, minSpeed int64, window time.Duration, cancel context.CancelFunc) *speedReader {
+	s := &speedReader{r: r, minBytes: int64(float64(minSpeed) * window.Seconds()), cancel: cancel}
+	var check func()
+	check = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.read < s.minBytes {
+			s.tooSlow = true
+			s.cancel()
+			return
+		}
+		s.read = 0
+		s.timer = time.AfterFunc(window, check)
+	}
+	s.timer = time.AfterFunc(window, check)
+	return s
+}
+
+func (s *speedReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.read += int64(n)
+	if s.tooSlow {
+		return n, ErrTooSlow
+	}
+	if err != nil {
+		s.timer.Stop()
+	}
+	return n, err
+}
@@ -0,0 +1,34 @@
+package youtube
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMinSpeed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1000")
+		w.Write(make([]byte, 10))
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.MinSpeed = 1000
+	y.MinSpeedDuration = 20 * time.Millisecond
+	start := time.Now()
+	err := y.videoDLWorker(filepath.Join(t.TempDir(), "dl.mp4"), ts.URL)
+	if err != ErrTooSlow {
+		t.Errorf("Slow download should fail with ErrTooSlow, err=%v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Slow download should be aborted early")
+	}
+}
@@ -0,0 +1,92 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//VideoStats : View and like counts of a video at a time, with the change since
+//the previous stats of a WatchStats.
+type VideoStats struct {
+	Time       time.Time
+	Views      int64
+	Likes      int64
+	ViewsDelta int64
+	LikesDelta int64
+}
+
+//GetVideoStats : Retrieval the current view and like counts of the decoded video.
+func (y *Youtube) GetVideoStats() (VideoStats, error) {
+	if y.VideoID == "" {
+		return VideoStats{}, errors.New("no video decoded")
+	}
+	data, err := y.fetchInitialData("https://www.youtube.com/watch?v=" + y.VideoID)
+	if err != nil {
+		return VideoStats{}, err
+	}
+	stats := parseVideoStats(data)
+	stats.Time = time.Now()
+	return stats, nil
+}
+
+//WatchStats : Fetch the stats of the decoded video every interval until ctx is
+//done, then the channel is closed. Failed fetches are logged and skipped.
+func (y *Youtube) WatchStats(ctx context.Context, interval time.Duration) <-chan VideoStats {
+	out := make(chan VideoStats)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last *VideoStats
+		for {
+			stats, err := y.GetVideoStats()
+			if err != nil {
+				y.log(fmt.Sprintf("Fetch video stats error=%s", err))
+			} else {
+				if last != nil {
+					stats.ViewsDelta = stats.Views - last.Views
+					stats.LikesDelta = stats.Likes - last.Likes
+				}
+				last = &stats
+				select {
+				case out <- stats:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+//parseVideoStats reads the counts of the watch page.
+func parseVideoStats(data interface{}) VideoStats {
+	var stats VideoStats
+	walkJSON(data, func(key string, v map[string]interface{}) {
+		switch key {
+		case "videoViewCountRenderer":
+			if stats.Views == 0 {
+				stats.Views = parseCount(jsonText(v["viewCount"]))
+			}
+		case "likeButtonViewModel":
+			walkJSON(v, func(key string, v map[string]interface{}) {
+				if key != "buttonViewModel" || stats.Likes != 0 {
+					return
+				}
+				//the accessibility text has the exact count, the title is rounded
+				stats.Likes = parseCount(jsonString(v["accessibilityText"]))
+				if stats.Likes == 0 {
+					stats.Likes = parseCount(jsonString(v["title"]))
+				}
+			})
+		}
+	})
+	return stats
+}
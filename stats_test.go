@@ -0,0 +1,18 @@
+package youtube
+
+import "testing"
+
+func TestParseVideoStats(t *testing.T) {
+	page := `<script>var ytInitialData = {"contents":{"videoPrimaryInfoRenderer":{
+"viewCount":{"videoViewCountRenderer":{"viewCount":{"simpleText":"1,234,567 views"},"shortViewCount":{"simpleText":"1.2M views"}}},
+"videoActions":{"segmentedLikeDislikeButtonViewModel":{"likeButtonViewModel":{"likeButtonViewModel":{"toggleButtonViewModel":{"toggleButtonViewModel":{
+"defaultButtonViewModel":{"buttonViewModel":{"title":"12K","accessibilityText":"like this video along with 12,345 other people"}}}}}}}}}}};</script>`
+	data, err := extractInitialData([]byte(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := parseVideoStats(data)
+	if stats.Views != 1234567 || stats.Likes != 12345 {
+		t.Errorf("Wrong stats: %+v", stats)
+	}
+}
@@ -0,0 +1,195 @@
+package youtube
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//StorageWriter : Destination of the downloaded files, the local disk by default.
+//Downloads that need ffmpeg run in a local temporary directory and only the
+//final file is written to the storage. A writer with an Abort() error method
+//is aborted instead of closed when the download fails.
+type StorageWriter interface {
+	Create(name string) (io.WriteCloser, error)
+}
+
+//closeOutput closes the output, or aborts it if the write failed and it can be.
+func closeOutput(out io.WriteCloser, failed error) error {
+	if a, ok := out.(interface{ Abort() error }); ok && failed != nil {
+		return a.Abort()
+	}
+	return out.Close()
+}
+
+//LocalStorage : Writes the files on disk, relative names are joined to Dir.
+type LocalStorage struct {
+	Dir string
+}
+
+//Create : Create the file and its parent directories.
+func (s LocalStorage) Create(name string) (io.WriteCloser, error) {
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(s.Dir, name)
+	}
+	name = longPath(name)
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(name)
+}
+
+//WriterStorage : Writes every file to the same writer, e.g. os.Stdout.
+type WriterStorage struct {
+	W io.Writer
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+//Create : Return the writer, the name is ignored.
+func (s WriterStorage) Create(name string) (io.WriteCloser, error) {
+	return nopWriteCloser{s.W}, nil
+}
+
+//singleFileStorage writes the file of the name to w and discards the others,
+//e.g. the subtitle sidecars.
+type singleFileStorage struct {
+	name string
+	w    io.Writer
+}
+
+func (s singleFileStorage) Create(name string) (io.WriteCloser, error) {
+	if name != s.name {
+		return nopWriteCloser{ioutil.Discard}, nil
+	}
+	return nopWriteCloser{s.w}, nil
+}
+
+//HTTPPutStorage : Uploads the files with PUT requests to BaseURL joined with the
+//file name, e.g. a WebDAV server, or to the url returned by URLFunc, e.g. S3 or
+//GCS presigned urls. The file is spooled to a temporary file so the request has
+//a Content-Length, S3 rejects the chunked uploads.
+type HTTPPutStorage struct {
+	BaseURL string
+	URLFunc func(name string) (string, error)
+	Header  http.Header
+	Client  *http.Client
+}
+
+//Create : Spool the file, Close uploads it and returns the result of the upload.
+func (s HTTPPutStorage) Create(name string) (io.WriteCloser, error) {
+	var target string
+	var err error
+	if s.URLFunc != nil {
+		target, err = s.URLFunc(name)
+	} else {
+		var u *url.URL
+		if u, err = url.Parse(s.BaseURL); err == nil {
+			u.Path = path.Join(u.Path, filepath.ToSlash(name))
+			target = u.String()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := ioutil.TempFile("", "youtube-upload")
+	if err != nil {
+		return nil, err
+	}
+	return &putUpload{File: tmp, storage: s, name: name, target: target}, nil
+}
+
+//putUpload is the spooled file of an upload.
+type putUpload struct {
+	*os.File
+	storage HTTPPutStorage
+	name    string
+	target  string
+}
+
+//Abort : Remove the spooled file without uploading it.
+func (u *putUpload) Abort() error {
+	defer os.Remove(u.File.Name())
+	return u.File.Close()
+}
+
+func (u *putUpload) Close() error {
+	defer os.Remove(u.File.Name())
+	defer u.File.Close()
+	size, err := u.File.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = u.File.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("PUT", u.target, u.File)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	for k, v := range u.storage.Header {
+		req.Header[k] = v
+	}
+	client := u.storage.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("upload of %s answered status code %d", u.name, resp.StatusCode)
+	}
+	return nil
+}
+
+//createOutput opens the download destination, in the storage unless ffmpeg
+//needs the file locally. A resumed local file is opened for appending.
+func (y *Youtube) createOutput(destFile string, resume bool) (io.WriteCloser, error) {
+	if y.Storage != nil && !y.localOutput {
+		return y.Storage.Create(destFile)
+	}
+	if resume {
+		return os.OpenFile(longPath(destFile), os.O_WRONLY|os.O_APPEND, 0644)
+	}
+	return LocalStorage{}.Create(destFile)
+}
+
+//needsLocalFiles tells if the download goes through ffmpeg or an external downloader.
+func (y *Youtube) needsLocalFiles() bool {
+	return strings.Contains(y.FormatSelector, "+") || y.RemuxTo != "" || y.Encode != nil ||
+		(y.EmbedSubtitles && len(y.Subtitles) > 0) || (y.FormatSelector == "" && y.onlyAdaptive()) ||
+		y.ExternalDownloader != nil || y.Normalize != nil || len(y.PostProcessors) > 0
+}
+
+//copyToStorage writes the local file to the storage under the name.
+func (y *Youtube) copyToStorage(localFile, name string) error {
+	in, err := os.Open(localFile)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := y.Storage.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if cerr := closeOutput(out, err); err == nil {
+		err = cerr
+	}
+	return err
+}
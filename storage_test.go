@@ -0,0 +1,88 @@
+package youtube
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestHTTPPutStorage(t *testing.T) {
+	video := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("video data"))
+	}))
+	defer video.Close()
+	uploads := make(map[string]string)
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//S3 refuses the chunked uploads
+		if r.Method != "PUT" || r.Header.Get("X-Token") != "secret" || r.ContentLength < 0 || len(r.TransferEncoding) > 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		uploads[r.URL.Path] = string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer store.Close()
+
+	y := NewYoutube(false)
+	y.Storage = HTTPPutStorage{BaseURL: store.URL + "/videos", Header: http.Header{"X-Token": {"secret"}}}
+	y.StreamList = []stream{{"itag": "18", "url": video.URL}}
+	if err := y.StartDownload("talks/dl.mp4"); err != nil {
+		t.Fatal(err)
+	}
+	if uploads["/videos/talks/dl.mp4"] != "video data" {
+		t.Errorf("Wrong uploads: %v", uploads)
+	}
+
+	y.Storage = HTTPPutStorage{BaseURL: store.URL}
+	if err := y.StartDownload("dl.mp4"); err == nil {
+		t.Error("Refused upload should fail")
+	}
+}
+
+func TestHTTPPutStorageAbort(t *testing.T) {
+	cut := truncatedServer()
+	defer cut.Close()
+	puts := 0
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		puts++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer store.Close()
+	spool := t.TempDir()
+	t.Setenv("TMPDIR", spool)
+
+	y := NewYoutube(false)
+	y.Storage = HTTPPutStorage{BaseURL: store.URL}
+	y.StreamList = []stream{{"itag": "18", "url": cut.URL}}
+	if err := y.StartDownload("dl.mp4"); err == nil {
+		t.Error("The cut download should fail")
+	}
+	if puts != 0 {
+		t.Errorf("The failed download was uploaded %d times", puts)
+	}
+	if files, _ := filepath.Glob(filepath.Join(spool, "youtube-upload*")); len(files) > 0 {
+		t.Errorf("The spooled files weren't removed: %v", files)
+	}
+}
+
+func TestWriterStorage(t *testing.T) {
+	video := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("video data"))
+	}))
+	defer video.Close()
+
+	var buf bytes.Buffer
+	y := NewYoutube(false)
+	y.Storage = WriterStorage{&buf}
+	y.StreamList = []stream{{"itag": "18", "url": video.URL}}
+	if err := y.StartDownload("dl.mp4"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "video data" {
+		t.Errorf("Wrong written data: %q", buf.String())
+	}
+}
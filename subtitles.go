@@ -0,0 +1,92 @@
+package youtube
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//subtitleTracks selects the caption track of each language of Subtitles,
+//missing languages are skipped.
+func (y *Youtube) subtitleTracks() []CaptionTrack {
+	var tracks []CaptionTrack
+	seen := make(map[string]bool)
+	for _, lang := range y.Subtitles {
+		t, err := y.SelectCaptionTrack([]string{lang}, y.AutoSubtitles)
+		if err != nil {
+			y.log(fmt.Sprintf("Skip %s subtitles: %s", lang, err))
+			continue
+		}
+		if !seen[t.URL] {
+			seen[t.URL] = true
+			tracks = append(tracks, t)
+		}
+	}
+	return tracks
+}
+
+//subtitleFile is the name of the sidecar file of the track, next to the video.
+func subtitleFile(videoFile string, t CaptionTrack) string {
+	return strings.TrimSuffix(videoFile, filepath.Ext(videoFile)) + "." + t.Language + ".vtt"
+}
+
+//downloadSubtitles writes the subtitles next to the video.
+func (y *Youtube) downloadSubtitles(videoFile string) error {
+	for _, t := range y.subtitleTracks() {
+		if err := y.DownloadCaptionTrack(subtitleFile(videoFile, t), t); err != nil {
+			return fmt.Errorf("%s subtitles: %s", t.Language, err)
+		}
+	}
+	return nil
+}
+
+//embedSubtitles muxes the subtitles into an mkv with their language tags and
+//returns the new file name.
+func (y *Youtube) embedSubtitles(file string) (string, error) {
+	tracks := y.subtitleTracks()
+	if len(tracks) == 0 {
+		return file, nil
+	}
+	args := []string{"-i", file}
+	var subs []string
+	defer func() {
+		for _, s := range subs {
+			os.Remove(s)
+		}
+	}()
+	for _, t := range tracks {
+		sub := subtitleFile(file, t)
+		subs = append(subs, sub)
+		if err := y.DownloadCaptionTrack(sub, t); err != nil {
+			return "", fmt.Errorf("%s subtitles: %s", t.Language, err)
+		}
+		args = append(args, "-i", sub)
+	}
+	for i := 0; i <= len(tracks); i++ {
+		args = append(args, "-map", strconv.Itoa(i))
+	}
+	args = append(args, "-c", "copy", "-c:s", "srt")
+	for i, t := range tracks {
+		args = append(args, fmt.Sprintf("-metadata:s:s:%d", i), "language="+t.Language)
+		if t.Name != "" {
+			args = append(args, fmt.Sprintf("-metadata:s:s:%d", i), "title="+t.Name)
+		}
+	}
+	args = append(args, y.sphericalArgs()...)
+
+	dest := strings.TrimSuffix(file, filepath.Ext(file)) + ".mkv"
+	out := dest + ".subs.mkv"
+	if err := y.runFFmpeg(append(args, out)...); err != nil {
+		os.Remove(out)
+		return "", err
+	}
+	if err := os.Rename(out, dest); err != nil {
+		return "", err
+	}
+	if dest != file {
+		os.Remove(file)
+	}
+	return dest, nil
+}
@@ -0,0 +1,76 @@
+package youtube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func subtitlesServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/timedtext" {
+			w.Write([]byte("WEBVTT " + r.URL.Query().Get("lang")))
+			return
+		}
+		w.Write([]byte("video data"))
+	}))
+}
+
+func TestSubtitleSidecars(t *testing.T) {
+	ts := subtitlesServer()
+	defer ts.Close()
+
+	dest := filepath.Join(t.TempDir(), "dl.mp4")
+	y := NewYoutube(false)
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	y.CaptionTracks = []CaptionTrack{
+		{Language: "en", URL: ts.URL + "/api/timedtext?lang=en"},
+		{Language: "de", AutoGenerated: true, URL: ts.URL + "/api/timedtext?lang=de"},
+	}
+	y.Subtitles = []string{"en", "de", "fr"}
+	if err := y.StartDownload(dest); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(strings.TrimSuffix(dest, ".mp4") + ".en.vtt"); string(b) != "WEBVTT en" {
+		t.Errorf("Wrong en subtitles %q", b)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(dest, ".mp4") + ".de.vtt"); !os.IsNotExist(err) {
+		t.Error("Auto generated subtitles need AutoSubtitles")
+	}
+}
+
+func TestEmbedSubtitles(t *testing.T) {
+	ts := subtitlesServer()
+	defer ts.Close()
+	dir := t.TempDir()
+	ffmpeg := filepath.Join(dir, "ffmpeg")
+	script := "#!/bin/sh\necho \"$@\" > " + filepath.Join(dir, "args") + "\nfor a; do last=\"$a\"; done\ncp \"$5\" \"$last\"\n"
+	if err := ioutil.WriteFile(ffmpeg, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	y := NewYoutube(false)
+	y.FFmpegPath = ffmpeg
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	y.CaptionTracks = []CaptionTrack{{Language: "pt-BR", Name: "Portuguese", URL: ts.URL + "/api/timedtext?lang=pt-BR"}}
+	y.Subtitles = []string{"pt"}
+	y.EmbedSubtitles = true
+	result, err := y.Download(filepath.Join(dir, "dl.mp4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Path != filepath.Join(dir, "dl.mkv") {
+		t.Errorf("Wrong embedded file: %s", result.Path)
+	}
+	args, _ := ioutil.ReadFile(filepath.Join(dir, "args"))
+	if !strings.Contains(string(args), "-map 0 -map 1 -c copy -c:s srt -metadata:s:s:0 language=pt-BR -metadata:s:s:0 title=Portuguese") {
+		t.Errorf("Wrong ffmpeg args: %s", args)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dl.pt-BR.vtt")); !os.IsNotExist(err) {
+		t.Error("Embedded subtitles should be removed")
+	}
+}
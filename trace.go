@@ -0,0 +1,59 @@
+package youtube
+
+import "context"
+
+//Tracer : Starts the spans traced around video information requests,
+//deciphering, downloads and ffmpeg runs. An OpenTelemetry tracer only needs a
+//small adapter to implement it, so the package doesn't depend on it.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+//Span : A traced operation started by a Tracer.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+//traceSpan nests the spans of a Youtube, restoring the parent on end.
+type traceSpan struct {
+	y      *Youtube
+	span   Span
+	parent context.Context
+}
+
+//startSpan starts a span when a Tracer is set, attrs are key and value pairs.
+func (y *Youtube) startSpan(name string, attrs ...interface{}) *traceSpan {
+	if y.Tracer == nil {
+		return nil
+	}
+	parent := y.traceCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, span := y.Tracer.Start(parent, name)
+	y.traceCtx = ctx
+	s := &traceSpan{y: y, span: span, parent: parent}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		s.set(attrs[i].(string), attrs[i+1])
+	}
+	return s
+}
+
+func (s *traceSpan) set(key string, value interface{}) {
+	if s != nil {
+		s.span.SetAttribute(key, value)
+	}
+}
+
+func (s *traceSpan) end(err error) {
+	if s == nil {
+		return
+	}
+	if err != nil {
+		s.span.RecordError(err)
+	}
+	s.span.End()
+	s.y.traceCtx = s.parent
+}
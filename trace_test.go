@@ -0,0 +1,61 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	name   string
+	parent string
+	attrs  map[string]interface{}
+	ended  bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.attrs["error"] = err }
+func (s *testSpan) End()                                       { s.ended = true }
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	s := &testSpan{name: name, attrs: make(map[string]interface{})}
+	if p, ok := ctx.Value(testSpanKey{}).(*testSpan); ok {
+		s.parent = p.name
+	}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, testSpanKey{}, s), s
+}
+
+func TestTracer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 100))
+	}))
+	defer ts.Close()
+
+	tracer := &testTracer{}
+	y := NewYoutube(false)
+	y.Tracer = tracer
+	y.VideoID = "rFejpH_tAHM"
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL}}
+	if err := y.StartDownload(filepath.Join(t.TempDir(), "dl.mp4")); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Wrong spans: %+v", tracer.spans)
+	}
+	dl := tracer.spans[1]
+	if dl.name != "youtube.download" || dl.parent != "youtube.start_download" || !dl.ended || dl.attrs["bytes"] != int64(100) {
+		t.Errorf("Wrong download span: %+v", dl)
+	}
+	if y.traceCtx != context.Background() {
+		t.Error("Trace context should be restored")
+	}
+}
@@ -0,0 +1,81 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+//ErrStreamForbidden : The stream url was refused, it expired or is throttled.
+//A StreamTransport returns it so the url is refreshed and the download retried once.
+var ErrStreamForbidden = errors.New("403 forbidden status code received")
+
+//Stream : A media stream opened by a StreamTransport.
+type Stream struct {
+	Body io.ReadCloser
+	//Offset is the position of the first byte of Body, the requested offset
+	//when the transport resumed the stream, 0 when it starts over.
+	Offset int64
+	//Length is the number of bytes of Body, -1 when unknown. A resumed stream
+	//that is already complete has an empty Body of Length 0.
+	Length int64
+	//URL is the url of the stream after the redirects.
+	URL string
+}
+
+//StreamTransport : Fetches the bytes of the media streams, the http client of the
+//Youtube object when nil. Implement it to download with another client, e.g. a
+//QUIC one, or one impersonating the TLS fingerprint of a browser against throttling.
+//The video information requests still use the http client.
+type StreamTransport interface {
+	//OpenStream starts reading the stream at offset. The rate limit, the idle
+	//timeout and the progress apply to the returned Body.
+	OpenStream(ctx context.Context, url string, offset int64) (*Stream, error)
+}
+
+//streamTransport is the StreamTransport option or the http one.
+func (y *Youtube) streamTransport() StreamTransport {
+	if y.StreamTransport != nil {
+		return y.StreamTransport
+	}
+	return httpStreamTransport{y}
+}
+
+//httpStreamTransport gets the streams with the http client and Range requests.
+type httpStreamTransport struct {
+	y *Youtube
+}
+
+func (t httpStreamTransport) OpenStream(ctx context.Context, url string, offset int64) (*Stream, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := t.y.do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	st := &Stream{Body: resp.Body, Length: resp.ContentLength, URL: resp.Request.URL.String()}
+	switch {
+	case resp.StatusCode == http.StatusForbidden:
+		resp.Body.Close()
+		return nil, ErrStreamForbidden
+	case offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		st.Body, st.Offset, st.Length = ioutil.NopCloser(strings.NewReader("")), offset, 0
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		st.Offset = offset
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close()
+		t.y.log(fmt.Sprintf("reading answer: non 200[code=%v] status code received", resp.StatusCode))
+		return nil, errors.New("non 200 status code received")
+	}
+	return st, nil
+}
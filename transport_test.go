@@ -0,0 +1,41 @@
+package youtube
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//fakeStreams serves "video data" from memory, and can resume.
+type fakeStreams struct {
+	urls []string
+}
+
+func (f *fakeStreams) OpenStream(ctx context.Context, url string, offset int64) (*Stream, error) {
+	f.urls = append(f.urls, url)
+	rest := "video data"[offset:]
+	return &Stream{Body: ioutil.NopCloser(strings.NewReader(rest)), Offset: offset, Length: int64(len(rest)), URL: url}, nil
+}
+
+func TestStreamTransport(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "dl.mp4")
+	if err := ioutil.WriteFile(dest, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	streams := &fakeStreams{}
+	y := NewYoutube(false)
+	y.StreamTransport = streams
+	y.OnExists = ExistsResume
+	y.StreamList = []stream{{"itag": "18", "url": "https://r1.googlevideo.com/videoplayback"}}
+	if err := y.StartDownload(dest); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != "video data" {
+		t.Errorf("Resumed file contains %q", b)
+	}
+	if len(streams.urls) != 1 || y.BytesReceived() != 0 {
+		t.Errorf("The http client should not be used: %v, %d bytes", streams.urls, y.BytesReceived())
+	}
+}
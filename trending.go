@@ -0,0 +1,44 @@
+package youtube
+
+import (
+	"fmt"
+	"net/url"
+)
+
+//Trending categories
+const (
+	TrendingNow    = ""
+	TrendingMusic  = "music"
+	TrendingGaming = "gaming"
+	TrendingMovies = "movies"
+)
+
+//trendingParams are the bp parameters selecting the tabs of the trending page.
+var trendingParams = map[string]string{
+	TrendingMusic:  "4gINGgt5dG1hX2NoYXJ0cw==",
+	TrendingGaming: "4gIcGhpnYW1pbmdfY29ycHVzX21vc3RfcG9wdWxhcg==",
+	TrendingMovies: "4gIKGgh0cmFpbGVycw==",
+}
+
+//GetTrending : Retrieval the trending videos of the region (ISO 3166 code, empty for
+//youtube's guess) and category (TrendingNow, TrendingMusic, TrendingGaming or TrendingMovies).
+func (y *Youtube) GetTrending(region, category string) ([]VideoSummary, error) {
+	params := url.Values{}
+	if region != "" {
+		params.Set("gl", region)
+	}
+	if category != TrendingNow {
+		bp, ok := trendingParams[category]
+		if !ok {
+			return nil, fmt.Errorf("unknown trending category '%s'", category)
+		}
+		params.Set("bp", bp)
+	}
+	data, err := y.fetchInitialData("https://www.youtube.com/feed/trending?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	videos := parseVideoSummaries(data, "videoRenderer")
+	y.log(fmt.Sprintf("Found %d trending videos", len(videos)))
+	return videos, nil
+}
@@ -0,0 +1,22 @@
+package youtube
+
+import "testing"
+
+const trendingPage = `<script>var ytInitialData = {"contents":{"items":[
+{"videoRenderer":{"videoId":"rFejpH_tAHM","title":{"runs":[{"text":"Simplicity is Complicated"}]},"ownerText":{"runs":[{"text":"dotconferences"}]}}},
+{"videoRenderer":{"videoId":"FHpvI8oGsuQ","title":{"runs":[{"text":"Concurrency"}]}}}]}};</script>`
+
+func TestParseTrending(t *testing.T) {
+	data, err := extractInitialData([]byte(trendingPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	videos := parseVideoSummaries(data, "videoRenderer")
+	if len(videos) != 2 || videos[0].Author != "dotconferences" || videos[1].Title != "Concurrency" {
+		t.Errorf("Wrong trending videos: %+v", videos)
+	}
+
+	if _, err := NewYoutube(false).GetTrending("TW", "news"); err == nil {
+		t.Error("Unknown category should fail")
+	}
+}
@@ -0,0 +1,34 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+)
+
+//ManifestURLs : The adaptive streaming manifests of the video, empty when youtube
+//doesn't give one. Live streams usually only have HLS.
+type ManifestURLs struct {
+	//DASH is the MPD manifest url.
+	DASH string
+	//HLS is the m3u8 master playlist url.
+	HLS string
+}
+
+//GetStreamURL : The deciphered url of the format of the itag, to hand to another
+//player or downloader. It expires after a few hours.
+func (y *Youtube) GetStreamURL(itag int) (string, error) {
+	for _, f := range y.Formats {
+		if f.Itag == itag {
+			return y.requestURL(f)
+		}
+	}
+	return "", fmt.Errorf("no format with itag=%d", itag)
+}
+
+//GetManifestURLs : The DASH and HLS manifest urls of the decoded video.
+func (y *Youtube) GetManifestURLs() (ManifestURLs, error) {
+	if y.manifests.DASH == "" && y.manifests.HLS == "" {
+		return ManifestURLs{}, errors.New("no manifest url found in the video information")
+	}
+	return y.manifests, nil
+}
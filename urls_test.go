@@ -0,0 +1,64 @@
+package youtube
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetStreamAndManifestURLs(t *testing.T) {
+	v, _ := url.ParseQuery(videoInfoFixture(`{"streamingData":{"hlsManifestUrl":"https://manifest.googlevideo.com/hls"}}`))
+	v.Set("dashmpd", "https://manifest.googlevideo.com/dash")
+	y := NewYoutube(false)
+	y.videoInfo = v.Encode()
+	if err := y.parseVideoInfo(); err != nil {
+		t.Fatal(err)
+	}
+	m, err := y.GetManifestURLs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.HLS != "https://manifest.googlevideo.com/hls" || m.DASH != "https://manifest.googlevideo.com/dash" {
+		t.Errorf("Wrong manifests %+v", m)
+	}
+
+	target, err := y.GetStreamURL(22)
+	if err != nil || target != "https://r1.googlevideo.com/videoplayback?itag=22" {
+		t.Errorf("Wrong stream url %s, error %v", target, err)
+	}
+	if _, err := y.GetStreamURL(137); err == nil {
+		t.Error("Missing itag should fail")
+	}
+
+	y.videoInfo = videoInfoFixture("")
+	if err := y.parseVideoInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := y.GetManifestURLs(); err == nil {
+		t.Error("Manifests of the previous video are kept")
+	}
+}
+
+func TestDecodeManifestOnly(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//a DASH only video, the manifest comes from the player response
+		w.Write([]byte(url.Values{"status": {"ok"}, "title": {"DASH"},
+			"player_response": {`{"streamingData":{"dashManifestUrl":"https://manifest.googlevideo.com/dash"}}`}}.Encode()))
+	}))
+	defer ts.Close()
+	defer func(u string) { videoInfoURL = u }(videoInfoURL)
+	videoInfoURL = ts.URL + "/get_video_info"
+
+	y := NewYoutube(false)
+	if err := y.DecodeURL("https://www.youtube.com/watch?v=rFejpH_tAHM"); err != nil {
+		t.Fatal(err)
+	}
+	m, err := y.GetManifestURLs()
+	if err != nil || m.DASH != "https://manifest.googlevideo.com/dash" || m.HLS != "" {
+		t.Errorf("Wrong manifests %+v, error %v", m, err)
+	}
+	if len(y.Formats) != 0 || len(y.StreamList) != 0 {
+		t.Errorf("Unexpected streams %v", y.StreamList)
+	}
+}
@@ -0,0 +1,33 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+//ValidateFormat : Check with a small ranged request that the format url is
+//still downloadable, to catch expired or geo-blocked urls before downloading.
+func (y *Youtube) ValidateFormat(ctx context.Context, f Format) error {
+	target, err := y.requestURL(f)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", "bytes=0-1023")
+	resp, err := y.do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("format itag=%d answered status code %d", f.Itag, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateFormat(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/expired" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("Range") != "bytes=0-1023" {
+			t.Errorf("Wrong range: %s", r.Header.Get("Range"))
+		}
+		w.WriteHeader(http.StatusPartialContent)
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	if err := y.ValidateFormat(context.Background(), Format{Itag: 18, URL: ts.URL + "/ok"}); err != nil {
+		t.Error(err)
+	}
+	if err := y.ValidateFormat(context.Background(), Format{Itag: 18, URL: ts.URL + "/expired"}); err == nil {
+		t.Error("Expired url should fail")
+	}
+}
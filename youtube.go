@@ -1,19 +1,20 @@
 package youtube
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
-	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 //SetLogOutput :Set logger writer
@@ -24,47 +25,163 @@ func SetLogOutput(w io.Writer) {
 //NewYoutube :Initialize youtube package object
 func NewYoutube(debug bool) *Youtube {
 	return &Youtube{
-		client: &http.Client{
-			Transport: &http.Transport{
-				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-					conn, err := net.Dial(network, addr)
-					fmt.Printf("Remote IP: %s\n", conn.RemoteAddr())
-					return conn, err
-				},
-			},
-		},
-		DebugMode:       debug,
-		DownloadPercent: make(chan int64, 100),
+		Timeouts:         DefaultTimeouts,
+		RateLimitRetries: DefaultRateLimitRetries,
+		RateLimitBackoff: DefaultRateLimitBackoff,
+		LiveReconnect:    DefaultLiveReconnect,
+		DebugMode:        debug,
+		DownloadPercent:  make(chan int64, 100),
+		Bandwidth:        &Bandwidth{},
+		locks:            &sharedLocks{},
 	}
 }
 
 type stream map[string]string
 
 type Youtube struct {
-	client            *http.Client
-	DebugMode         bool
-	StreamList        []stream
-	VideoID           string
-	videoInfo         string
-	DownloadPercent   chan int64
-	contentLength     float64
-	totalWrittenBytes float64
-	downloadLevel     float64
+	client               *http.Client
+	locks                *sharedLocks
+	Timeouts             Timeouts
+	MinSpeed             int64
+	MinSpeedDuration     time.Duration
+	RateLimiter          *RateLimiter
+	StreamTransport      StreamTransport
+	ExternalDownloader   *ExternalDownloader
+	Cookies              []*http.Cookie
+	Bandwidth            *Bandwidth
+	MaxRedirects         int
+	ForceIPv4            bool
+	ForceIPv6            bool
+	SourceAddress        string
+	Proxy                string
+	Resolver             Resolver
+	ConnHooks            *ConnHooks
+	RecordTo             *Fixture
+	ReplayFrom           *Fixture
+	RateLimitRetries     int
+	RateLimitBackoff     time.Duration
+	Language             string
+	Region               string
+	RestrictedMode       bool
+	APIKey               string
+	quotaExceeded        bool
+	VideoInfoParams      url.Values
+	RewriteURL           func(string) (string, error)
+	FinalURL             string
+	DebugMode            bool
+	Logger               Logger
+	CacheDir             string
+	DisableCache         bool
+	StreamList           []stream
+	Formats              []Format
+	manifests            ManifestURLs
+	AllowedItags         []int
+	BlockedItags         []int
+	CipherExtractors     []CipherExtractor
+	Projection           string
+	FormatSelector       string
+	MaxFileSize          int64
+	formatLess           func(a, b Format) bool
+	FFmpegPath           string
+	RemuxTo              string
+	Encode               *EncodePreset
+	Normalize            *Loudness
+	PostProcessors       []PostProcessor
+	VideoID              string
+	PlaylistID           string
+	PreferPlaylist       bool
+	Playlist             *Playlist
+	OnPlaylistProgress   func(PlaylistProgress)
+	OutputTemplate       string
+	Details              *VideoDetails
+	Microformat          *Microformat
+	CaptionTracks        []CaptionTrack
+	TranslationLanguages []string
+	Subtitles            []string
+	AutoSubtitles        bool
+	EmbedSubtitles       bool
+	playability          playability
+	IsUpcoming           bool
+	ScheduledStart       time.Time
+	LiveDuration         time.Duration
+	LiveUntil            time.Time
+	LiveReconnect        time.Duration
+	OnLiveGap            func(LiveGap)
+	TrailerVideoID       string
+	DownloadTrailer      bool
+	Archive              *Archive
+	Storage              StorageWriter
+	OnExists             ExistsPolicy
+	SetMtime             bool
+	RestrictFileNames    bool
+	Checksum             bool
+	WriteNFO             bool
+	ExecAfterDownload    string
+	SplitChapters        bool
+	ChapterTemplate      string
+	Chapters             []Chapter
+	SilenceSplit         *SilenceDetect
+	result               *DownloadResult
+	localOutput          bool
+	noStreamFallback     bool
+	videoInfo            string
+	playerVersion        string
+	playerVersionAt      time.Time
+	decipher             func(s string) string
+	DownloadPercent      chan int64
+	DownloadID           string
+	OnProgress           func(ProgressEvent)
+	Tracer               Tracer
+	traceCtx             context.Context
+	ctx                  context.Context
+	contentLength        float64
+	expectedSize         int64
+	streamItag           int
+	totalWrittenBytes    float64
+	downloadLevel        float64
+	lastPercent          int64
+	phase                string
+	phaseIndex           int
+	phaseCount           int
 }
 
 //DecodeURL : Decode youtube URL to retrieval video information.
 func (y *Youtube) DecodeURL(url string) error {
+	span := y.startSpan("youtube.decode", "url", url)
+	err := y.decodeURL(url)
+	span.end(err)
+	return err
+}
+
+func (y *Youtube) decodeURL(url string) error {
+	y.Playlist = nil
 	err := y.findVideoID(url)
 	if err != nil {
 		return fmt.Errorf("findVideoID error=%s", err)
 	}
 
+	if y.PlaylistID != "" && (y.PreferPlaylist || y.VideoID == "") {
+		y.Playlist, err = y.GetPlaylist(y.PlaylistID)
+		if err != nil {
+			return fmt.Errorf("getPlaylist error=%s", err)
+		}
+		return nil
+	}
+
 	err = y.getVideoInfo()
 	if err != nil {
 		return fmt.Errorf("getVideoInfo error=%s", err)
 	}
 
 	err = y.parseVideoInfo()
+	if err != nil && y.DownloadTrailer && y.TrailerVideoID != "" {
+		y.log(fmt.Sprintf("Video %s is upcoming, decode its trailer %s", y.VideoID, y.TrailerVideoID))
+		y.VideoID = y.TrailerVideoID
+		if err = y.getVideoInfo(); err != nil {
+			return fmt.Errorf("getVideoInfo error=%s", err)
+		}
+		err = y.parseVideoInfo()
+	}
 	if err != nil {
 		return fmt.Errorf("parse video info failed, err=%s", err)
 	}
@@ -74,20 +191,192 @@ func (y *Youtube) DecodeURL(url string) error {
 
 //StartDownload : Starting download video to specific address.
 func (y *Youtube) StartDownload(destFile string) error {
+	_, err := y.Download(destFile)
+	return err
+}
+
+//Download : Download the video like StartDownload and report which streams were used.
+func (y *Youtube) Download(destFile string) (*DownloadResult, error) {
+	span := y.startSpan("youtube.start_download", "video_id", y.VideoID, "file", destFile)
+	result, err := y.startDownload(destFile)
+	span.end(err)
+	return result, err
+}
+
+func (y *Youtube) startDownload(destFile string) (*DownloadResult, error) {
+	if y.Archive != nil && y.Archive.Contains(y.VideoID) {
+		y.log(fmt.Sprintf("Skip video %s, already in the archive", y.VideoID))
+		return nil, ErrAlreadyDownloaded
+	}
+	if y.RestrictFileNames {
+		destFile = y.asciiDest(destFile)
+	}
+	destFile, skip, err := y.resolveDest(destFile)
+	if err != nil {
+		return nil, err
+	}
+	y.result = &DownloadResult{Path: destFile, Skipped: skip}
+	defer func() { y.result = nil }()
+	if skip {
+		y.result.Size = fileSize(destFile)
+		return y.result, nil
+	}
+	if y.Storage != nil && y.needsLocalFiles() {
+		err = y.downloadThroughLocal(destFile)
+	} else {
+		err = y.downloadAndProcess(destFile)
+	}
+	if err == nil && !y.EmbedSubtitles && len(y.Subtitles) > 0 {
+		err = y.downloadSubtitles(y.result.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if y.SetMtime && y.Storage == nil {
+		y.setMtime(y.result.Path)
+	}
+	if y.SplitChapters && y.Storage == nil {
+		if y.result.Chapters, y.result.ChapterFiles, err = y.splitChapters(y.result.Path); err != nil {
+			return nil, err
+		}
+	}
+	if y.WriteNFO && y.Storage == nil {
+		if err = y.writeNFO(y.result.Path); err != nil {
+			return nil, err
+		}
+	}
+	if y.Checksum && y.Storage == nil {
+		if y.result.SHA256, err = y.writeChecksum(y.result.Path); err != nil {
+			return nil, err
+		}
+	}
+	if y.Archive != nil {
+		if err = y.Archive.Add(y.VideoID); err != nil {
+			return nil, err
+		}
+	}
+	if y.ExecAfterDownload != "" {
+		if err = y.execAfterDownload(y.result); err != nil {
+			return y.result, fmt.Errorf("exec after download error=%s", err)
+		}
+	}
+	return y.result, nil
+}
+
+func (y *Youtube) downloadAndProcess(destFile string) error {
+	err := y.downloadSelectedOrList(destFile)
+	if err != nil {
+		return err
+	}
+	final, err := y.postProcess(destFile)
+	if err != nil {
+		return err
+	}
+	y.result.Path = final
+	if y.Storage == nil {
+		y.result.Size = fileSize(final)
+	}
+	return nil
+}
+
+func (y *Youtube) downloadSelectedOrList(destFile string) error {
+	if y.FormatSelector != "" || y.MaxFileSize > 0 {
+		return y.downloadSelected(destFile)
+	}
+	if y.onlyAdaptive() {
+		return y.downloadAdaptiveOnly(destFile)
+	}
+	return y.downloadStreamList(destFile)
+}
+
+//downloadThroughLocal runs the ffmpeg steps in a temporary directory and
+//writes the final file to the storage.
+func (y *Youtube) downloadThroughLocal(destFile string) error {
+	dir, err := ioutil.TempDir("", "youtube")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	y.localOutput = true
+	defer func() { y.localOutput = false }()
+
+	local := filepath.Join(dir, filepath.Base(destFile))
+	if err = y.downloadSelectedOrList(local); err != nil {
+		return err
+	}
+	final, err := y.postProcess(local)
+	if err != nil {
+		return err
+	}
+	y.result.Path = filepath.Join(filepath.Dir(destFile), filepath.Base(final))
+	y.result.Size = fileSize(final)
+	return y.copyToStorage(final, y.result.Path)
+}
+
+func (y *Youtube) downloadStreamList(destFile string) error {
 	//download highest resolution on [0]
-	err := errors.New("Empty stream list")
-	y.log(fmt.Sprintln("Download StreamList=", y.StreamList))
+	if len(y.StreamList) == 0 {
+		return errors.New("Empty stream list")
+	}
+	y.log(fmt.Sprintf("Download StreamList=%v", y.StreamList))
+	var errs []error
 	for _, v := range y.StreamList {
-		url := v["url"]
-		y.log(fmt.Sprintln("Download url=", url))
+		url, err := y.streamURL(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("itag=%s: %w", v["itag"], err))
+			continue
+		}
+		y.log(fmt.Sprintf("Download url=%s", url))
 
-		y.log(fmt.Sprintln("Download to file=", destFile))
+		y.log(fmt.Sprintf("Download to file=%s", destFile))
+		itag, _ := strconv.Atoi(v["itag"])
+		y.expectedSize = y.formatSize(itag)
+		y.streamItag = itag
 		err = y.videoDLWorker(destFile, url)
+		if err == ErrStreamForbidden {
+			//signed stream URLs expire, refresh them once and retry
+			y.log(fmt.Sprintf("Download forbidden, refreshing stream url for itag=%s", v["itag"]))
+			if url, err = y.refreshStreamURL(v["itag"]); err == nil {
+				err = y.videoDLWorker(destFile, url)
+			}
+		}
+		y.expectedSize = 0
+		y.streamItag = 0
 		if err == nil {
-			break
+			y.recordStream(itag)
+			return nil
+		}
+		var tooLarge *TooLargeError
+		if errors.As(err, &tooLarge) {
+			//the memory buffer is full of the failed stream
+			return err
+		}
+		errs = append(errs, fmt.Errorf("itag=%s: %w", v["itag"], err))
+		if y.noStreamFallback {
+			//the writer already got a part of the failed stream
+			return errors.Join(errs...)
 		}
 	}
-	return err
+	//every stream failed, keep all the reasons
+	return errors.Join(errs...)
+}
+
+//refreshStreamURL : Fetch the video information again and return the new url of the same stream.
+func (y *Youtube) refreshStreamURL(itag string) (string, error) {
+	//the signature may come from an outdated player
+	y.forgetPlayerVersion()
+	if err := y.getVideoInfo(); err != nil {
+		return "", fmt.Errorf("getVideoInfo error=%s", err)
+	}
+	if err := y.parseVideoInfo(); err != nil {
+		return "", fmt.Errorf("parse video info failed, err=%s", err)
+	}
+	for _, f := range y.Formats {
+		if strconv.Itoa(f.Itag) == itag {
+			return y.formatURL(f)
+		}
+	}
+	return "", fmt.Errorf("stream itag=%s not found after refresh", itag)
 }
 
 func (y *Youtube) parseVideoInfo() error {
@@ -102,28 +391,74 @@ func (y *Youtube) parseVideoInfo() error {
 		return err
 	}
 	if status[0] == "fail" {
-		reason, ok := answer["reason"]
-		if ok {
-			err = fmt.Errorf("'fail' response status found in the server's answer, reason: '%s'", reason[0])
-		} else {
-			err = errors.New(fmt.Sprint("'fail' response status found in the server's answer, no reason given"))
-		}
-		return err
+		//deleted and private videos, typed like the playability of the player response
+		y.Microformat = nil
+		y.playability = playability{status: "ERROR", reason: answer.Get("reason")}
+		return y.playabilityError()
 	}
 	if status[0] != "ok" {
 		err = fmt.Errorf("non-success response status found in the server's answer (status: '%s')", status)
 		return err
 	}
 
+	y.Microformat = nil
+	y.CaptionTracks, y.TranslationLanguages = nil, nil
+	y.IsUpcoming, y.ScheduledStart, y.TrailerVideoID = false, time.Time{}, ""
+	y.playability = playability{}
+	y.manifests = ManifestURLs{}
+	if pr, ok := answer["player_response"]; ok {
+		y.parsePlayerResponse(pr[0])
+	}
+	if y.manifests.DASH == "" {
+		y.manifests.DASH = answer.Get("dashmpd")
+	}
+	if y.manifests.HLS == "" {
+		y.manifests.HLS = answer.Get("hlsvp")
+	}
+
 	// read the streams map
 	streamMap, ok := answer["url_encoded_fmt_stream_map"]
-	if !ok {
+	if !ok && (y.IsUpcoming || y.TrailerVideoID != "") {
+		return y.upcomingError()
+	}
+	if !ok && y.playability.status != "" && y.playability.status != "OK" {
+		return y.playabilityError()
+	}
+	if !ok && answer.Get("adaptive_fmts") == "" && (y.manifests.HLS != "" || y.manifests.DASH != "") {
+		//live and DASH only videos, see RecordLive and GetManifestURLs
+		y.Formats, y.StreamList, y.Projection = nil, nil, ""
+		y.log("No stream map, only the manifests")
+		return nil
+	}
+	if !ok && answer.Get("adaptive_fmts") == "" {
 		err = errors.New(fmt.Sprint("no stream map found in the server's answer"))
 		return err
 	}
+	if !ok {
+		//only adaptive formats, see downloadAdaptiveOnly
+		streamMap = []string{""}
+	}
+
+	y.Formats = parseFormats(streamMap[0], true)
+	if adaptive, ok := answer["adaptive_fmts"]; ok {
+		y.Formats = append(y.Formats, parseFormats(adaptive[0], false)...)
+	}
+	if pr, ok := answer["player_response"]; ok {
+		applyStreamingInfo(y.Formats, pr[0])
+	}
+	y.Projection = ""
+	for _, f := range y.Formats {
+		if f.Projection != "" {
+			y.Projection = f.Projection
+			break
+		}
+	}
 
 	// read each stream
-	streamsList := strings.Split(streamMap[0], ",")
+	var streamsList []string
+	if streamMap[0] != "" {
+		streamsList = strings.Split(streamMap[0], ",")
+	}
 
 	var streams []stream
 	for streamPos, streamRaw := range streamsList {
@@ -149,6 +484,7 @@ func (y *Youtube) parseVideoInfo() error {
 		}
 
 		streams = append(streams, stream{
+			"itag":    streamQry.Get("itag"),
 			"quality": streamQry["quality"][0],
 			"type":    streamQry["type"][0],
 			"url":     streamQry["url"][0],
@@ -160,99 +496,240 @@ func (y *Youtube) parseVideoInfo() error {
 	}
 
 	y.StreamList = streams
+	y.filterItags()
+	y.sortFormats()
+	if len(y.StreamList) == 0 && len(y.adaptiveItags()) > 0 {
+		y.log(fmt.Sprintf("No muxed stream, only the adaptive itags %v", y.adaptiveItags()))
+		return nil
+	}
 	if len(y.StreamList) == 0 {
 		return errors.New(fmt.Sprint("no stream list found in the server's answer"))
 	}
 	return nil
 }
 
+var videoInfoURL = "http://youtube.com/get_video_info"
+
 func (y *Youtube) getVideoInfo() error {
-	url := "http://youtube.com/get_video_info?video_id=" + y.VideoID
-	y.log(fmt.Sprintf("url: %s", url))
-	resp, err := y.client.Get(url)
+	params := url.Values{"video_id": {y.VideoID}}
+	for k, v := range y.VideoInfoParams {
+		params[k] = v
+	}
+	span := y.startSpan("youtube.video_info", "video_id", y.VideoID)
+	body, err := y.fetch(videoInfoURL + "?" + params.Encode())
+	span.end(err)
 	if err != nil {
 		return err
 	}
+	y.videoInfo = string(body)
+	return nil
+}
+
+//fetch gets a youtube page or api answer, localized when Language is set.
+func (y *Youtube) fetch(url string) ([]byte, error) {
+	return y.request(context.Background(), "GET", url, nil)
+}
+
+//request sends the request within the Request timeout and returns the answer body.
+func (y *Youtube) request(ctx context.Context, method, url string, body []byte) ([]byte, error) {
+	if y.Language != "" {
+		url = setQueryParam(url, "hl", y.Language)
+	}
+	url = y.regionURL(url)
+	y.log(fmt.Sprintf("url: %s", url))
+	if y.Timeouts.Request > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, y.Timeouts.Request)
+		defer cancel()
+	}
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := y.do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		return err
+		return nil, fmt.Errorf("non 200 status code received: %d", resp.StatusCode)
 	}
-	body, err := ioutil.ReadAll(resp.Body)
+	return ioutil.ReadAll(resp.Body)
+}
+
+func setQueryParam(target, key, value string) string {
+	u, err := url.Parse(target)
 	if err != nil {
-		return err
+		return target
 	}
-	y.videoInfo = string(body)
-	return nil
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String()
 }
 
-func (y *Youtube) findVideoID(url string) error {
-	videoID := url
-	if strings.Contains(videoID, "youtu") || strings.ContainsAny(videoID, "\"?&/<%=") {
-		reList := []*regexp.Regexp{
-			regexp.MustCompile(`(?:v|embed|watch\?v)(?:=|/)([^"&?/=%]{11})`),
-			regexp.MustCompile(`(?:=|/)([^"&?/=%]{11})`),
-			regexp.MustCompile(`([^"&?/=%]{11})`),
-		}
-		for _, re := range reList {
-			if isMatch := re.MatchString(videoID); isMatch {
-				subs := re.FindStringSubmatch(videoID)
-				videoID = subs[1]
-			}
-		}
+func (y *Youtube) findVideoID(target string) error {
+	if ids := ExtractAllVideoIDs(target); len(ids) > 1 {
+		return fmt.Errorf("%d video urls found, decode them one by one from ExtractAllVideoIDs", len(ids))
 	}
-	y.log(fmt.Sprintf("Found video id: '%s'", videoID))
+	if urls := findURLs(target); len(urls) > 0 {
+		target = urls[0]
+	}
+	videoID, playlistID, err := parseVideoURL(target)
 	y.VideoID = videoID
-	if strings.ContainsAny(videoID, "?&/<%=") {
-		return errors.New("invalid characters in video id")
+	y.PlaylistID = playlistID
+	if err != nil {
+		return err
+	}
+	if videoID != "" {
+		y.log(fmt.Sprintf("Found video id: '%s'", videoID))
 	}
-	if len(videoID) < 10 {
-		return errors.New("the video id must be at least 10 characters long")
+	if playlistID != "" {
+		y.log(fmt.Sprintf("Found playlist id: '%s'", playlistID))
 	}
 	return nil
 }
 
 func (y *Youtube) Write(p []byte) (n int, err error) {
 	n = len(p)
+	before := int64(y.totalWrittenBytes)
 	y.totalWrittenBytes = y.totalWrittenBytes + float64(n)
+	if y.contentLength <= 0 {
+		//unknown size, report the bytes only
+		if before/unknownSizeStep != int64(y.totalWrittenBytes)/unknownSizeStep {
+			y.reportProgress()
+		}
+		return
+	}
 	currentPercent := ((y.totalWrittenBytes / y.contentLength) * 100)
-	if (y.downloadLevel <= currentPercent) && (y.downloadLevel < 100) {
+	for (y.downloadLevel <= currentPercent) && (y.downloadLevel < 100) {
 		y.downloadLevel++
-		y.DownloadPercent <- int64(y.downloadLevel)
+		y.reportProgress()
 	}
 	return
 }
 func (y *Youtube) videoDLWorker(destFile string, target string) error {
-	resp, err := y.client.Get(target)
+	span := y.startSpan("youtube.download", "file", destFile)
+	err := y.download(destFile, target, span)
+	span.end(err)
+	return err
+}
+
+func (y *Youtube) download(destFile string, target string, span *traceSpan) error {
+	target, err := y.rewriteURL(target)
 	if err != nil {
-		y.log(fmt.Sprintf("Http.Get\nerror: %s\ntarget: %s\n", err, target))
 		return err
 	}
-	defer resp.Body.Close()
-	y.contentLength = float64(resp.ContentLength)
-
-	if resp.StatusCode != 200 {
-		y.log(fmt.Sprintf("reading answer: non 200[code=%v] status code received: '%v'", resp.StatusCode, err))
-		return errors.New("non 200 status code received")
+	offset := y.resumeOffset(destFile)
+	parent := y.ctx
+	if parent == nil {
+		parent = context.Background()
 	}
-	err = os.MkdirAll(filepath.Dir(destFile), 0755)
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	if y.ExternalDownloader != nil {
+		return y.externalDownload(ctx, target, destFile, span)
+	}
+	st, err := y.streamTransport().OpenStream(ctx, target, offset)
 	if err != nil {
+		if err == ErrStreamForbidden {
+			y.log(fmt.Sprintf("reading answer: 403 status code received, target: %s", target))
+		}
 		return err
 	}
-	out, err := os.Create(destFile)
+	defer st.Body.Close()
+	y.FinalURL = st.URL
+	if u, err := url.Parse(st.URL); err == nil {
+		span.set("host", u.Host)
+	}
+	if y.FinalURL != target {
+		y.log(fmt.Sprintf("Download redirected to=%s", y.FinalURL))
+	}
+	body := io.Reader(st.Body)
+	if y.Timeouts.Idle > 0 {
+		body = newIdleReader(body, y.Timeouts.Idle, cancel)
+	}
+	if y.MinSpeed > 0 {
+		window := y.MinSpeedDuration
+		if window <= 0 {
+			window = DefaultMinSpeedDuration
+		}
+		body = newSpeedReader(body, y.MinSpeed, window, cancel)
+	}
+	if y.RateLimiter != nil {
+		body = &limitedReader{r: body, limiter: y.RateLimiter, ctx: ctx}
+	}
+	y.contentLength = float64(st.Length)
+	if st.Length < 0 && y.expectedSize > 0 {
+		//chunked answers of VBR formats, estimated from the bitrate and duration
+		y.log(fmt.Sprintf("No Content-Length, expect about %d bytes", y.expectedSize))
+		y.contentLength = float64(y.expectedSize)
+	}
+	y.totalWrittenBytes = 0
+	y.downloadLevel = 0
+	if y.phaseCount <= 1 {
+		y.lastPercent = 0
+	}
+
+	if offset > 0 && st.Offset == offset && st.Length == 0 {
+		y.log(fmt.Sprintf("%s is already complete", destFile))
+		y.clearResumeItag(destFile)
+		return nil
+	}
+	if st.Offset != offset {
+		//the transport couldn't resume, start over
+		offset = 0
+	} else if offset > 0 {
+		y.log(fmt.Sprintf("Resume download of %s at byte %d", destFile, offset))
+		if st.Length >= 0 {
+			y.contentLength += float64(offset)
+		}
+		y.totalWrittenBytes = float64(offset)
+	}
+	y.writeResumeItag(destFile)
+	out, err := y.createOutput(destFile, offset > 0)
 	if err != nil {
 		return err
 	}
 	mw := io.MultiWriter(out, y)
-	_, err = io.Copy(mw, resp.Body)
+	written, err := io.Copy(mw, body)
+	span.set("bytes", written)
+	if y.result != nil {
+		y.result.Size = offset + written
+	}
+	if cerr := closeOutput(out, err); err == nil {
+		err = cerr
+	}
 	if err != nil {
-		y.log(fmt.Sprintln("download video err=", err))
+		y.log(fmt.Sprintf("download video err=%s", err))
 		return err
 	}
+	y.clearResumeItag(destFile)
+	if st.Length < 0 {
+		//the size is known now, end the estimated or bytes only progress
+		y.contentLength = y.totalWrittenBytes
+		y.downloadLevel = 100
+		y.reportProgress()
+	}
 	return nil
 }
 
 func (y *Youtube) log(logText string) {
 	if y.DebugMode {
+		if y.DownloadID != "" {
+			logText = "[" + y.DownloadID + "] " + logText
+		}
+		if y.Logger != nil {
+			y.Logger.Log(logText)
+			return
+		}
 		log.Println(logText)
 	}
 }
@@ -1,8 +1,14 @@
 package youtube
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
 	"os/user"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -16,6 +22,10 @@ func TestMain(m *testing.M) {
 	//init download path
 	usr, _ := user.Current()
 	dfPath = filepath.Join(usr.HomeDir, "Movies", "test")
+	//keep the cached players out of the user cache
+	cache, _ := ioutil.TempDir("", "youtube-cache")
+	defer os.RemoveAll(cache)
+	os.Setenv("XDG_CACHE_HOME", cache)
 
 	m.Run()
 }
@@ -50,3 +60,112 @@ func TestParseVideo(t *testing.T) {
 		return
 	}
 }
+
+func TestFetchLanguage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Query().Get("hl")))
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.Language = "zh-TW"
+	body, err := y.fetch(ts.URL + "/get_video_info?video_id=rFejpH_tAHM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "zh-TW" {
+		t.Errorf("Wrong hl parameter sent: %s", body)
+	}
+}
+
+func TestProgressEvent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 1000))
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.DownloadID = "dl-1"
+	var last ProgressEvent
+	y.OnProgress = func(ev ProgressEvent) {
+		last = ev
+	}
+	if err := y.videoDLWorker(filepath.Join(t.TempDir(), "dl.mp4"), ts.URL); err != nil {
+		t.Fatal(err)
+	}
+	if last.DownloadID != "dl-1" || last.Percent != 100 || last.Bytes != 1000 {
+		t.Errorf("Wrong last progress event: %+v", last)
+	}
+}
+
+func TestVideoInfoParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.RawQuery))
+	}))
+	defer ts.Close()
+	defer func(u string) { videoInfoURL = u }(videoInfoURL)
+	videoInfoURL = ts.URL + "/get_video_info"
+
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.VideoInfoParams = url.Values{"el": {"embedded"}, "ps": {"default"}}
+	if err := y.getVideoInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if y.videoInfo != "el=embedded&ps=default&video_id=rFejpH_tAHM" {
+		t.Errorf("Wrong video info query: %s", y.videoInfo)
+	}
+}
+
+func TestStreamListErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	y := NewYoutube(false)
+	y.StreamList = []stream{{"itag": "22", "url": ts.URL}, {"itag": "18", "url": ts.URL}}
+	err := y.StartDownload(filepath.Join(t.TempDir(), "dl.mp4"))
+	if err == nil {
+		t.Fatal("Failed streams should return an error")
+	}
+	if msg := err.Error(); !strings.Contains(msg, "itag=22: ") || !strings.Contains(msg, "itag=18: ") {
+		t.Errorf("Every stream failure should be reported: %s", msg)
+	}
+}
+
+func TestRefreshForbiddenStream(t *testing.T) {
+	var refetches int
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/get_video_info":
+			refetches++
+			v, _ := url.ParseQuery(videoInfoFixture(""))
+			v.Set("url_encoded_fmt_stream_map", "itag=18&quality=medium&type=video%2Fmp4&url="+url.QueryEscape(ts.URL+"/video?sig=new"))
+			w.Write([]byte(v.Encode()))
+		case r.URL.Query().Get("sig") != "new":
+			//the signature of the first url expired
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.Write([]byte("video data"))
+		}
+	}))
+	defer ts.Close()
+	defer func(u string) { videoInfoURL = u }(videoInfoURL)
+	videoInfoURL = ts.URL + "/get_video_info"
+
+	dest := filepath.Join(t.TempDir(), "dl.mp4")
+	y := NewYoutube(false)
+	y.VideoID = "rFejpH_tAHM"
+	y.StreamList = []stream{{"itag": "18", "url": ts.URL + "/video?sig=old"}}
+	if err := y.StartDownload(dest); err != nil {
+		t.Fatal(err)
+	}
+	if refetches != 1 {
+		t.Errorf("Video information fetched %d times, want 1", refetches)
+	}
+	if b, _ := ioutil.ReadFile(dest); string(b) != "video data" {
+		t.Errorf("File contains %q", b)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2024, 1, 31, 23, 59, 30, 0, time.UTC) //a wednesday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/15 8-18 * * 1-5", time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)},
+		{"30 6 * * 0", time.Date(2024, 2, 4, 6, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * 6", time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 */2 * 1", time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		c, err := parseCron(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %s", tt.expr, err)
+			continue
+		}
+		if got := c.next(from); !got.Equal(tt.want) {
+			t.Errorf("%s: next is %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("%s: should fail", expr)
+		}
+	}
+}
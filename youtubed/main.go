@@ -0,0 +1,533 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	. "github.com/kkdai/youtube"
+)
+
+const usageString string = `Usage: youtubed [OPTION]
+Download server queueing the videos submitted to its REST API.
+Example: youtubed -addr :8080 -d /srv/videos
+  curl -d '{"url":"https://www.youtube.com/watch?v=rFejpH_tAHM"}' localhost:8080/downloads
+  curl localhost:8080/downloads?status=done
+  curl localhost:8080/downloads/1/events
+  curl -X DELETE localhost:8080/downloads/1`
+
+//Job status
+const (
+	statusQueued   = "queued"
+	statusRunning  = "running"
+	statusDone     = "done"
+	statusSkipped  = "skipped"
+	statusFailed   = "failed"
+	statusCanceled = "canceled"
+)
+
+//job is a submitted download, as answered by the api and saved in the queue file.
+type job struct {
+	ID       int    `json:"id"`
+	URL      string `json:"url"`
+	Priority int    `json:"priority"`
+	File     string `json:"file"`
+	Status   string `json:"status"`
+	Percent  int64  `json:"percent"`
+	Error    string `json:"error,omitempty"`
+	//Callback receives the job as json once it is done, skipped or failed.
+	Callback string `json:"callback,omitempty"`
+	//Trigger is the watch folder file the job comes from.
+	Trigger string `json:"trigger,omitempty"`
+
+	item *BatchItem
+}
+
+//finished reports whether the job won't change anymore.
+func (j *job) finished() bool {
+	return j.Status != statusQueued && j.Status != statusRunning
+}
+
+//server owns the jobs, persisted to the queue file on every status change.
+type server struct {
+	batch     *Batch
+	outputDir string
+	//outputTemplate names the files of the synced videos, see OutputPath.
+	outputTemplate string
+	queueFile      string
+	wake           chan struct{}
+	stopped        chan struct{}
+	//changed is closed and replaced whenever a job changes, see broadcast.
+	changed chan struct{}
+
+	mu       sync.Mutex
+	jobs     map[int]*job
+	items    map[string]*job
+	triggers map[string]*trigger
+	lastID   int
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Println(usageString)
+		flag.PrintDefaults()
+	}
+	usr, _ := user.Current()
+	var addr string
+	flag.StringVar(&addr, "addr", "localhost:8080", "The address of the REST API.")
+	var outputDir string
+	flag.StringVar(&outputDir, "d",
+		filepath.Join(usr.HomeDir, "Movies", "youtubedr"),
+		"The output directory.")
+	var queueFile string
+	flag.StringVar(&queueFile, "queue", "", "Keep the jobs in this file so pending downloads survive restarts, <dir>/youtubed-queue.json by default.")
+	var workers int
+	flag.IntVar(&workers, "workers", 2, "The number of parallel downloads.")
+	var archive string
+	flag.StringVar(&archive, "archive", "", "Record downloaded video ids in this file and skip the ones already in it.")
+	var watchDir string
+	flag.StringVar(&watchDir, "watch", "", "Download the links of the .url and .txt files dropped in this directory, then move them to its done or failed subdirectory.")
+	var syncFile string
+	flag.StringVar(&syncFile, "sync", "", "Download the new videos of the channels and playlists of this json file on their cron schedules, needs -archive.")
+	var outputTemplate string
+	flag.StringVar(&outputTemplate, "output-template", "", "Name the files of the synced videos with this template, e.g. \"{{.Channel}}/{{.PlaylistTitle}}/{{.Index}} - {{.Title}}.{{.Ext}}\".")
+	var shutdownTimeout time.Duration
+	flag.DurationVar(&shutdownTimeout, "shutdown-timeout", 30*time.Second, "On SIGTERM or SIGINT, wait this long for the running downloads, the interrupted ones resume on the next start.")
+	var debug bool
+	flag.BoolVar(&debug, "debug", false, "Log the download details.")
+	flag.Parse()
+	if queueFile == "" {
+		queueFile = filepath.Join(outputDir, "youtubed-queue.json")
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		log.Fatalln("err:", err)
+	}
+
+	s := &server{
+		batch:          NewBatch(workers, debug),
+		outputDir:      outputDir,
+		outputTemplate: outputTemplate,
+		queueFile:      queueFile,
+		wake:           make(chan struct{}, 1),
+		stopped:        make(chan struct{}),
+		changed:        make(chan struct{}),
+		jobs:           make(map[int]*job),
+		items:          make(map[string]*job),
+		triggers:       make(map[string]*trigger),
+	}
+	if archive != "" {
+		a, err := OpenArchive(archive)
+		if err != nil {
+			log.Fatalln("err:", err)
+		}
+		s.batch.Archive = a
+	}
+	if outputTemplate != "" {
+		if _, err := OutputPath(outputDir, outputTemplate, OutputFields{}); err != nil {
+			log.Fatalln("err:", err)
+		}
+	}
+	var sources []*syncSource
+	if syncFile != "" {
+		if s.batch.Archive == nil {
+			log.Fatalln("err: -sync needs -archive to know the downloaded videos")
+		}
+		var err error
+		if sources, err = loadSyncSources(syncFile); err != nil {
+			log.Fatalln("err:", err)
+		}
+	}
+	//interrupted downloads resume from their partial file
+	s.batch.NewYoutube = func() *Youtube {
+		y := NewYoutube(debug)
+		y.OnExists = ExistsResume
+		return y
+	}
+	s.batch.OnProgress = s.progress
+	s.batch.OnFinish = s.finish
+	if err := s.load(); err != nil {
+		log.Fatalln("err:", err)
+	}
+	go s.run()
+	if watchDir != "" {
+		go s.watch(watchDir, watchInterval)
+	}
+	for _, src := range sources {
+		go s.schedule(src)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/downloads", s.handleDownloads)
+	mux.HandleFunc("/downloads/", s.handleDownload)
+	srv := &http.Server{Addr: addr, Handler: mux}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
+	go func() {
+		<-stop
+		s.shutdown(srv, shutdownTimeout)
+	}()
+	log.Println("listen on", addr, "download to dir=", outputDir)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	<-s.stopped
+}
+
+//shutdown stops the api, then waits for the running downloads until the timeout
+//and saves the queue, the jobs not done yet stay queued for the next start.
+func (s *server) shutdown(srv *http.Server, timeout time.Duration) {
+	log.Println("Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	srv.Shutdown(ctx)
+	if err := s.batch.Shutdown(ctx); err != nil {
+		log.Println("Interrupted the running downloads:", err)
+	}
+	s.mu.Lock()
+	s.save()
+	s.mu.Unlock()
+	close(s.stopped)
+}
+
+//run processes the batch whenever jobs are submitted.
+func (s *server) run() {
+	for range s.wake {
+		if err := s.batch.Run(); err != nil {
+			log.Println("err:", err)
+		}
+	}
+}
+
+//addJob numbers and queues a new job, the caller holds the lock.
+func (s *server) addJob(j *job) *job {
+	s.lastID++
+	j.ID = s.lastID
+	j.Status = statusQueued
+	s.jobs[j.ID] = j
+	s.submit(j)
+	return j
+}
+
+//submit queues a job, the caller holds the lock.
+func (s *server) submit(j *job) {
+	item := s.batch.Add(j.URL, j.File, j.Priority)
+	s.items[item.ID()] = j
+	j.item = item
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+//load reads the queue file and queues again the jobs that didn't finish.
+func (s *server) load() error {
+	b, err := ioutil.ReadFile(s.queueFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var jobs []*job
+	if err := json.Unmarshal(b, &jobs); err != nil {
+		return fmt.Errorf("invalid queue file %s: %s", s.queueFile, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range jobs {
+		s.jobs[j.ID] = j
+		if j.ID > s.lastID {
+			s.lastID = j.ID
+		}
+		if j.Status == statusQueued || j.Status == statusRunning {
+			j.Status, j.Percent = statusQueued, 0
+			s.submit(j)
+		}
+		s.track(j)
+	}
+	s.finishLoadedTriggers()
+	log.Printf("Loaded %d jobs from %s", len(jobs), s.queueFile)
+	return nil
+}
+
+//save writes the jobs to the queue file, the caller holds the lock.
+func (s *server) save() {
+	b, err := json.MarshalIndent(s.list(""), "", "  ")
+	if err == nil {
+		tmp := s.queueFile + ".tmp"
+		if err = ioutil.WriteFile(tmp, b, 0644); err == nil {
+			err = os.Rename(tmp, s.queueFile)
+		}
+	}
+	if err != nil {
+		log.Println("err: save queue:", err)
+	}
+}
+
+//list returns the jobs with the status, all of them when empty, by id.
+func (s *server) list(status string) []*job {
+	jobs := []*job{}
+	for _, j := range s.jobs {
+		if status == "" || j.Status == status {
+			jobs = append(jobs, j)
+		}
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].ID < jobs[b].ID })
+	return jobs
+}
+
+func (s *server) progress(e ProgressEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.items[e.DownloadID]
+	if !ok {
+		return
+	}
+	started := j.Status == statusQueued
+	if started {
+		j.Status = statusRunning
+		s.save()
+	}
+	if started || j.Percent != e.Percent {
+		j.Percent = e.Percent
+		s.broadcast()
+	}
+}
+
+//broadcast wakes the progress streams, the caller holds the lock.
+func (s *server) broadcast() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *server) finish(item *BatchItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.items[item.ID()]
+	if !ok {
+		return
+	}
+	delete(s.items, item.ID())
+	switch {
+	case item.Err == ErrShutdown:
+		//downloaded again on the next start
+		j.Status, j.Percent = statusQueued, 0
+		s.save()
+		s.broadcast()
+		return
+	case item.Err == ErrCanceled:
+		j.Status = statusCanceled
+	case item.Err == ErrAlreadyDownloaded:
+		j.Status = statusSkipped
+	case item.Err != nil:
+		j.Status, j.Error = statusFailed, item.Err.Error()
+	default:
+		j.Status, j.Percent = statusDone, 100
+	}
+	s.ended(j)
+}
+
+//ended saves and announces a finished job, the caller holds the lock.
+func (s *server) ended(j *job) {
+	s.save()
+	s.broadcast()
+	s.triggerFinished(j)
+	if j.Callback != "" {
+		go notify(*j)
+	}
+}
+
+//callbackRetries is the number of attempts to deliver a callback.
+const callbackRetries = 3
+
+//callbackBackoff is the delay after the first failed attempt, multiplied by the attempt.
+var callbackBackoff = 5 * time.Second
+
+var callbackClient = &http.Client{Timeout: 30 * time.Second}
+
+//notify posts the finished job to its callback url, retrying on failures.
+func notify(j job) {
+	body, err := json.Marshal(j)
+	if err != nil {
+		log.Println("err: callback:", err)
+		return
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := callbackClient.Post(j.Callback, "application/json", bytes.NewReader(body))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return
+			}
+			err = fmt.Errorf("status code %d", resp.StatusCode)
+		}
+		if attempt == callbackRetries {
+			log.Printf("err: callback of job %d to %s failed: %s", j.ID, j.Callback, err)
+			return
+		}
+		time.Sleep(time.Duration(attempt) * callbackBackoff)
+	}
+}
+
+//handleDownloads submits a job with POST {"url": .., "priority": .., "callback": ..} and lists
+//the jobs with GET, filtered by the status parameter.
+func (s *server) handleDownloads(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		s.mu.Lock()
+		jobs := s.list(r.URL.Query().Get("status"))
+		writeJSON(w, http.StatusOK, jobs)
+		s.mu.Unlock()
+	case "POST":
+		var req struct {
+			URL      string `json:"url"`
+			Priority int    `json:"priority"`
+			Callback string `json:"callback"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		videoID, err := ExtractVideoID(req.URL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Callback != "" {
+			if u, err := url.Parse(req.Callback); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				http.Error(w, "invalid callback url", http.StatusBadRequest)
+				return
+			}
+		}
+		s.mu.Lock()
+		j := s.addJob(&job{
+			URL:      req.URL,
+			Priority: req.Priority,
+			File:     filepath.Join(s.outputDir, videoID+".mp4"),
+			Callback: req.Callback,
+		})
+		s.save()
+		writeJSON(w, http.StatusAccepted, j)
+		s.mu.Unlock()
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+//handleDownload answers the job of the id with GET /downloads/3, cancels it
+//with DELETE /downloads/3 and streams its changes with GET /downloads/3/events.
+func (s *server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/downloads/")
+	events := strings.HasSuffix(path, "/events")
+	id, err := strconv.Atoi(strings.TrimSuffix(path, "/events"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	switch {
+	case r.Method == "GET" && events:
+		s.streamJob(w, r, id)
+	case r.Method == "GET":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		j, ok := s.jobs[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, j)
+	case r.Method == "DELETE" && !events:
+		s.cancelJob(w, r, id)
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+//cancelJob removes a queued job or stops a running one, which is canceled once
+//its download is interrupted.
+func (s *server) cancelJob(w http.ResponseWriter, r *http.Request, id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if j.finished() || j.item == nil || !s.batch.Cancel(j.item) {
+		http.Error(w, "job is "+j.Status, http.StatusConflict)
+		return
+	}
+	if j.item.Err == ErrCanceled {
+		//a queued job, OnFinish won't see it
+		delete(s.items, j.item.ID())
+		j.Status = statusCanceled
+		s.ended(j)
+		writeJSON(w, http.StatusOK, j)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, j)
+}
+
+//streamJob writes the job as a json line each time its status or percent
+//changes, until it is finished or the client leaves.
+func (s *server) streamJob(w http.ResponseWriter, r *http.Request, id int) {
+	s.mu.Lock()
+	_, ok := s.jobs[id]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	var last job
+	for {
+		s.mu.Lock()
+		j := *s.jobs[id]
+		changed := s.changed
+		s.mu.Unlock()
+		if j.Status != last.Status || j.Percent != last.Percent {
+			if err := enc.Encode(j); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			last = j
+		}
+		if j.finished() {
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/kkdai/youtube"
+)
+
+func TestNotifyRetry(t *testing.T) {
+	defer func(d time.Duration) { callbackBackoff = d }(callbackBackoff)
+	callbackBackoff = time.Millisecond
+
+	var attempts int
+	var got job
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Wrong content type %s", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	j := job{ID: 3, URL: "https://youtu.be/rFejpH_tAHM", File: "rFejpH_tAHM.mp4", Status: statusDone, Percent: 100, Callback: ts.URL}
+	notify(j)
+	if attempts != 2 {
+		t.Errorf("Callback attempted %d times, want 2", attempts)
+	}
+	if got != j {
+		t.Errorf("Wrong callback payload %+v", got)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	s := testServer(t)
+	s.mu.Lock()
+	j := s.addJob(&job{URL: "https://youtu.be/rFejpH_tAHM", File: "rFejpH_tAHM.mp4"})
+	s.mu.Unlock()
+	ts := httptest.NewServer(http.HandlerFunc(s.handleDownload))
+	defer ts.Close()
+
+	req, _ := http.NewRequest("DELETE", ts.URL+"/downloads/1", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || j.Status != statusCanceled || s.batch.Pending() != 0 {
+		t.Errorf("Got %d, job %s, %d pending", resp.StatusCode, j.Status, s.batch.Pending())
+	}
+	if resp, err = http.DefaultClient.Do(req); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("Canceling again got %d", resp.StatusCode)
+		}
+	}
+}
+
+func TestStreamJob(t *testing.T) {
+	s := testServer(t)
+	s.mu.Lock()
+	j := s.addJob(&job{URL: "https://youtu.be/rFejpH_tAHM", File: "rFejpH_tAHM.mp4"})
+	s.mu.Unlock()
+	ts := httptest.NewServer(http.HandlerFunc(s.handleDownload))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/downloads/1/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	dec := json.NewDecoder(resp.Body)
+	next := func() job {
+		t.Helper()
+		var got job
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	if got := next(); got.Status != statusQueued {
+		t.Errorf("First event %+v", got)
+	}
+	s.progress(ProgressEvent{DownloadID: j.item.ID(), Percent: 50})
+	if got := next(); got.Status != statusRunning || got.Percent != 50 {
+		t.Errorf("Progress event %+v", got)
+	}
+	s.finish(j.item)
+	if got := next(); got.Status != statusDone || got.Percent != 100 {
+		t.Errorf("Last event %+v", got)
+	}
+	if err := dec.Decode(&job{}); err != io.EOF {
+		t.Errorf("The stream should end with the job, err=%v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/kkdai/youtube"
+)
+
+//syncSource is a channel or playlist downloaded on a cron schedule, in the
+//sync file:
+//
+//	[{"channel": "@GoogleDevelopers", "cron": "0 */6 * * *"},
+//	 {"playlist": "PL59FEE129ADFF2B12", "cron": "30 2 * * 1"}]
+type syncSource struct {
+	//Channel is a channel id (UC...) or handle (@name), its uploads are synced.
+	Channel string `json:"channel"`
+	//Playlist is a playlist id or url.
+	Playlist string `json:"playlist"`
+	Cron     string `json:"cron"`
+	//Priority of the queued downloads.
+	Priority int `json:"priority"`
+
+	schedule *cronSchedule
+}
+
+func (src *syncSource) String() string {
+	if src.Channel != "" {
+		return "channel " + src.Channel
+	}
+	return "playlist " + src.Playlist
+}
+
+//loadSyncSources reads and checks the sync file.
+func loadSyncSources(path string) ([]*syncSource, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sources []*syncSource
+	if err := json.Unmarshal(b, &sources); err != nil {
+		return nil, fmt.Errorf("invalid sync file %s: %s", path, err)
+	}
+	for i, src := range sources {
+		if (src.Channel == "") == (src.Playlist == "") {
+			return nil, fmt.Errorf("sync source %d needs either a channel or a playlist", i+1)
+		}
+		if src.schedule, err = parseCron(src.Cron); err != nil {
+			return nil, fmt.Errorf("sync source %d: %s", i+1, err)
+		}
+	}
+	return sources, nil
+}
+
+//schedule syncs the source at each time of its cron expression.
+func (s *server) schedule(src *syncSource) {
+	for {
+		next := src.schedule.next(time.Now())
+		if next.IsZero() {
+			log.Printf("err: %s is never scheduled", src)
+			return
+		}
+		time.Sleep(time.Until(next))
+		if err := s.sync(src); err != nil {
+			log.Printf("err: sync %s: %s", src, err)
+		}
+	}
+}
+
+//sync queues the videos of the source which are neither in the archive nor
+//already queued. The playlist is read with the downloader of the jobs.
+func (s *server) sync(src *syncSource) error {
+	y := s.batch.NewYoutube()
+	playlistID, err := src.playlistID(y)
+	if err != nil {
+		return err
+	}
+	p, err := y.GetPlaylist(playlistID)
+	if err != nil {
+		return err
+	}
+	var channel *ChannelInfo
+	if s.outputTemplate != "" && src.Channel != "" {
+		if channel, err = y.GetChannelInfo(src.Channel); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queued := make(map[string]bool)
+	used := make(map[string]bool)
+	for _, j := range s.jobs {
+		if j.Status == statusQueued || j.Status == statusRunning {
+			queued[j.URL] = true
+			used[j.File] = true
+		}
+	}
+	var added int
+	for _, v := range p.Videos {
+		watchURL := "https://www.youtube.com/watch?v=" + v.ID
+		if s.batch.Archive.Contains(v.ID) || queued[watchURL] {
+			continue
+		}
+		file := filepath.Join(s.outputDir, v.ID+".mp4")
+		if s.outputTemplate != "" {
+			f := OutputFields{ID: v.ID, Title: v.Title, PlaylistID: p.ID, PlaylistTitle: p.Title, Index: v.Index, Ext: "mp4"}
+			if channel != nil {
+				f.Channel, f.ChannelID = channel.Title, channel.ID
+			}
+			if file, err = OutputPath(s.outputDir, s.outputTemplate, f); err != nil {
+				return err
+			}
+		}
+		s.addJob(&job{
+			URL:      watchURL,
+			Priority: src.Priority,
+			File:     UniquePath(file, v.ID, used),
+		})
+		added++
+	}
+	if added > 0 {
+		s.save()
+	}
+	log.Printf("Sync %s: %d new videos of %d", src, added, len(p.Videos))
+	return nil
+}
+
+//playlistID returns the playlist to sync, the uploads playlist of a channel.
+func (src *syncSource) playlistID(y *Youtube) (string, error) {
+	if src.Playlist != "" {
+		if u, err := url.Parse(src.Playlist); err == nil && u.Query().Get("list") != "" {
+			return u.Query().Get("list"), nil
+		}
+		return src.Playlist, nil
+	}
+	channelID := src.Channel
+	if strings.HasPrefix(channelID, "@") {
+		info, err := y.GetChannelInfo(channelID)
+		if err != nil {
+			return "", err
+		}
+		channelID = info.ID
+	}
+	if !strings.HasPrefix(channelID, "UC") {
+		return "", errors.New("invalid channel id " + channelID)
+	}
+	//the uploads playlist is the channel id with the UU prefix
+	return "UU" + strings.TrimPrefix(channelID, "UC"), nil
+}
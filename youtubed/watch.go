@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/kkdai/youtube"
+)
+
+//watchInterval is the delay between two scans of the watch folder.
+const watchInterval = 5 * time.Second
+
+//Watch folder subdirectories
+const (
+	watchProcessing = "processing"
+	watchDone       = "done"
+	watchFailed     = "failed"
+)
+
+//trigger counts the unfinished jobs of a watch folder file.
+type trigger struct {
+	pending int
+	failed  bool
+}
+
+//watch polls the directory for dropped files. A picked file is moved to the
+//processing subdirectory until all its downloads are finished.
+func (s *server) watch(dir string, interval time.Duration) {
+	for _, sub := range []string{watchProcessing, watchDone, watchFailed} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			log.Println("err: watch:", err)
+			return
+		}
+	}
+	log.Println("watch dir=", dir)
+	var last map[string]fileState
+	for {
+		last = s.scan(dir, last)
+		time.Sleep(interval)
+	}
+}
+
+//fileState is the size and modification time of a dropped file at a scan.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+//scan picks up the files unchanged since the last scan, a file still being
+//copied waits for the next one. It returns the states of the files left.
+func (s *server) scan(dir string, last map[string]fileState) map[string]fileState {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		log.Println("err: watch:", err)
+	}
+	states := make(map[string]fileState)
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if !e.Mode().IsRegular() || (ext != ".url" && ext != ".txt") {
+			continue
+		}
+		st := fileState{size: e.Size(), modTime: e.ModTime()}
+		if prev, ok := last[e.Name()]; ok && prev.size == st.size && prev.modTime.Equal(st.modTime) {
+			s.pickup(dir, e.Name())
+			continue
+		}
+		states[e.Name()] = st
+	}
+	return states
+}
+
+//pickup queues the links of the file, an invalid file goes to failed at once.
+func (s *server) pickup(dir, name string) {
+	links, err := readLinks(filepath.Join(dir, name))
+	if err == nil && len(links) == 0 {
+		err = fmt.Errorf("no link found")
+	}
+	if err != nil {
+		log.Printf("err: watch file %s: %s", name, err)
+		moveTrigger(filepath.Join(dir, name), watchFailed)
+		return
+	}
+	processing := filepath.Join(dir, watchProcessing, name)
+	if err := os.Rename(filepath.Join(dir, name), processing); err != nil {
+		//not read again at every scan
+		log.Printf("err: watch file %s: %s", name, err)
+		moveTrigger(filepath.Join(dir, name), watchFailed)
+		return
+	}
+	log.Printf("Watch file %s: %d links", name, len(links))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, l := range links {
+		videoID, _ := ExtractVideoID(l)
+		s.track(s.addJob(&job{
+			URL:     l,
+			File:    filepath.Join(s.outputDir, videoID+".mp4"),
+			Trigger: processing,
+		}))
+	}
+	s.save()
+}
+
+//readLinks reads the urls of a .txt file, one per line with # comments, or the
+//URL= line of a .url internet shortcut.
+func readLinks(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	shortcut := strings.EqualFold(filepath.Ext(path), ".url")
+	var links []string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if shortcut {
+			if !strings.HasPrefix(strings.ToUpper(line), "URL=") {
+				continue
+			}
+			line = line[len("URL="):]
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := ExtractVideoID(line); err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		links = append(links, line)
+	}
+	return links, scanner.Err()
+}
+
+//track counts the job in its trigger file, the caller holds the lock.
+func (s *server) track(j *job) {
+	if j.Trigger == "" {
+		return
+	}
+	t, ok := s.triggers[j.Trigger]
+	if !ok {
+		t = &trigger{}
+		s.triggers[j.Trigger] = t
+	}
+	switch j.Status {
+	case statusQueued, statusRunning:
+		t.pending++
+	case statusFailed, statusCanceled:
+		t.failed = true
+	}
+}
+
+//finishLoadedTriggers moves the trigger files whose jobs all finished before
+//a restart, the caller holds the lock.
+func (s *server) finishLoadedTriggers() {
+	for path, t := range s.triggers {
+		if t.pending == 0 {
+			s.finishTrigger(path, t)
+		}
+	}
+}
+
+//triggerFinished updates the trigger file of a finished job, the caller holds the lock.
+func (s *server) triggerFinished(j *job) {
+	t, ok := s.triggers[j.Trigger]
+	if !ok {
+		return
+	}
+	t.pending--
+	t.failed = t.failed || j.Status == statusFailed || j.Status == statusCanceled
+	if t.pending == 0 {
+		s.finishTrigger(j.Trigger, t)
+	}
+}
+
+func (s *server) finishTrigger(path string, t *trigger) {
+	delete(s.triggers, path)
+	sub := watchDone
+	if t.failed {
+		sub = watchFailed
+	}
+	moveTrigger(path, sub)
+}
+
+//moveTrigger moves the file to the subdirectory of the watch folder.
+func moveTrigger(path, sub string) {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == watchProcessing {
+		dir = filepath.Dir(dir)
+	}
+	if err := os.Rename(path, filepath.Join(dir, sub, filepath.Base(path))); err != nil && !os.IsNotExist(err) {
+		log.Println("err: watch:", err)
+	}
+}
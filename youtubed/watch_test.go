@@ -0,0 +1,115 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "github.com/kkdai/youtube"
+)
+
+func testServer(t *testing.T) *server {
+	dir := t.TempDir()
+	return &server{
+		batch:     NewBatch(1, false),
+		outputDir: dir,
+		queueFile: filepath.Join(dir, "youtubed-queue.json"),
+		wake:      make(chan struct{}, 1),
+		changed:   make(chan struct{}),
+		jobs:      make(map[int]*job),
+		items:     make(map[string]*job),
+		triggers:  make(map[string]*trigger),
+	}
+}
+
+func TestReadLinks(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		err     bool
+	}{
+		{"list.txt", "# my videos\nhttps://www.youtube.com/watch?v=rFejpH_tAHM\n\n  https://youtu.be/rFejpH_tAHM  \n", []string{"https://www.youtube.com/watch?v=rFejpH_tAHM", "https://youtu.be/rFejpH_tAHM"}, false},
+		{"link.url", "[InternetShortcut]\r\nURL=https://www.youtube.com/watch?v=rFejpH_tAHM\r\n", []string{"https://www.youtube.com/watch?v=rFejpH_tAHM"}, false},
+		{"bad.txt", "https://www.youtube.com/watch?v=rFejpH_tAHM\nnot a link\n", nil, true},
+		{"empty.txt", "# nothing\n", nil, false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := ioutil.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		links, err := readLinks(path)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: got error %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(links, tt.want) {
+			t.Errorf("%s: got links %q, want %q", tt.name, links, tt.want)
+		}
+	}
+}
+
+func TestWatchTriggers(t *testing.T) {
+	s := testServer(t)
+	dir := t.TempDir()
+	for _, sub := range []string{watchProcessing, watchDone, watchFailed} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		"ok.txt":   "https://www.youtube.com/watch?v=rFejpH_tAHM\nhttps://youtu.be/rFejpH_tAHM\n",
+		"fail.url": "URL=https://www.youtube.com/watch?v=rFejpH_tAHM\n",
+		"bad.txt":  "not a link\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	//a file is picked up once it is unchanged between two scans
+	last := s.scan(dir, nil)
+	if len(last) != 3 || len(s.jobs) != 0 {
+		t.Fatalf("First scan picked files up: %v, %d jobs", last, len(s.jobs))
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "ok.txt"), []byte(files["ok.txt"]+"# still copying\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	last = s.scan(dir, last)
+	if len(last) != 1 || len(s.jobs) != 1 {
+		t.Fatalf("Second scan left %v, %d jobs", last, len(s.jobs))
+	}
+	s.scan(dir, last)
+	if len(s.jobs) != 3 {
+		t.Fatalf("Got %d jobs, want 3", len(s.jobs))
+	}
+	assertIn := func(sub, name string) {
+		t.Helper()
+		if _, err := os.Stat(filepath.Join(dir, sub, name)); err != nil {
+			t.Errorf("%s is not in %s: %s", name, sub, err)
+		}
+	}
+	assertIn(watchFailed, "bad.txt")
+	assertIn(watchProcessing, "ok.txt")
+	assertIn(watchProcessing, "fail.url")
+
+	//the files leave processing when all their jobs are finished
+	s.mu.Lock()
+	for _, j := range s.list("") {
+		j.Status = statusDone
+		if filepath.Base(j.Trigger) == "fail.url" {
+			j.Status = statusFailed
+		}
+		s.triggerFinished(j)
+	}
+	s.mu.Unlock()
+	assertIn(watchDone, "ok.txt")
+	assertIn(watchFailed, "fail.url")
+	if len(s.triggers) != 0 {
+		t.Errorf("Triggers left: %v", s.triggers)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//defaultConfigPath is $XDG_CONFIG_HOME/youtubedr/config.toml, ~/.config by default,
+//or the config directory of the OS.
+func defaultConfigPath(home string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "youtubedr", "config.toml")
+}
+
+//loadConfig sets the flags not given on the command line from the config file,
+//a flat toml file of flag names and values:
+//
+//	f = "bestvideo[height<=1080]+bestaudio/best"
+//	r = 1000000
+//	archive = "/home/gopher/.youtubedr-archive"
+//	proxy = "socks5://127.0.0.1:1080"
+//
+//A missing file is ignored unless it was asked with -config.
+func loadConfig(fs *flag.FlagSet, path string, required bool) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) && !required {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("%s line %d: expected name = value", path, n)
+		}
+		name := strings.TrimSpace(kv[0])
+		value, err := configValue(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return fmt.Errorf("%s line %d: %s", path, n, err)
+		}
+		if fs.Lookup(name) == nil || name == "config" {
+			return fmt.Errorf("%s line %d: unknown option '%s'", path, n, name)
+		}
+		if set[name] {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("%s line %d: %s", path, n, err)
+		}
+	}
+	return scanner.Err()
+}
+
+//configValue reads a quoted string, or a bare number or boolean, followed by
+//an optional # comment.
+func configValue(raw string) (string, error) {
+	var value, rest string
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		quoted, err := strconv.QuotedPrefix(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", raw)
+		}
+		value, _ = strconv.Unquote(quoted)
+		rest = raw[len(quoted):]
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", raw)
+		}
+		value, rest = raw[1:end+1], raw[end+2:]
+	default:
+		if i := strings.Index(raw, "#"); i >= 0 {
+			raw = strings.TrimSpace(raw[:i])
+		}
+		return raw, nil
+	}
+	if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("unexpected %s after the string", rest)
+	}
+	return value, nil
+}
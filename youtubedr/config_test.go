@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigValue(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+		err  bool
+	}{
+		{`"best"`, "best", false},
+		{`"best" # note`, "best", false},
+		{`"a \"b\" # c"`, `a "b" # c`, false},
+		{`'C:\videos' # windows`, `C:\videos`, false},
+		{`1000000 # bytes per second`, "1000000", false},
+		{`true`, "true", false},
+		{`"best`, "", true},
+		{`'best`, "", true},
+		{`"best" worst`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := configValue(tt.raw)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("%s: got %q, err=%v", tt.raw, got, err)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	newFlags := func() (*flag.FlagSet, *string, *int) {
+		fs := flag.NewFlagSet("youtubedr", flag.ContinueOnError)
+		return fs, fs.String("f", "", ""), fs.Int("r", 0, "")
+	}
+
+	config := "# youtubedr\nf = \"bestvideo+bestaudio\" # note\n\nr = 1000 # rate\n"
+	if err := ioutil.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs, format, rate := newFlags()
+	if err := fs.Parse([]string{"-r", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfig(fs, path, true); err != nil {
+		t.Fatal(err)
+	}
+	if *format != "bestvideo+bestaudio" || *rate != 5 {
+		t.Errorf("Got f=%q r=%d, the command line should win", *format, *rate)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("f = \"best\"\nquality = \"hd\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs, _, _ = newFlags()
+	if err := loadConfig(fs, path, true); err == nil || !strings.Contains(err.Error(), "line 2: unknown option 'quality'") {
+		t.Errorf("Unknown option error: %v", err)
+	}
+
+	fs, _, _ = newFlags()
+	missing := filepath.Join(t.TempDir(), "config.toml")
+	if err := loadConfig(fs, missing, false); err != nil {
+		t.Errorf("A missing default config should be ignored: %s", err)
+	}
+	if err := loadConfig(fs, missing, true); err == nil {
+		t.Error("A missing -config file should fail")
+	}
+}
+
+func TestLoadConfigProxy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	config := "proxy = \"socks5://127.0.0.1:1080\" # tor\n"
+	if err := ioutil.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("youtubedr", flag.ContinueOnError)
+	proxy := fs.String("proxy", "", "")
+	if err := loadConfig(fs, path, true); err != nil {
+		t.Fatal(err)
+	}
+	if *proxy != "socks5://127.0.0.1:1080" {
+		t.Errorf("Wrong proxy %q", *proxy)
+	}
+}
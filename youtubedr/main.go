@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
 	"os/user"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	. "github.com/kkdai/youtube"
 )
@@ -13,7 +19,9 @@ import (
 const usageString string = `Usage: youtubedr [OPTION] [URL]
 Download a video from youtube.
 Example: youtubedr -o "Campaign Diary".mp4 https://www.youtube.com/watch\?v\=XbNghLqsVwU
-`
+Example: youtubedr -playlist https://www.youtube.com/watch\?v\=XbNghLqsVwU\&list\=PL59FEE129ADFF2B12
+Example: youtubedr -a list.txt
+Example: youtubedr doctor, to diagnose connection issues for a bug report`
 
 func main() {
 	flag.Usage = func() {
@@ -27,16 +35,314 @@ func main() {
 	flag.StringVar(&outputDir, "d",
 		filepath.Join(usr.HomeDir, "Movies", "youtubedr"),
 		"The output directory.")
+	var playlist bool
+	flag.BoolVar(&playlist, "playlist", false, "Download the whole playlist when the URL has a list.")
+	var outputTemplate string
+	flag.StringVar(&outputTemplate, "output-template", "", "With -playlist, name the files with this template, e.g. \"{{.Channel}}/{{.PlaylistTitle}}/{{.Index}} - {{.Title}}.{{.Ext}}\".")
+	var language string
+	flag.StringVar(&language, "hl", "", "The language of the video metadata, e.g. en, zh-TW.")
+	var region string
+	flag.StringVar(&region, "region", "", "The country of the results, e.g. US or FR, like the location setting of the account.")
+	var restricted bool
+	flag.BoolVar(&restricted, "restricted-mode", false, "Turn the Restricted Mode on, it is off even when the browser cookies have it.")
+	var live bool
+	flag.BoolVar(&live, "live", false, "Record the live stream from its HLS manifest, as MPEG-TS.")
+	var liveDuration time.Duration
+	flag.DurationVar(&liveDuration, "live-duration", 0, "With -live, stop recording after this duration, e.g. 90m.")
+	var liveUntil string
+	flag.StringVar(&liveUntil, "live-until", "", "With -live, stop recording at this local time, e.g. 21:30, or RFC 3339 time.")
+	var liveReconnect time.Duration
+	flag.DurationVar(&liveReconnect, "live-reconnect", DefaultLiveReconnect, "With -live, keep reconnecting this long after losing the connection.")
+	var archive string
+	flag.StringVar(&archive, "archive", "", "Record downloaded video ids in this file and skip the ones already in it.")
+	var format string
+	flag.StringVar(&format, "f", "", "The format selector, e.g. \"bestvideo[height<=1080]+bestaudio/best\", merging needs ffmpeg.")
+	var maxSize int64
+	flag.Int64Var(&maxSize, "max-filesize", 0, "Pick the best formats whose total size fits under this number of bytes.")
+	var remux string
+	flag.StringVar(&remux, "remux", "", "Remux the download to this container without re-encoding, e.g. mp4 or mkv, needs ffmpeg.")
+	var encode string
+	flag.StringVar(&encode, "encode", "", "Re-encode the download with a preset: baseline720 or main1080, needs ffmpeg.")
+	var loudnorm string
+	flag.StringVar(&loudnorm, "loudnorm", "", "Normalize the loudness: podcast (-16 LUFS), music (-14 LUFS) or a target in LUFS, needs ffmpeg.")
+	var forceIPv4, forceIPv6 bool
+	flag.BoolVar(&forceIPv4, "4", false, "Make all connections via IPv4.")
+	flag.BoolVar(&forceIPv6, "6", false, "Make all connections via IPv6.")
+	var sourceAddress string
+	flag.StringVar(&sourceAddress, "source-address", "", "Client-side IP address or interface name to bind to.")
+	var proxy string
+	flag.StringVar(&proxy, "proxy", "", "Use this http, https or socks5 proxy, e.g. socks5://127.0.0.1:1080, the one of HTTPS_PROXY by default.")
+	var doh string
+	flag.StringVar(&doh, "doh", "", "Resolve host names with this DNS-over-HTTPS server, e.g. https://cloudflare-dns.com/dns-query.")
+	var apiKey string
+	flag.StringVar(&apiKey, "api-key", "", "Get the playlists and channels with the YouTube Data API v3 and this key, scraping when the quota is exceeded.")
+	var subs string
+	flag.StringVar(&subs, "sub", "", "Download the subtitles of these comma separated languages, e.g. en,pt-BR.")
+	var autoSubs, embedSubs bool
+	flag.BoolVar(&autoSubs, "auto-sub", false, "Use the auto generated subtitles when a language has no uploaded ones.")
+	flag.BoolVar(&embedSubs, "embed-subs", false, "Embed the subtitles in an mkv file, needs ffmpeg.")
+	var batchFile string
+	flag.StringVar(&batchFile, "a", "", "Download the urls or video ids listed in this file, one per line.")
+	var exists string
+	flag.StringVar(&exists, "exists", "overwrite", "When the output file exists: overwrite, skip, error, number or resume.")
+	var mtime bool
+	flag.BoolVar(&mtime, "mtime", false, "Set the modification time of the file to the video upload date.")
+	var restrict bool
+	flag.BoolVar(&restrict, "restrict-filenames", false, "Keep only ASCII characters and no spaces in the output file names.")
+	var checksum bool
+	flag.BoolVar(&checksum, "sha256", false, "Write a .sha256 file next to each download.")
+	var manifest string
+	flag.StringVar(&manifest, "manifest", "", "With -a, write the sha256 of all the downloaded files to this file.")
+	var splitChapters bool
+	flag.BoolVar(&splitChapters, "split-chapters", false, "Also copy each chapter of the video to its own file, needs ffmpeg.")
+	var chapterTemplate string
+	flag.StringVar(&chapterTemplate, "chapter-template", DefaultChapterTemplate, "The names of the chapter files, with {name}, {index}, {chapter} and {ext}.")
+	var splitSilence bool
+	flag.BoolVar(&splitSilence, "split-silence", false, "With -split-chapters, split at the silences when the video has no chapters.")
+	var nfo bool
+	flag.BoolVar(&nfo, "nfo", false, "Write a .nfo file next to each download for Jellyfin, Emby, Kodi or Plex.")
+	var execCmd string
+	flag.StringVar(&execCmd, "exec", "", "Run this command after each download, with {path}, {dir}, {file}, {id}, {url}, {title}, {author}, {channel_id}, {size} and {sha256}, split on spaces without a shell.")
+	var budget int64
+	flag.Int64Var(&budget, "budget", 0, "Stop downloading after receiving this number of bytes.")
+	var external string
+	flag.StringVar(&external, "external-downloader", "", "Download the streams with this command, \"aria2c\" or a template like \"curl -o {output} {url}\".")
+	var browser string
+	flag.StringVar(&browser, "cookies-from-browser", "", "Use the youtube cookies of this browser: chrome, chromium, brave, edge or firefox, needs sqlite3.")
+	var limitRate int64
+	flag.Int64Var(&limitRate, "r", 0, "Maximum download rate in bytes per second.")
+	var record, replay string
+	flag.StringVar(&record, "record", "", "Save the sanitized http exchanges to this file, to attach to a bug report.")
+	flag.StringVar(&replay, "replay", "", "Answer the http requests from a file saved with -record, without network.")
+	var config string
+	flag.StringVar(&config, "config", "", "Read the default options from this file, "+defaultConfigPath(usr.HomeDir)+" by default.")
 	flag.Parse()
+	configPath := config
+	if configPath == "" {
+		configPath = defaultConfigPath(usr.HomeDir)
+	}
+	if err := loadConfig(flag.CommandLine, configPath, config != ""); err != nil {
+		fmt.Println("err:", err)
+		return
+	}
 	log.Println(flag.Args())
 	log.Println("download to dir=", outputDir)
-	y := NewYoutube(true)
+	var replayFixture, recordFixture *Fixture
+	if replay != "" {
+		f, err := LoadFixture(replay)
+		if err != nil {
+			fmt.Println("err:", err)
+			return
+		}
+		replayFixture = f
+	}
+	if record != "" {
+		recordFixture = &Fixture{}
+		defer func() {
+			if err := recordFixture.Save(record); err != nil {
+				fmt.Println("err:", err)
+			}
+		}()
+	}
+	bandwidth := NewBandwidth(budget)
+	defer func() { log.Println("received bytes=", bandwidth.Used()) }()
+	var cookies []*http.Cookie
+	if browser != "" {
+		var skipped []error
+		var err error
+		if cookies, skipped, err = ImportBrowserCookies(browser); err != nil {
+			fmt.Println("err:", err)
+			return
+		}
+		for _, e := range skipped {
+			log.Println("Skip", e)
+		}
+	}
+	var downloader *ExternalDownloader
+	if external != "" {
+		var err error
+		if downloader, err = ParseExternalDownloader(external); err != nil {
+			fmt.Println("err:", err)
+			return
+		}
+	}
+	var limiter *RateLimiter
+	if limitRate > 0 {
+		limiter = NewRateLimiter(limitRate)
+	}
+	var resolver Resolver
+	if doh != "" {
+		resolver = NewDoHResolver(doh)
+	}
+	var preset *EncodePreset
+	switch encode {
+	case "":
+	case "baseline720":
+		preset = &PresetBaseline720
+	case "main1080":
+		preset = &PresetMain1080
+	default:
+		fmt.Println("err: unknown encode preset", encode)
+		return
+	}
+	var normalize *Loudness
+	switch loudnorm {
+	case "":
+	case "podcast":
+		normalize = &LoudnessPodcast
+	case "music":
+		normalize = &LoudnessMusic
+	default:
+		target, err := strconv.ParseFloat(loudnorm, 64)
+		if err != nil {
+			fmt.Println("err: unknown loudnorm target", loudnorm)
+			return
+		}
+		l := LoudnessPodcast
+		l.TargetLUFS = target
+		normalize = &l
+	}
+	var onExists ExistsPolicy
+	switch exists {
+	case "overwrite":
+	case "skip":
+		onExists = ExistsSkip
+	case "error":
+		onExists = ExistsError
+	case "number":
+		onExists = ExistsNumber
+	case "resume":
+		onExists = ExistsResume
+	default:
+		fmt.Println("err: unknown exists policy", exists)
+		return
+	}
+	var archiveFile *Archive
+	if archive != "" {
+		a, err := OpenArchive(archive)
+		if err != nil {
+			fmt.Println("err:", err)
+			return
+		}
+		archiveFile = a
+	}
+	//newYoutube configures a downloader from the options, each batch item gets
+	//its own, sharing only the budget, rate limit, archive and fixtures.
+	newYoutube := func() *Youtube {
+		y := NewYoutube(true)
+		y.PreferPlaylist = playlist
+		y.OutputTemplate = outputTemplate
+		y.Language = language
+		y.Region = region
+		y.RestrictedMode = restricted
+		y.APIKey = apiKey
+		y.ForceIPv4 = forceIPv4
+		y.ForceIPv6 = forceIPv6
+		y.SourceAddress = sourceAddress
+		y.Proxy = proxy
+		y.ReplayFrom = replayFixture
+		y.RecordTo = recordFixture
+		y.Bandwidth = bandwidth
+		y.Cookies = cookies
+		y.ExternalDownloader = downloader
+		y.RateLimiter = limiter
+		y.Resolver = resolver
+		y.FormatSelector = format
+		y.MaxFileSize = maxSize
+		if subs != "" {
+			y.Subtitles = strings.Split(subs, ",")
+		}
+		y.AutoSubtitles = autoSubs
+		y.EmbedSubtitles = embedSubs
+		y.RemuxTo = remux
+		y.SetMtime = mtime
+		y.RestrictFileNames = restrict
+		y.Checksum = checksum
+		y.WriteNFO = nfo
+		y.ExecAfterDownload = execCmd
+		y.SplitChapters = splitChapters
+		y.ChapterTemplate = chapterTemplate
+		if splitSilence {
+			y.SilenceSplit = &DefaultSilenceDetect
+		}
+		y.Encode = preset
+		y.Normalize = normalize
+		y.OnExists = onExists
+		y.Archive = archiveFile
+		return y
+	}
+	y := newYoutube()
+	if flag.Arg(0) == "doctor" {
+		report := y.Doctor()
+		fmt.Print(report)
+		if !report.OK() {
+			os.Exit(1)
+		}
+		return
+	}
+	if batchFile != "" {
+		b := NewBatch(1, true)
+		b.ManifestFile = manifest
+		b.NewYoutube = newYoutube
+		if _, err := b.LoadBatchFile(batchFile, outputDir); err != nil {
+			fmt.Println("err:", err)
+			return
+		}
+		if err := b.Run(); err != nil {
+			fmt.Println("err:", err)
+		}
+		return
+	}
 	arg := flag.Arg(0)
 	if err := y.DecodeURL(arg); err != nil {
 		fmt.Println("err:", err)
 		return
 	}
-	if err := y.StartDownload(filepath.Join(outputDir, outputFile)); err != nil {
+	if live {
+		y.LiveDuration = liveDuration
+		y.LiveReconnect = liveReconnect
+		y.OnLiveGap = func(g LiveGap) { log.Println("missed live segments", g.From, "to", g.To) }
+		if liveUntil != "" {
+			until, err := parseUntil(liveUntil, time.Now())
+			if err != nil {
+				fmt.Println("err:", err)
+				return
+			}
+			y.LiveUntil = until
+		}
+		rec, err := y.RecordLive(context.Background(), filepath.Join(outputDir, outputFile))
+		if err != nil {
+			fmt.Println("err:", err)
+		}
+		if rec != nil {
+			log.Println("recorded segments=", rec.Segments, "duration=", rec.Duration)
+		}
+		return
+	}
+	if y.Playlist != nil {
+		if err := y.StartPlaylistDownload(outputDir); err != nil {
+			fmt.Println("err:", err)
+		}
+		return
+	}
+	if err := y.StartDownload(filepath.Join(outputDir, outputFile)); err != nil && err != ErrAlreadyDownloaded {
 		fmt.Println("err:", err)
 	}
 }
+
+//parseUntil reads a RFC 3339 time, or a local clock time like 21:30 which is
+//tomorrow when it has already passed today.
+func parseUntil(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	clock, err := time.ParseInLocation("15:04", s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s', use 21:30 or 2006-01-02T21:30:00Z", s)
+	}
+	t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUntil(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2024, 1, 31, 20, 0, 0, 0, loc)
+	tests := []struct {
+		s    string
+		want time.Time
+	}{
+		{"21:30", time.Date(2024, 1, 31, 21, 30, 0, 0, loc)},
+		{"20:00", time.Date(2024, 2, 1, 20, 0, 0, 0, loc)},
+		{"06:15", time.Date(2024, 2, 1, 6, 15, 0, 0, loc)},
+		{"2024-02-03T10:00:00Z", time.Date(2024, 2, 3, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseUntil(tt.s, now)
+		if err != nil {
+			t.Errorf("%s: %s", tt.s, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: got %s, want %s", tt.s, got, tt.want)
+		}
+	}
+
+	for _, s := range []string{"", "25:00", "9pm", "2024-02-03"} {
+		if _, err := parseUntil(s, now); err == nil {
+			t.Errorf("%s: should fail", s)
+		}
+	}
+}